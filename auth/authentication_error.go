@@ -0,0 +1,15 @@
+package auth
+
+// AuthenticationError is returned when one of the authenticators failed
+// to provide credentials for the request.
+type AuthenticationError struct {
+	message string
+}
+
+func (e AuthenticationError) Error() string {
+	return e.message
+}
+
+func NewAuthenticationError(message string) *AuthenticationError {
+	return &AuthenticationError{message}
+}
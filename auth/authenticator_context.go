@@ -1,5 +1,12 @@
 package auth
 
+import (
+	"context"
+
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/utils"
+)
+
 // AuthenticatorContext provides information required for authenticating requests.
 type AuthenticatorContext struct {
 	Type     string                 `json:"type"`
@@ -7,6 +14,10 @@ type AuthenticatorContext struct {
 	Debug    bool                   `json:"debug"`
 	Insecure bool                   `json:"insecure"`
 	Request  AuthenticatorRequest   `json:"request"`
+
+	RetryPolicy utils.RetryPolicy          `json:"-"`
+	Context     context.Context            `json:"-"`
+	Network     network.HttpClientSettings `json:"-"`
 }
 
 func NewAuthenticatorContext(
@@ -14,6 +25,9 @@ func NewAuthenticatorContext(
 	config map[string]interface{},
 	debug bool,
 	insecure bool,
-	request AuthenticatorRequest) *AuthenticatorContext {
-	return &AuthenticatorContext{authType, config, debug, insecure, request}
+	request AuthenticatorRequest,
+	retryPolicy utils.RetryPolicy,
+	context context.Context,
+	settings network.HttpClientSettings) *AuthenticatorContext {
+	return &AuthenticatorContext{authType, config, debug, insecure, request, retryPolicy, context, settings}
 }
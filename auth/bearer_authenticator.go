@@ -46,7 +46,9 @@ func (a BearerAuthenticator) Auth(ctx AuthenticatorContext) AuthenticatorResult
 		config.ClientId,
 		config.ClientSecret,
 		config.Properties,
-		ctx.Insecure)
+		ctx.Network,
+		ctx.RetryPolicy,
+		ctx.Context)
 	tokenResponse, err := identityClient.GetToken(*tokenRequest)
 	if err != nil {
 		return *AuthenticatorError(fmt.Errorf("Error retrieving bearer token: %w", err))
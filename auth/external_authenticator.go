@@ -32,7 +32,7 @@ func (a ExternalAuthenticator) Auth(ctx AuthenticatorContext) AuthenticatorResul
 	if err != nil {
 		return *AuthenticatorError(fmt.Errorf("Error invoking external authenticator '%s' using path '%s': %w", a.config.Name, a.config.Path, err))
 	}
-	cmd := exec.Command(path)
+	cmd := exec.CommandContext(ctx.Context, path)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -1,15 +1,17 @@
 package auth
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/UiPath/uipathcli/cache"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/utils"
 )
 
@@ -19,6 +21,10 @@ type identityClient struct {
 
 const TokenRoute = "/connect/token"
 
+// tokenLocks serializes token requests with the same cache key so that
+// concurrently executed operations, e.g. in batch mode, share a single token.
+var tokenLocks sync.Map
+
 func (c identityClient) GetToken(tokenRequest tokenRequest) (*tokenResponse, error) {
 	form := url.Values{}
 	form.Add("grant_type", tokenRequest.GrantType)
@@ -32,42 +38,54 @@ func (c identityClient) GetToken(tokenRequest tokenRequest) (*tokenResponse, err
 		form.Add(key, value)
 	}
 
+	// The token cache is bypassed while recording or replaying so that the
+	// token request is part of the recording and replayed tokens, which are
+	// redacted, never end up in the cache.
+	useCache := tokenRequest.Network.Cassette == nil
 	cacheKey := c.cacheKey(tokenRequest)
-	token, expiresIn := c.cache.Get(cacheKey)
-	if token != "" {
-		return newTokenResponse(token, expiresIn), nil
+	if useCache {
+		lock, _ := tokenLocks.LoadOrStore(cacheKey, &sync.Mutex{})
+		lock.(*sync.Mutex).Lock()
+		defer lock.(*sync.Mutex).Unlock()
+
+		token, expiresIn := c.cache.Get(cacheKey)
+		if token != "" {
+			return newTokenResponse(token, expiresIn), nil
+		}
 	}
 
-	response, err := c.retrieveToken(tokenRequest.BaseUri, form, tokenRequest.Insecure)
+	response, err := c.retrieveToken(tokenRequest.Context, tokenRequest.BaseUri, form, tokenRequest.Network, tokenRequest.RetryPolicy)
 	if err != nil {
 		return nil, err
 	}
-	c.cache.Set(cacheKey, response.AccessToken, response.ExpiresIn)
+	if useCache {
+		c.cache.Set(cacheKey, response.AccessToken, response.ExpiresIn)
+	}
 	return response, nil
 }
 
-func (c identityClient) retrieveToken(baseUri url.URL, form url.Values, insecure bool) (*tokenResponse, error) {
+func (c identityClient) retrieveToken(ctx context.Context, baseUri url.URL, form url.Values, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy) (*tokenResponse, error) {
 	var response *tokenResponse
-	err := utils.Retry(func() error {
+	err := utils.RetryWithAttempt(ctx, retryPolicy, func(attempt int) error {
 		var err error
-		response, err = c.send(baseUri, form, insecure)
+		response, err = c.send(utils.WithRetryAttempt(ctx, attempt), baseUri, form, settings, retryPolicy)
 		return err
 	})
 	return response, err
 }
 
-func (c identityClient) send(baseUri url.URL, form url.Values, insecure bool) (*tokenResponse, error) {
+func (c identityClient) send(ctx context.Context, baseUri url.URL, form url.Values, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy) (*tokenResponse, error) {
 	uri := baseUri.JoinPath(TokenRoute)
-	request, err := http.NewRequest("POST", uri.String(), strings.NewReader(form.Encode()))
+	request, err := http.NewRequestWithContext(ctx, "POST", uri.String(), strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("Error preparing request: %w", err)
 	}
 	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint // This is user configurable and disabled by default
+	client, err := network.NewHttpClient(settings)
+	if err != nil {
+		return nil, err
 	}
-	client := http.Client{Transport: transport}
 	response, err := client.Do(request)
 	if err != nil {
 		return nil, utils.Retryable(fmt.Errorf("Error sending request: %w", err))
@@ -77,8 +95,9 @@ func (c identityClient) send(baseUri url.URL, form url.Values, insecure bool) (*
 	if err != nil {
 		return nil, utils.Retryable(fmt.Errorf("Error reading response: %w", err))
 	}
-	if response.StatusCode >= 500 {
-		return nil, utils.Retryable(fmt.Errorf("Token service returned status code '%v' and body '%v'", response.StatusCode, string(bytes)))
+	if retryPolicy.IsRetryableStatusCode(response.StatusCode) {
+		retryAfter := utils.ParseRetryAfter(response.Header.Get("Retry-After"))
+		return nil, utils.RetryableAfter(fmt.Errorf("Token service returned status code '%v' and body '%v'", response.StatusCode, string(bytes)), retryAfter)
 	}
 	if response.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("Token service returned status code '%v' and body '%v'", response.StatusCode, string(bytes))
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/UiPath/uipathcli/cache"
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/utils"
 )
 
 // The OAuthAuthenticator triggers the oauth authorization code flow with proof key for code exchange (PKCE).
@@ -46,7 +48,7 @@ func (a OAuthAuthenticator) Auth(ctx AuthenticatorContext) AuthenticatorResult {
 			return *AuthenticatorError(fmt.Errorf("Invalid identity url '%s': %w", ctx.Request.URL, err))
 		}
 	}
-	token, err := a.retrieveToken(*identityBaseUri, *config, ctx.Insecure)
+	token, err := a.retrieveToken(ctx.Context, *identityBaseUri, *config, ctx.Network, ctx.RetryPolicy)
 	if err != nil {
 		return *AuthenticatorError(fmt.Errorf("Error retrieving access token: %w", err))
 	}
@@ -54,17 +56,18 @@ func (a OAuthAuthenticator) Auth(ctx AuthenticatorContext) AuthenticatorResult {
 	return *AuthenticatorSuccess(ctx.Request.Header, ctx.Config)
 }
 
-func (a OAuthAuthenticator) retrieveToken(identityBaseUri url.URL, config oauthAuthenticatorConfig, insecure bool) (string, error) {
+func (a OAuthAuthenticator) retrieveToken(ctx context.Context, identityBaseUri url.URL, config oauthAuthenticatorConfig, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy) (string, error) {
 	cacheKey := fmt.Sprintf("oauthtoken|%s|%s|%s|%s", identityBaseUri.Scheme, identityBaseUri.Hostname(), config.ClientId, config.Scopes)
+	useCache := settings.Cassette == nil
 	token, _ := a.cache.Get(cacheKey)
-	if token != "" {
+	if useCache && token != "" {
 		return token, nil
 	}
 
 	secretGenerator := newSecretGenerator()
 	codeVerifier, codeChallenge := secretGenerator.GeneratePkce()
 	state := secretGenerator.GenerateState()
-	code, err := a.login(identityBaseUri, config, state, codeChallenge)
+	code, err := a.login(ctx, identityBaseUri, config, state, codeChallenge)
 	if err != nil {
 		return "", err
 	}
@@ -76,17 +79,21 @@ func (a OAuthAuthenticator) retrieveToken(identityBaseUri url.URL, config oauthA
 		code,
 		codeVerifier,
 		config.RedirectUrl.String(),
-		insecure)
+		settings,
+		retryPolicy,
+		ctx)
 	tokenResponse, err := identityClient.GetToken(*tokenRequest)
 	if err != nil {
 		return "", err
 	}
-	a.cache.Set(cacheKey, tokenResponse.AccessToken, tokenResponse.ExpiresIn)
+	if useCache {
+		a.cache.Set(cacheKey, tokenResponse.AccessToken, tokenResponse.ExpiresIn)
+	}
 	return tokenResponse.AccessToken, nil
 }
 
-func (a OAuthAuthenticator) login(identityBaseUri url.URL, config oauthAuthenticatorConfig, state string, codeChallenge string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+func (a OAuthAuthenticator) login(parentCtx context.Context, identityBaseUri url.URL, config oauthAuthenticatorConfig, state string, codeChallenge string) (string, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 120*time.Second)
 	defer cancel()
 
 	var code string
@@ -146,6 +153,9 @@ func (a OAuthAuthenticator) login(identityBaseUri url.URL, config oauthAuthentic
 
 	<-ctx.Done()
 
+	if parentCtx.Err() != nil {
+		return "", parentCtx.Err()
+	}
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		return "", fmt.Errorf("OAuth Login expired")
 	}
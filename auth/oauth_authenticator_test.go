@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -15,6 +16,8 @@ import (
 	"testing"
 
 	"github.com/UiPath/uipathcli/cache"
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/utils"
 )
 
 func TestOAuthAuthenticatorNotEnabled(t *testing.T) {
@@ -23,7 +26,7 @@ func TestOAuthAuthenticatorNotEnabled(t *testing.T) {
 		"scopes":   "OR.Users",
 	}
 	request := NewAuthenticatorRequest("http:/localhost", map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	authenticator := NewOAuthAuthenticator(cache.NewFileCache(), nil)
 	result := authenticator.Auth(*context)
@@ -44,7 +47,7 @@ func TestOAuthAuthenticatorPreservesExistingHeaders(t *testing.T) {
 		"my-header": "my-value",
 	}
 	request := NewAuthenticatorRequest("http:/localhost", headers)
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	authenticator := NewOAuthAuthenticator(cache.NewFileCache(), nil)
 	result := authenticator.Auth(*context)
@@ -63,7 +66,7 @@ func TestOAuthAuthenticatorInvalidRequestUrl(t *testing.T) {
 		"scopes":      "OR.Users",
 	}
 	request := NewAuthenticatorRequest("://invalid", map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	authenticator := NewOAuthAuthenticator(cache.NewFileCache(), nil)
 	result := authenticator.Auth(*context)
@@ -79,7 +82,7 @@ func TestOAuthAuthenticatorInvalidIdentityUrl(t *testing.T) {
 		"scopes":      "OR.Users",
 	}
 	request := NewAuthenticatorRequest("INVALID-URL", map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	authenticator := NewOAuthAuthenticator(cache.NewFileCache(), nil)
 	result := authenticator.Auth(*context)
@@ -95,7 +98,7 @@ func TestOAuthAuthenticatorInvalidConfig(t *testing.T) {
 		"scopes":      "OR.Users",
 	}
 	request := NewAuthenticatorRequest("http:/localhost", map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	authenticator := NewOAuthAuthenticator(cache.NewFileCache(), nil)
 	result := authenticator.Auth(*context)
@@ -155,7 +158,7 @@ func TestOAuthFlowWithCustomIdentityUri(t *testing.T) {
 		"uri":         identityUrl.String() + "/identity_",
 	}
 	request := NewAuthenticatorRequest("no-url", map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 
 	loginUrl, resultChannel := callAuthenticator(*context)
 	performLogin(loginUrl, t)
@@ -290,7 +293,7 @@ func createAuthContext(identityUrl url.URL) AuthenticatorContext {
 		"scopes":      "OR.Users",
 	}
 	request := NewAuthenticatorRequest(fmt.Sprintf("%s://%s", identityUrl.Scheme, identityUrl.Host), map[string]string{})
-	context := NewAuthenticatorContext("login", config, false, false, *request)
+	context := NewAuthenticatorContext("login", config, false, false, *request, *utils.DefaultRetryPolicy(), context.Background(), network.HttpClientSettings{})
 	return *context
 }
 
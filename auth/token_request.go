@@ -1,6 +1,12 @@
 package auth
 
-import "net/url"
+import (
+	"context"
+	"net/url"
+
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/utils"
+)
 
 type tokenRequest struct {
 	BaseUri      url.URL
@@ -12,13 +18,15 @@ type tokenRequest struct {
 	CodeVerifier string
 	RedirectUri  string
 	Properties   map[string]string
-	Insecure     bool
+	Network      network.HttpClientSettings
+	RetryPolicy  utils.RetryPolicy
+	Context      context.Context
 }
 
-func newTokenRequest(baseUri url.URL, grantType string, scopes string, clientId string, clientSecret string, properties map[string]string, insecure bool) *tokenRequest {
-	return &tokenRequest{baseUri, grantType, scopes, clientId, clientSecret, "", "", "", properties, insecure}
+func newTokenRequest(baseUri url.URL, grantType string, scopes string, clientId string, clientSecret string, properties map[string]string, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy, context context.Context) *tokenRequest {
+	return &tokenRequest{baseUri, grantType, scopes, clientId, clientSecret, "", "", "", properties, settings, retryPolicy, context}
 }
 
-func newAuthorizationCodeTokenRequest(baseUri url.URL, clientId string, code string, codeVerifier string, redirectUrl string, insecure bool) *tokenRequest {
-	return &tokenRequest{baseUri, "authorization_code", "", clientId, "", code, codeVerifier, redirectUrl, map[string]string{}, insecure}
+func newAuthorizationCodeTokenRequest(baseUri url.URL, clientId string, code string, codeVerifier string, redirectUrl string, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy, context context.Context) *tokenRequest {
+	return &tokenRequest{baseUri, "authorization_code", "", clientId, "", code, codeVerifier, redirectUrl, map[string]string{}, settings, retryPolicy, context}
 }
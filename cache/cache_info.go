@@ -0,0 +1,12 @@
+package cache
+
+// CacheInfo provides statistics about the stored responses.
+type CacheInfo struct {
+	Directory string `json:"directory"`
+	Entries   int    `json:"entries"`
+	Size      int64  `json:"size"`
+}
+
+func NewCacheInfo(directory string, entries int, size int64) *CacheInfo {
+	return &CacheInfo{directory, entries, size}
+}
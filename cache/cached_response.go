@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachedResponse is an HTTP response which is stored in the response cache.
+//
+// The response is fresh until the Expires timestamp and can be served without
+// contacting the service. Afterwards, it needs to be revalidated using the
+// ETag and Last-Modified validators.
+//
+// Responses with a Vary header keep the values of the listed request headers
+// and are only served for requests with the same header values.
+type CachedResponse struct {
+	StatusCode int                 `json:"statusCode"`
+	Status     string              `json:"status"`
+	Header     map[string][]string `json:"header"`
+	Body       []byte              `json:"body"`
+	Expires    int64               `json:"expires"`
+	VaryHeader map[string]string   `json:"varyHeader,omitempty"`
+}
+
+func (r CachedResponse) ETag() string {
+	return http.Header(r.Header).Get("ETag")
+}
+
+func (r CachedResponse) LastModified() string {
+	return http.Header(r.Header).Get("Last-Modified")
+}
+
+// Matches returns true when the request headers listed in the Vary header of
+// the response have the same values as the ones of the cached request.
+func (r CachedResponse) Matches(requestHeader http.Header) bool {
+	for _, name := range varyHeaderNames(r.Header) {
+		if name == "*" || requestHeader.Get(name) != r.VaryHeader[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Fresh returns true when the response can be served without revalidation.
+func (r CachedResponse) Fresh(now time.Time) bool {
+	return r.Expires > now.Unix()
+}
+
+// Revalidated updates the cached response with the headers of a
+// '304 Not Modified' response.
+func (r CachedResponse) Revalidated(header http.Header, now time.Time) CachedResponse {
+	merged := http.Header(r.Header).Clone()
+	for name, values := range header {
+		merged[name] = values
+	}
+	r.Header = merged
+	r.Expires = expires(merged, now)
+	return r
+}
+
+// NewCachedResponse creates the cache entry for the response. It returns nil
+// when the response must not be stored, e.g. because of Cache-Control: no-store
+// or because it can neither be served fresh nor revalidated.
+//
+// The request header provides the values for the headers listed in the Vary
+// response header. Responses with 'Vary: *' are never stored.
+func NewCachedResponse(statusCode int, status string, header http.Header, body []byte, requestHeader http.Header, now time.Time) *CachedResponse {
+	if statusCode != http.StatusOK {
+		return nil
+	}
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, noStore := directives["no-store"]; noStore {
+		return nil
+	}
+	varyHeader := map[string]string{}
+	for _, name := range varyHeaderNames(header) {
+		if name == "*" {
+			return nil
+		}
+		varyHeader[name] = requestHeader.Get(name)
+	}
+	response := CachedResponse{
+		StatusCode: statusCode,
+		Status:     status,
+		Header:     header.Clone(),
+		Body:       body,
+		Expires:    expires(header, now),
+		VaryHeader: varyHeader,
+	}
+	if !response.Fresh(now) && response.ETag() == "" && response.LastModified() == "" {
+		return nil
+	}
+	return &response
+}
+
+func expires(header http.Header, now time.Time) int64 {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, noCache := directives["no-cache"]; noCache {
+		return 0
+	}
+	maxAge, err := strconv.Atoi(directives["max-age"])
+	if err != nil || maxAge <= 0 {
+		return 0
+	}
+	return now.Unix() + int64(maxAge)
+}
+
+func varyHeaderNames(header http.Header) []string {
+	names := []string{}
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := map[string]string{}
+	for _, directive := range strings.Split(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name != "" {
+			directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+	return directives
+}
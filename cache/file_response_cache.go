@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const responseCacheDirectory = "responses"
+const responseCacheFileExtension = ".json"
+
+// The FileResponseCache stores HTTP responses on disk in the user cache
+// directory so that they can be revalidated by later CLI invocations.
+type FileResponseCache struct {
+	directory string
+}
+
+func (c FileResponseCache) Get(key string) *CachedResponse {
+	if c.directory == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var response CachedResponse
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil
+	}
+	return &response
+}
+
+func (c FileResponseCache) Set(key string, response CachedResponse) {
+	if c.directory == "" {
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(c.directory, cacheDirectoryPermissions)
+	if err != nil {
+		return
+	}
+	// Write to a temporary file first so that concurrent CLI invocations
+	// never read a partially written entry.
+	file, err := os.CreateTemp(c.directory, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return
+	}
+	err = os.Rename(file.Name(), c.path(key))
+	if err != nil {
+		os.Remove(file.Name())
+	}
+}
+
+// Clear removes all stored responses and returns the number of removed entries.
+func (c FileResponseCache) Clear() (int, error) {
+	entries, err := c.entries()
+	if err != nil {
+		return 0, err
+	}
+	for i, entry := range entries {
+		err := os.Remove(filepath.Join(c.directory, entry.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			return i, fmt.Errorf("Error clearing response cache: %w", err)
+		}
+	}
+	return len(entries), nil
+}
+
+func (c FileResponseCache) Info() (*CacheInfo, error) {
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	size := int64(0)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil {
+			size += info.Size()
+		}
+	}
+	return NewCacheInfo(c.directory, len(entries), size), nil
+}
+
+func (c FileResponseCache) entries() ([]os.DirEntry, error) {
+	if c.directory == "" {
+		return []os.DirEntry{}, nil
+	}
+	entries, err := os.ReadDir(c.directory)
+	if os.IsNotExist(err) {
+		return []os.DirEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading response cache: %w", err)
+	}
+	result := []os.DirEntry{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), responseCacheFileExtension) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (c FileResponseCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.directory, fmt.Sprintf("%x%s", hash, responseCacheFileExtension))
+}
+
+func NewFileResponseCache() *FileResponseCache {
+	directory := ""
+	userCacheDirectory, err := os.UserCacheDir()
+	if err == nil {
+		directory = filepath.Join(userCacheDirectory, cacheDirectory, responseCacheDirectory)
+	}
+	return newFileResponseCache(directory)
+}
+
+func newFileResponseCache(directory string) *FileResponseCache {
+	return &FileResponseCache{directory}
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheGetReturnsNilWhenNotCached(t *testing.T) {
+	cache := newFileResponseCache(t.TempDir())
+
+	response := cache.Get("UNKNOWN")
+
+	if response != nil {
+		t.Errorf("Should not return any response from cache, but got: %v", response)
+	}
+}
+
+func TestResponseCacheGetReturnsResponseWhenSet(t *testing.T) {
+	cache := newFileResponseCache(t.TempDir())
+	header := http.Header{"Etag": []string{`"v1"`}}
+	response := NewCachedResponse(200, "200 OK", header, []byte(`{"id":1}`), http.Header{}, time.Now())
+
+	cache.Set("my-key", *response)
+	result := cache.Get("my-key")
+
+	if result == nil || string(result.Body) != `{"id":1}` || result.ETag() != `"v1"` {
+		t.Errorf("Should return response from cache, but got: %v", result)
+	}
+}
+
+func TestResponseCacheClearRemovesAllEntries(t *testing.T) {
+	cache := newFileResponseCache(t.TempDir())
+	header := http.Header{"Etag": []string{`"v1"`}}
+	response := NewCachedResponse(200, "200 OK", header, []byte(`{}`), http.Header{}, time.Now())
+	cache.Set("key1", *response)
+	cache.Set("key2", *response)
+
+	info, _ := cache.Info()
+	if info.Entries != 2 || info.Size == 0 {
+		t.Errorf("Should return info for two entries, but got: %v", info)
+	}
+	removed, err := cache.Clear()
+	if err != nil || removed != 2 {
+		t.Errorf("Should remove two entries, but got: %v, %v", removed, err)
+	}
+	if cache.Get("key1") != nil {
+		t.Errorf("Should not return response after clear")
+	}
+}
+
+func TestCachedResponseWithMaxAgeIsFresh(t *testing.T) {
+	now := time.Now()
+	header := http.Header{"Cache-Control": []string{"private, max-age=60"}}
+
+	response := NewCachedResponse(200, "200 OK", header, []byte{}, http.Header{}, now)
+
+	if response == nil || !response.Fresh(now) || response.Fresh(now.Add(61*time.Second)) {
+		t.Errorf("Should be fresh for 60 seconds, but got: %v", response)
+	}
+}
+
+func TestCachedResponseWithNoCacheNeedsRevalidation(t *testing.T) {
+	now := time.Now()
+	header := http.Header{"Cache-Control": []string{"no-cache, max-age=60"}, "Etag": []string{`"v1"`}}
+
+	response := NewCachedResponse(200, "200 OK", header, []byte{}, http.Header{}, now)
+
+	if response == nil || response.Fresh(now) {
+		t.Errorf("Should need revalidation, but got: %v", response)
+	}
+}
+
+func TestCachedResponseIsNotCreatedForUncacheableResponses(t *testing.T) {
+	now := time.Now()
+	noStore := http.Header{"Cache-Control": []string{"no-store"}, "Etag": []string{`"v1"`}}
+	noValidators := http.Header{}
+	notFound := http.Header{"Etag": []string{`"v1"`}}
+
+	if NewCachedResponse(200, "200 OK", noStore, []byte{}, http.Header{}, now) != nil {
+		t.Errorf("Should not cache no-store response")
+	}
+	if NewCachedResponse(200, "200 OK", noValidators, []byte{}, http.Header{}, now) != nil {
+		t.Errorf("Should not cache response without validators")
+	}
+	if NewCachedResponse(404, "404 Not Found", notFound, []byte{}, http.Header{}, now) != nil {
+		t.Errorf("Should not cache error response")
+	}
+}
+
+func TestCachedResponseRevalidatedUpdatesExpiry(t *testing.T) {
+	now := time.Now()
+	header := http.Header{"Etag": []string{`"v1"`}}
+	response := NewCachedResponse(200, "200 OK", header, []byte{}, http.Header{}, now)
+
+	revalidated := response.Revalidated(http.Header{"Cache-Control": []string{"max-age=30"}}, now)
+
+	if !revalidated.Fresh(now) || revalidated.ETag() != `"v1"` {
+		t.Errorf("Should be fresh after revalidation, but got: %v", revalidated)
+	}
+}
+
+func TestCachedResponseMatchesVaryHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("ETag", `"v1"`)
+	header.Set("Vary", "Accept-Language")
+	requestHeader := http.Header{}
+	requestHeader.Set("Accept-Language", "en")
+
+	response := NewCachedResponse(200, "200 OK", header, []byte{}, requestHeader, time.Now())
+
+	if !response.Matches(requestHeader) {
+		t.Errorf("Expected response to match request with same header values")
+	}
+	otherHeader := http.Header{}
+	otherHeader.Set("Accept-Language", "de")
+	if response.Matches(otherHeader) {
+		t.Errorf("Expected response not to match request with different header values")
+	}
+}
+
+func TestCachedResponseDoesNotStoreVaryAll(t *testing.T) {
+	header := http.Header{}
+	header.Set("ETag", `"v1"`)
+	header.Set("Vary", "*")
+
+	if NewCachedResponse(200, "200 OK", header, []byte{}, http.Header{}, time.Now()) != nil {
+		t.Errorf("Expected response with Vary: * not to be stored")
+	}
+}
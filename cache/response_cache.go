@@ -0,0 +1,11 @@
+package cache
+
+// ResponseCache interface for storing HTTP responses.
+// It is used to revalidate responses of GET requests using their
+// ETag and Last-Modified validators across multiple CLI invocations.
+type ResponseCache interface {
+	Get(key string) *CachedResponse
+	Set(key string, response CachedResponse)
+	Clear() (int, error)
+	Info() (*CacheInfo, error)
+}
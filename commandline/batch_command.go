@@ -0,0 +1,117 @@
+package commandline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/UiPath/uipathcli/parser"
+	"github.com/urfave/cli/v2"
+)
+
+func (b CommandBuilder) createBatchCommand() *cli.Command {
+	flagBuilder := newFlagBuilder()
+	flagBuilder.AddFlag(&cli.StringFlag{
+		Name:  fileFlagName,
+		Usage: "JSONL or YAML file with the operations to execute",
+	})
+	flagBuilder.AddFlag(&cli.IntFlag{
+		Name:  concurrencyFlagName,
+		Usage: "Number of operations to execute in parallel",
+		Value: 4,
+	})
+	flagBuilder.AddFlags(b.CreateDefaultFlags(true))
+	flagBuilder.AddFlag(b.HelpFlag())
+
+	return &cli.Command{
+		Name:               "batch",
+		Usage:              "Executes operations from a file",
+		Description:        "Executes all operations from a JSONL or YAML file and prints one json result per operation",
+		Flags:              flagBuilder.ToList(),
+		CustomHelpTemplate: subcommandHelpTemplate,
+		Action: func(context *cli.Context) error {
+			err := b.validateRequiredFlags(context, fileFlagName)
+			if err != nil {
+				return err
+			}
+			version := context.String(versionFlagName)
+			if version == "" {
+				version = b.versionFromProfile(context.String(profileFlagName))
+			}
+			handler := newBatchCommandHandler(b.StdOut, b.StdErr, b.batchRunner(version))
+			ctx, tracer, span := b.startTrace(context.Context, context.Command.HelpName)
+			err = handler.Execute(ctx, context.String(fileFlagName), context.Int(concurrencyFlagName), b.batchArgs(context))
+			err = b.finishTrace(tracer, span, context.String(traceFileFlagName), err)
+			return b.cancellationError(context.Context, 0, err)
+		},
+		HideHelp: true,
+	}
+}
+
+// batchArgs forwards the global arguments provided to the batch command to
+// every operation. Non-2xx responses always fail the individual operation.
+// The trace file is written once by the batch command for all operations.
+func (b CommandBuilder) batchArgs(context *cli.Context) []string {
+	args := []string{"--" + failFlagName}
+	for _, name := range predefinedFlags {
+		if name == fileFlagName || name == helpFlagName || name == failFlagName || name == traceFileFlagName || !context.IsSet(name) {
+			continue
+		}
+		args = append(args, fmt.Sprintf("--%s=%v", name, context.Value(name)))
+	}
+	return args
+}
+
+// batchRunner executes every operation through the regular operation command.
+// The definitions are loaded once per service and shared between operations.
+func (b CommandBuilder) batchRunner(version string) batchRunFunc {
+	definitions := map[string]*parser.Definition{}
+	mutex := sync.Mutex{}
+	loadDefinition := func(service string) (*parser.Definition, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if definition, found := definitions[service]; found {
+			return definition, nil
+		}
+		definition, err := b.DefinitionProvider.Load(service, version)
+		if err != nil {
+			return nil, err
+		}
+		definitions[service] = definition
+		return definition, nil
+	}
+
+	return func(ctx context.Context, service string, operation []string, args []string, stdOut io.Writer, stdErr io.Writer) error {
+		definition, err := loadDefinition(service)
+		if err != nil {
+			return err
+		}
+		if definition == nil {
+			return newValidationError(fmt.Errorf("Unknown service '%s'", service))
+		}
+		if !b.hasOperation(*definition, operation) {
+			return newValidationError(fmt.Errorf("Unknown operation '%s' for service '%s'", strings.Join(operation, " "), service))
+		}
+
+		builder := b
+		builder.Input = nil
+		builder.StdOut = stdOut
+		builder.StdErr = stdErr
+		commands := []*cli.Command{builder.createServiceCommand(*definition)}
+		builder.HandleUsageErrors(commands)
+		app := &cli.App{
+			Name:                      "uipath",
+			Commands:                  commands,
+			Writer:                    stdOut,
+			ErrWriter:                 stdErr,
+			HideVersion:               true,
+			HideHelpCommand:           true,
+			DisableSliceFlagSeparator: true,
+			OnUsageError:              builder.UsageError,
+		}
+		commandArgs := append([]string{"uipath", service}, operation...)
+		return app.RunContext(ctx, append(commandArgs, args...))
+	}
+}
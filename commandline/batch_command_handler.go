@@ -0,0 +1,341 @@
+package commandline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// batchRunFunc executes a single operation of the given service with the
+// provided command line arguments.
+type batchRunFunc func(ctx context.Context, service string, operation []string, args []string, stdOut io.Writer, stdErr io.Writer) error
+
+// The batchCommandHandler implements the 'uipath batch' command which executes
+// many operations from a JSONL or YAML file using a bounded worker pool.
+//
+// Every operation is executed through the regular operation command so that
+// validation, type conversion, authentication and retries behave exactly the
+// same as when the operation is called directly. The handler writes one json
+// result record per operation on standard output and a summary on standard error.
+//
+// Example ops.jsonl:
+//
+//	{"service": "orchestrator", "operation": "users get-by-id", "parameters": {"key": 1}}
+//	{"service": "orchestrator", "operation": "users delete-by-id", "parameters": {"key": 2}}
+type batchCommandHandler struct {
+	StdOut io.Writer
+	StdErr io.Writer
+	Run    batchRunFunc
+}
+
+type batchOperation struct {
+	Service    string                 `json:"service" yaml:"service"`
+	Operation  string                 `json:"operation" yaml:"operation"`
+	Parameters map[string]interface{} `json:"parameters" yaml:"parameters"`
+}
+
+type batchJob struct {
+	Line      int
+	Operation *batchOperation
+	Err       error
+}
+
+type batchResult struct {
+	Line      int         `json:"line"`
+	Service   string      `json:"service"`
+	Operation string      `json:"operation"`
+	Status    string      `json:"status"`
+	ExitCode  int         `json:"exitCode"`
+	Body      interface{} `json:"body,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+const batchStatusSucceeded = "succeeded"
+const batchStatusFailed = "failed"
+
+func (h batchCommandHandler) Execute(ctx context.Context, path string, concurrency int, args []string) error {
+	if concurrency < 1 {
+		return newValidationError(fmt.Errorf("Invalid value for concurrency: '%d', must be at least 1", concurrency))
+	}
+	jobs, err := h.readJobs(path)
+	if err != nil {
+		return newValidationError(err)
+	}
+
+	jobChan := make(chan batchJob)
+	resultChan := make(chan batchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				resultChan <- h.execute(ctx, job, args)
+			}
+		}()
+	}
+	go func() {
+		for _, job := range jobs {
+			jobChan <- job
+		}
+		close(jobChan)
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	var firstFailure *batchResult
+	failed := 0
+	for result := range resultChan {
+		h.writeResult(result)
+		if result.Status == batchStatusFailed {
+			failed++
+			if firstFailure == nil || result.Line < firstFailure.Line {
+				r := result
+				firstFailure = &r
+			}
+		}
+	}
+
+	fmt.Fprintf(h.StdErr, "Batch completed: %d operations, %d succeeded, %d failed\n", len(jobs), len(jobs)-failed, failed)
+	if firstFailure != nil {
+		return newBatchError(fmt.Sprintf("%d of %d batch operations failed", failed, len(jobs)), firstFailure.ExitCode)
+	}
+	return nil
+}
+
+func (h batchCommandHandler) execute(ctx context.Context, job batchJob, args []string) batchResult {
+	if job.Err != nil {
+		return h.failed(job, ExitCodeValidationError, job.Err.Error())
+	}
+	if ctx.Err() != nil {
+		return h.failed(job, ExitCode(ctx.Err()), "Operation cancelled")
+	}
+	if job.Operation.Service == "" || job.Operation.Operation == "" {
+		return h.failed(job, ExitCodeValidationError, "Batch operation requires a service and an operation")
+	}
+	parameterArgs, err := h.parameterArgs(job.Operation.Parameters)
+	if err != nil {
+		return h.failed(job, ExitCodeValidationError, err.Error())
+	}
+
+	stdOut := &bytes.Buffer{}
+	stdErr := &bytes.Buffer{}
+	operation := strings.Fields(job.Operation.Operation)
+	err = h.Run(ctx, job.Operation.Service, operation, append(parameterArgs, args...), stdOut, stdErr)
+	if stdErr.Len() > 0 {
+		_, _ = h.StdErr.Write(stdErr.Bytes())
+	}
+
+	result := batchResult{
+		Line:      job.Line,
+		Service:   job.Operation.Service,
+		Operation: job.Operation.Operation,
+		Status:    batchStatusSucceeded,
+		ExitCode:  ExitCode(err),
+		Body:      parseResultBody(stdOut.Bytes()),
+	}
+	if err != nil {
+		result.Status = batchStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (h batchCommandHandler) failed(job batchJob, exitCode int, message string) batchResult {
+	result := batchResult{
+		Line:     job.Line,
+		Status:   batchStatusFailed,
+		ExitCode: exitCode,
+		Error:    message,
+	}
+	if job.Operation != nil {
+		result.Service = job.Operation.Service
+		result.Operation = job.Operation.Operation
+	}
+	return result
+}
+
+func (h batchCommandHandler) writeResult(result batchResult) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		data, _ = json.Marshal(h.failed(batchJob{Line: result.Line}, ExitCodeError, err.Error()))
+	}
+	fmt.Fprintln(h.StdOut, string(data))
+}
+
+// parseResultBody converts the output of a single operation into a json value
+// which can be embedded in the result records.
+func parseResultBody(body []byte) interface{} {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var data interface{}
+	err := json.Unmarshal(body, &data)
+	if err != nil {
+		return string(body)
+	}
+	return data
+}
+
+func (h batchCommandHandler) parameterArgs(parameters map[string]interface{}) ([]string, error) {
+	names := []string{}
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := []string{}
+	for _, name := range names {
+		values, ok := parameters[name].([]interface{})
+		if !ok {
+			values = []interface{}{parameters[name]}
+		}
+		for _, value := range values {
+			formatted, err := h.formatValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for parameter '%s': %w", name, err)
+			}
+			if _, ok := value.(bool); ok {
+				// Boolean switches only accept their value in the --name=value
+				// form, otherwise '--delete false' would enable the switch.
+				args = append(args, "--"+name+"="+formatted)
+				continue
+			}
+			args = append(args, "--"+name, formatted)
+		}
+	}
+	return args, nil
+}
+
+func (h batchCommandHandler) formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+func (h batchCommandHandler) readJobs(path string) ([]batchJob, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading batch file '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension == ".yaml" || extension == ".yml" {
+		return h.readYamlJobs(file, path)
+	}
+	return h.readJsonLinesJobs(file, path)
+}
+
+func (h batchCommandHandler) readJsonLinesJobs(reader io.Reader, path string) ([]batchJob, error) {
+	jobs := []batchJob{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		operation, err := h.parseJsonLine(text)
+		if err != nil {
+			jobs = append(jobs, batchJob{Line: line, Err: fmt.Errorf("Invalid batch operation: %w", err)})
+			continue
+		}
+		jobs = append(jobs, batchJob{Line: line, Operation: operation})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading batch file '%s': %w", path, err)
+	}
+	return jobs, nil
+}
+
+// parseJsonLine keeps numbers as json.Number so that large ids are passed to
+// the operation without losing precision.
+func (h batchCommandHandler) parseJsonLine(text string) (*batchOperation, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var operation batchOperation
+	err := decoder.Decode(&operation)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return &operation, nil
+}
+
+func (h batchCommandHandler) readYamlJobs(reader io.Reader, path string) ([]batchJob, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading batch file '%s': %w", path, err)
+	}
+	var operations []batchOperation
+	err = yaml.Unmarshal(data, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing batch file '%s': %w", path, err)
+	}
+	jobs := []batchJob{}
+	for i := range operations {
+		operation := operations[i]
+		operation.Parameters = h.normalizeYaml(operation.Parameters).(map[string]interface{})
+		jobs = append(jobs, batchJob{Line: i + 1, Operation: &operation})
+	}
+	return jobs, nil
+}
+
+// normalizeYaml converts the map[interface{}]interface{} values created by the
+// yaml parser into map[string]interface{} so that they can be serialized as json.
+func (h batchCommandHandler) normalizeYaml(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range v {
+			result[key] = h.normalizeYaml(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = h.normalizeYaml(item)
+		}
+		return result
+	case []interface{}:
+		result := []interface{}{}
+		for _, item := range v {
+			result = append(result, h.normalizeYaml(item))
+		}
+		return result
+	case nil:
+		return map[string]interface{}{}
+	}
+	return value
+}
+
+func newBatchCommandHandler(stdOut io.Writer, stdErr io.Writer, run batchRunFunc) *batchCommandHandler {
+	return &batchCommandHandler{stdOut, stdErr, run}
+}
@@ -0,0 +1,16 @@
+package commandline
+
+// BatchError is returned when at least one operation of a batch failed.
+// It carries the exit code of the first failed operation in the file.
+type BatchError struct {
+	message  string
+	exitCode int
+}
+
+func (e BatchError) Error() string {
+	return e.message
+}
+
+func newBatchError(message string, exitCode int) *BatchError {
+	return &BatchError{message, exitCode}
+}
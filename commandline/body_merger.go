@@ -0,0 +1,62 @@
+package commandline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// The bodyMerger combines the JSON request body from the --file argument
+// with the body parameters provided as individual command-line arguments.
+//
+// Nested objects are merged recursively so that a single field of a base
+// template can be overridden. All other values, including arrays, are
+// replaced.
+type bodyMerger struct{}
+
+func (m bodyMerger) Read(input utils.Stream) (map[string]interface{}, error) {
+	reader, err := input.Data()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading request body from '%s': %w", input.Name(), err)
+	}
+	var body interface{}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing request body from '%s': %w", input.Name(), err)
+	}
+	result, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Request body from '%s' must be a JSON object to be merged", input.Name())
+	}
+	return result, nil
+}
+
+// Merge returns a new object with the values of the override deep-merged
+// into the base object.
+func (m bodyMerger) Merge(base map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range override {
+		baseObject, baseIsObject := result[key].(map[string]interface{})
+		overrideObject, overrideIsObject := value.(map[string]interface{})
+		if baseIsObject && overrideIsObject {
+			result[key] = m.Merge(baseObject, overrideObject)
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func newBodyMerger() *bodyMerger {
+	return &bodyMerger{}
+}
@@ -0,0 +1,40 @@
+package commandline
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBodyMergerMergesNestedObjects(t *testing.T) {
+	base := map[string]interface{}{
+		"name":  "my-product",
+		"price": map[string]interface{}{"value": 100, "currency": "EUR"},
+		"tags":  []interface{}{"a", "b"},
+	}
+	override := map[string]interface{}{
+		"price": map[string]interface{}{"value": 200},
+		"tags":  []interface{}{"c"},
+	}
+
+	result := newBodyMerger().Merge(base, override)
+
+	data, _ := json.Marshal(result)
+	expected := `{"name":"my-product","price":{"currency":"EUR","value":200},"tags":["c"]}`
+	if string(data) != expected {
+		t.Errorf("Expected merged body %v, but got: %v", expected, string(data))
+	}
+	if base["price"].(map[string]interface{})["value"] != 100 {
+		t.Errorf("Expected base object to be unchanged, but got: %v", base)
+	}
+}
+
+func TestBodyMergerReplacesObjectWithScalar(t *testing.T) {
+	base := map[string]interface{}{"price": map[string]interface{}{"value": 100}}
+	override := map[string]interface{}{"price": nil}
+
+	result := newBodyMerger().Merge(base, override)
+
+	if result["price"] != nil {
+		t.Errorf("Expected object to be replaced, but got: %v", result["price"])
+	}
+}
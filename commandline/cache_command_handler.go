@@ -0,0 +1,42 @@
+package commandline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/UiPath/uipathcli/cache"
+)
+
+// The cacheCommandHandler implements the 'uipath cache' commands which
+// inspect and clear the HTTP response cache.
+type cacheCommandHandler struct {
+	StdOut io.Writer
+	Cache  cache.ResponseCache
+}
+
+func (h cacheCommandHandler) Info() error {
+	info, err := h.Cache.Info()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error formatting cache info: %w", err)
+	}
+	fmt.Fprintln(h.StdOut, string(data))
+	return nil
+}
+
+func (h cacheCommandHandler) Clear() error {
+	count, err := h.Cache.Clear()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h.StdOut, "Removed %d cached responses\n", count)
+	return nil
+}
+
+func newCacheCommandHandler(stdOut io.Writer, cache cache.ResponseCache) *cacheCommandHandler {
+	return &cacheCommandHandler{stdOut, cache}
+}
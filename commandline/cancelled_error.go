@@ -0,0 +1,21 @@
+package commandline
+
+// CancelledError is returned when the command was aborted before it
+// completed, either because the user pressed Ctrl+C or because the
+// provided --timeout expired.
+type CancelledError struct {
+	message string
+	err     error
+}
+
+func (e CancelledError) Error() string {
+	return e.message
+}
+
+func (e CancelledError) Unwrap() error {
+	return e.err
+}
+
+func newCancelledError(message string, err error) *CancelledError {
+	return &CancelledError{message, err}
+}
@@ -3,6 +3,7 @@
 package commandline
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -24,10 +25,10 @@ type Cli struct {
 	pluginExecutor     executor.Executor
 }
 
-func (c Cli) run(args []string, input utils.Stream) error {
+func (c Cli) run(ctx context.Context, args []string, input utils.Stream) error {
 	err := c.configProvider.Load()
 	if err != nil {
-		return err
+		return newConfigError(err)
 	}
 
 	CommandBuilder := CommandBuilder{
@@ -45,6 +46,7 @@ func (c Cli) run(args []string, input utils.Stream) error {
 	if err != nil {
 		return err
 	}
+	CommandBuilder.HandleUsageErrors(commands)
 
 	app := &cli.App{
 		Name:                      "uipath",
@@ -58,15 +60,22 @@ func (c Cli) run(args []string, input utils.Stream) error {
 		HideVersion:               true,
 		HideHelpCommand:           true,
 		DisableSliceFlagSeparator: true,
+		OnUsageError:              CommandBuilder.UsageError,
 	}
-	return app.Run(args)
+	return app.RunContext(ctx, args)
 }
 
 const colorRed = "\033[31m"
 const colorReset = "\033[0m"
 
 func (c Cli) Run(args []string, input utils.Stream) error {
-	err := c.run(args, input)
+	return c.RunContext(context.Background(), args, input)
+}
+
+// RunContext executes the CLI command and aborts all in-flight requests
+// as soon as the provided context is cancelled.
+func (c Cli) RunContext(ctx context.Context, args []string, input utils.Stream) error {
+	err := c.run(ctx, args, input)
 	if err != nil {
 		message := err.Error()
 		if c.coloredOutput {
@@ -1,21 +1,26 @@
 package commandline
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/UiPath/uipathcli/cache"
 	"github.com/UiPath/uipathcli/config"
 	"github.com/UiPath/uipathcli/executor"
 	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/output"
 	"github.com/UiPath/uipathcli/parser"
+	"github.com/UiPath/uipathcli/tracing"
 	"github.com/UiPath/uipathcli/utils"
 	"github.com/urfave/cli/v2"
 )
@@ -33,8 +38,46 @@ const outputFormatFlagName = "output"
 const queryFlagName = "query"
 const waitFlagName = "wait"
 const waitTimeoutFlagName = "wait-timeout"
+const waitIntervalFlagName = "wait-interval"
+const waitBackoffFlagName = "wait-backoff"
+const waitFailFlagName = "wait-fail"
+const waitProgressFlagName = "wait-progress"
+const waitProbeFlagName = "wait-probe"
+const waitProbeIdFlagName = "wait-probe-id"
 const versionFlagName = "version"
 const fileFlagName = "file"
+const paginateFlagName = "paginate"
+const pageSizeFlagName = "page-size"
+const maxItemsFlagName = "max-items"
+const maxRetriesFlagName = "max-retries"
+const retryBackoffFlagName = "retry-backoff"
+const retryMaxBackoffFlagName = "retry-max-backoff"
+const retryJitterFlagName = "retry-jitter"
+const retryStatusCodesFlagName = "retry-status-codes"
+const failFlagName = "fail"
+const noFailFlagName = "no-fail"
+const timeoutFlagName = "timeout"
+const proxyFlagName = "proxy"
+const caCertFlagName = "ca-cert"
+const clientCertFlagName = "client-cert"
+const clientKeyFlagName = "client-key"
+const dryRunFlagName = "dry-run"
+const asCurlFlagName = "as-curl"
+const outFileFlagName = "out-file"
+const recordFlagName = "record"
+const replayFlagName = "replay"
+const forEachFlagName = "for-each"
+const forEachFileFlagName = "for-each-file"
+const forEachFormatFlagName = "for-each-format"
+const forEachConcurrencyFlagName = "for-each-concurrency"
+const concurrencyFlagName = "concurrency"
+const cacheFlagName = "cache"
+const noCacheFlagName = "no-cache"
+const mergeFlagName = "merge"
+const compressRequestFlagName = "compress-request"
+const timingFlagName = "timing"
+const requestIdFlagName = "request-id"
+const traceFileFlagName = "trace-file"
 
 var predefinedFlags = []string{
 	insecureFlagName,
@@ -48,12 +91,50 @@ var predefinedFlags = []string{
 	queryFlagName,
 	waitFlagName,
 	waitTimeoutFlagName,
+	waitIntervalFlagName,
+	waitBackoffFlagName,
+	waitFailFlagName,
+	waitProgressFlagName,
+	waitProbeFlagName,
+	waitProbeIdFlagName,
 	versionFlagName,
 	fileFlagName,
+	paginateFlagName,
+	pageSizeFlagName,
+	maxItemsFlagName,
+	maxRetriesFlagName,
+	retryBackoffFlagName,
+	retryMaxBackoffFlagName,
+	retryJitterFlagName,
+	retryStatusCodesFlagName,
+	failFlagName,
+	noFailFlagName,
+	timeoutFlagName,
+	proxyFlagName,
+	caCertFlagName,
+	clientCertFlagName,
+	clientKeyFlagName,
+	dryRunFlagName,
+	asCurlFlagName,
+	outFileFlagName,
+	recordFlagName,
+	replayFlagName,
+	forEachFlagName,
+	forEachFileFlagName,
+	forEachFormatFlagName,
+	forEachConcurrencyFlagName,
+	cacheFlagName,
+	noCacheFlagName,
+	mergeFlagName,
+	compressRequestFlagName,
+	timingFlagName,
+	requestIdFlagName,
+	traceFileFlagName,
 }
 
 const outputFormatJson = "json"
 const outputFormatText = "text"
+const outputFormatCurl = "curl"
 
 const subcommandHelpTemplate = `NAME:
    {{template "helpNameTemplate" .}}
@@ -107,6 +188,93 @@ func (b CommandBuilder) fileInput(context *cli.Context, parameters []parser.Para
 	return utils.NewFileStream(value)
 }
 
+// mergeInput decides whether the JSON body from --file is merged with the body
+// parameters instead of being sent as-is. Merging is enabled with --merge and
+// automatically for JSON operations when body parameters are provided as well.
+func (b CommandBuilder) mergeInput(context *cli.Context, operation parser.Operation, input utils.Stream) (bool, error) {
+	merge := context.Bool(mergeFlagName)
+	if merge && input == nil {
+		return false, fmt.Errorf("The --%s parameter requires a request body from --%s", mergeFlagName, fileFlagName)
+	}
+	if input == nil {
+		return false, nil
+	}
+	json := operation.ContentType == "application/json"
+	if merge && !json {
+		return false, fmt.Errorf("The --%s parameter is only supported for operations with a JSON request body", mergeFlagName)
+	}
+	return merge || (json && b.hasBodyArguments(context, operation.Parameters)), nil
+}
+
+func (b CommandBuilder) hasBodyArguments(context *cli.Context, parameters []parser.Parameter) bool {
+	for _, parameter := range parameters {
+		if parameter.In == parser.ParameterInBody && context.IsSet(parameter.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBody uses the JSON object from the input as the request body. Body
+// parameters provided as arguments override the values from the file while
+// values from the profile and defaults are only used for missing fields.
+func (b CommandBuilder) mergeBody(context *cli.Context, operation parser.Operation, input utils.Stream, parameters executor.ExecutionParameters) (executor.ExecutionParameters, error) {
+	merger := newBodyMerger()
+	body, err := merger.Read(input)
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[string]interface{}{}
+	overrides := map[string]interface{}{}
+	result := []executor.ExecutionParameter{}
+	for _, parameter := range parameters {
+		if parameter.In != parser.ParameterInBody {
+			result = append(result, parameter)
+		} else if b.isBodyArgumentSet(context, operation.Parameters, parameter.Name) {
+			overrides[parameter.Name] = parameter.Value
+		} else {
+			defaults[parameter.Name] = parameter.Value
+		}
+	}
+	body = merger.Merge(merger.Merge(defaults, body), overrides)
+
+	keys := []string{}
+	for key := range body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parameter := executor.NewExecutionParameter(key, body[key], parser.ParameterInBody)
+		result = append(result, *parameter)
+	}
+	return result, nil
+}
+
+func (b CommandBuilder) isBodyArgumentSet(context *cli.Context, parameters []parser.Parameter, fieldName string) bool {
+	for _, parameter := range parameters {
+		if parameter.In == parser.ParameterInBody && parameter.FieldName == fieldName {
+			return context.IsSet(parameter.Name)
+		}
+	}
+	return false
+}
+
+// missingBodyParameters removes the body parameters which are provided by
+// the merged request body so that only missing arguments are validated.
+func (b CommandBuilder) missingBodyParameters(parameters []parser.Parameter, executionParameters executor.ExecutionParameters) []parser.Parameter {
+	body := map[string]bool{}
+	for _, parameter := range executionParameters.Body() {
+		body[parameter.Name] = true
+	}
+	result := []parser.Parameter{}
+	for _, parameter := range parameters {
+		if parameter.In != parser.ParameterInBody || !body[parameter.FieldName] {
+			result = append(result, parameter)
+		}
+	}
+	return result
+}
+
 func (b CommandBuilder) createExecutionParameters(context *cli.Context, config *config.Config, operation parser.Operation) (executor.ExecutionParameters, error) {
 	typeConverter := newTypeConverter()
 
@@ -171,6 +339,14 @@ func (b CommandBuilder) createFlags(parameters []parser.Parameter) []cli.Flag {
 				Usage: formatter.Description(),
 			}
 			flags = append(flags, &flag)
+		} else if parameter.In == parser.ParameterInCustom && parameter.Type == parser.ParameterTypeBoolean {
+			// Boolean parameters of custom commands are switches which
+			// do not require a value, e.g. --resume
+			flag := cli.BoolFlag{
+				Name:  parameter.Name,
+				Usage: formatter.Description(),
+			}
+			flags = append(flags, &flag)
 		} else {
 			flag := cli.StringFlag{
 				Name:  parameter.Name,
@@ -196,18 +372,151 @@ func (b CommandBuilder) sortParameters(parameters []parser.Parameter) {
 
 func (b CommandBuilder) outputFormat(config config.Config, context *cli.Context) (string, error) {
 	outputFormat := context.String(outputFormatFlagName)
+	if context.Bool(asCurlFlagName) {
+		outputFormat = outputFormatCurl
+	}
 	if outputFormat == "" {
 		outputFormat = config.Output
 	}
 	if outputFormat == "" {
 		outputFormat = outputFormatJson
 	}
-	if outputFormat != outputFormatJson && outputFormat != outputFormatText {
-		return "", fmt.Errorf("Invalid output format '%s', allowed values: %s, %s", outputFormat, outputFormatJson, outputFormatText)
+	if outputFormat != outputFormatJson && outputFormat != outputFormatText && outputFormat != outputFormatCurl {
+		return "", fmt.Errorf("Invalid output format '%s', allowed values: %s, %s, %s", outputFormat, outputFormatJson, outputFormatText, outputFormatCurl)
 	}
 	return outputFormat, nil
 }
 
+func (b CommandBuilder) retryPolicy(config config.Config, context *cli.Context) (*utils.RetryPolicy, error) {
+	policy := utils.DefaultRetryPolicy()
+	if config.Retry.MaxRetries != nil {
+		policy.MaxAttempts = *config.Retry.MaxRetries + 1
+	}
+	if context.IsSet(maxRetriesFlagName) {
+		policy.MaxAttempts = context.Int(maxRetriesFlagName) + 1
+	}
+	if policy.MaxAttempts < 1 {
+		return nil, fmt.Errorf("Invalid value for --%s: needs to be 0 or greater", maxRetriesFlagName)
+	}
+	if config.Retry.Backoff > 0 {
+		policy.Backoff = config.Retry.Backoff
+	}
+	if context.IsSet(retryBackoffFlagName) {
+		policy.Backoff = context.Duration(retryBackoffFlagName)
+	}
+	if config.Retry.MaxBackoff > 0 {
+		policy.MaxBackoff = config.Retry.MaxBackoff
+	}
+	if context.IsSet(retryMaxBackoffFlagName) {
+		policy.MaxBackoff = context.Duration(retryMaxBackoffFlagName)
+	}
+	policy.Jitter = context.Bool(retryJitterFlagName) || config.Retry.Jitter
+	if len(config.Retry.StatusCodes) > 0 {
+		policy.StatusCodes = config.Retry.StatusCodes
+	}
+	statusCodes := context.String(retryStatusCodesFlagName)
+	if statusCodes != "" {
+		value, err := b.parseStatusCodes(statusCodes)
+		if err != nil {
+			return nil, err
+		}
+		policy.StatusCodes = value
+	}
+	return policy, nil
+}
+
+func (b CommandBuilder) httpClientSettings(config config.Config, context *cli.Context, profileName string, service string) (*network.HttpClientSettings, error) {
+	insecure := context.Bool(insecureFlagName) || config.Insecure
+	proxy := config.Proxy
+	proxyFlag := context.String(proxyFlagName)
+	if proxyFlag != "" {
+		var err error
+		proxy, err = url.Parse(proxyFlag)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing %s argument: %w", proxyFlagName, err)
+		}
+	}
+	caCert := context.String(caCertFlagName)
+	if caCert == "" {
+		caCert = config.CaCert
+	}
+	clientCert := context.String(clientCertFlagName)
+	if clientCert == "" {
+		clientCert = config.ClientCert
+	}
+	clientKey := context.String(clientKeyFlagName)
+	if clientKey == "" {
+		clientKey = config.ClientKey
+	}
+	cassette, err := b.cassette(context)
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter, err := b.rateLimiter(config, profileName, service)
+	if err != nil {
+		return nil, err
+	}
+	var timing *network.TimingReporter
+	if context.Bool(timingFlagName) {
+		debug := context.Bool(debugFlagName) || config.Debug
+		timing = network.NewTimingReporter(b.StdErr, debug)
+	}
+	return &network.HttpClientSettings{
+		Insecure:            insecure,
+		Proxy:               proxy,
+		CaCert:              caCert,
+		ClientCert:          clientCert,
+		ClientKey:           clientKey,
+		Cassette:            cassette,
+		MaxIdleConns:        config.Connection.MaxIdleConns,
+		MaxIdleConnsPerHost: config.Connection.MaxIdleConnsPerHost,
+		IdleConnTimeout:     config.Connection.IdleTimeout,
+		RateLimiter:         rateLimiter,
+		Timing:              timing,
+	}, nil
+}
+
+// rateLimiter returns the limiter for the service which is shared by all
+// operations of the profile executed in this process.
+func (b CommandBuilder) rateLimiter(config config.Config, profileName string, service string) (*network.RateLimiter, error) {
+	value := config.RateLimit[service]
+	if value == "" {
+		return nil, nil
+	}
+	rateLimit, err := network.ParseRateLimit(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid rate limit for service '%s': %w", service, err)
+	}
+	return network.NewSharedRateLimiter(profileName+"|"+service, *rateLimit), nil
+}
+
+func (b CommandBuilder) cassette(context *cli.Context) (*network.Cassette, error) {
+	record := context.String(recordFlagName)
+	replay := context.String(replayFlagName)
+	if record != "" && replay != "" {
+		return nil, fmt.Errorf("The --%s and --%s parameters cannot be combined", recordFlagName, replayFlagName)
+	}
+	if record != "" {
+		return network.NewCassette(record, false), nil
+	}
+	if replay != "" {
+		return network.NewCassette(replay, true), nil
+	}
+	return nil, nil
+}
+
+func (b CommandBuilder) parseStatusCodes(value string) ([]int, error) {
+	result := []int{}
+	for _, item := range strings.Split(value, ",") {
+		statusCode, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for --%s: '%s' is not a status code", retryStatusCodesFlagName, item)
+		}
+		result = append(result, statusCode)
+	}
+	return result, nil
+}
+
 func (b CommandBuilder) createBaseUri(operation parser.Operation, config config.Config, context *cli.Context) (url.URL, error) {
 	uriArgument, err := b.parseUriArgument(context)
 	if err != nil {
@@ -251,6 +560,36 @@ func (b CommandBuilder) getValue(parameter parser.Parameter, context *cli.Contex
 	return ""
 }
 
+// HandleUsageErrors reports invalid arguments, like unknown flags or flags
+// without a value, of all commands as validation errors.
+func (b CommandBuilder) HandleUsageErrors(commands []*cli.Command) {
+	for _, command := range commands {
+		command.OnUsageError = b.UsageError
+		b.HandleUsageErrors(command.Subcommands)
+	}
+}
+
+func (b CommandBuilder) UsageError(context *cli.Context, err error, isSubcommand bool) error {
+	return newValidationError(err)
+}
+
+// validateRequiredFlags returns a validation error in case one of the given
+// flags of the built-in commands is not provided.
+func (b CommandBuilder) validateRequiredFlags(context *cli.Context, names ...string) error {
+	err := errors.New("Invalid arguments:")
+	result := true
+	for _, name := range names {
+		if !context.IsSet(name) {
+			result = false
+			err = fmt.Errorf("%w\n  Argument --%s is missing", err, name)
+		}
+	}
+	if result {
+		return nil
+	}
+	return newValidationError(err)
+}
+
 func (b CommandBuilder) validateArguments(context *cli.Context, parameters []parser.Parameter, config config.Config) error {
 	err := errors.New("Invalid arguments:")
 	result := true
@@ -299,6 +638,13 @@ func (b CommandBuilder) outputWriter(writer io.Writer, format string, query stri
 	return output.NewJsonOutputWriter(writer, transformer)
 }
 
+func (b CommandBuilder) fileOutputWriter(writer io.Writer, path string, format string, query string) output.OutputWriter {
+	if path == "-" {
+		return output.NewRawOutputWriter(writer)
+	}
+	return output.NewFileOutputWriter(path, b.outputWriter(writer, format, query))
+}
+
 func (b CommandBuilder) executeCommand(context executor.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
 	if context.Plugin != nil {
 		return b.PluginExecutor.Call(context, writer, logger)
@@ -306,9 +652,12 @@ func (b CommandBuilder) executeCommand(context executor.ExecutionContext, writer
 	return b.Executor.Call(context, writer, logger)
 }
 
-func (b CommandBuilder) createOperationCommand(operation parser.Operation) *cli.Command {
-	parameters := operation.Parameters
+func (b CommandBuilder) createOperationCommand(definition parser.Definition, operation parser.Operation) *cli.Command {
+	service := definition.Name
+	// Sort a copy so that definitions can be shared between concurrent batch operations
+	parameters := append([]parser.Parameter{}, operation.Parameters...)
 	b.sortParameters(parameters)
+	operation.Parameters = parameters
 
 	flagBuilder := newFlagBuilder()
 	flagBuilder.AddFlags(b.createFlags(parameters))
@@ -325,32 +674,69 @@ func (b CommandBuilder) createOperationCommand(operation parser.Operation) *cli.
 			profileName := context.String(profileFlagName)
 			config := b.ConfigProvider.Config(profileName)
 			if config == nil {
-				return fmt.Errorf("Could not find profile '%s'", profileName)
+				return newConfigError(fmt.Errorf("Could not find profile '%s'", profileName))
 			}
 			outputFormat, err := b.outputFormat(*config, context)
 			if err != nil {
-				return err
+				return newValidationError(err)
 			}
 			query := context.String(queryFlagName)
 			wait := context.String(waitFlagName)
-			waitTimeout := context.Int(waitTimeoutFlagName)
+			paginate := context.Bool(paginateFlagName)
+			pageSize := context.Int(pageSizeFlagName)
+			maxItems := context.Int(maxItemsFlagName)
+			asCurl := outputFormat == outputFormatCurl
+			dryRun := context.Bool(dryRunFlagName) || asCurl
+			outFile := context.String(outFileFlagName)
+			forEach := context.String(forEachFlagName)
 
 			baseUri, err := b.createBaseUri(operation, *config, context)
 			if err != nil {
-				return err
+				return newValidationError(err)
+			}
+			waitOptions, err := b.waitOptions(context, definition)
+			if err != nil {
+				return newValidationError(err)
+			}
+			retryPolicy, err := b.retryPolicy(*config, context)
+			if err != nil {
+				return newValidationError(err)
+			}
+			httpClientSettings, err := b.httpClientSettings(*config, context, profileName, service)
+			if err != nil {
+				return newValidationError(err)
 			}
 
+			forEachParameter, requiredParameters, err := b.forEachParameter(operation.Parameters, forEach)
+			if err != nil {
+				return newValidationError(err)
+			}
 			input := b.fileInput(context, operation.Parameters)
+			merge, err := b.mergeInput(context, operation, input)
+			if err != nil {
+				return newValidationError(err)
+			}
 			if input == nil {
-				err = b.validateArguments(context, operation.Parameters, *config)
+				err = b.validateArguments(context, requiredParameters, *config)
 				if err != nil {
-					return err
+					return newValidationError(err)
 				}
 			}
 
 			parameters, err := b.createExecutionParameters(context, config, operation)
 			if err != nil {
-				return err
+				return newValidationError(err)
+			}
+			if merge {
+				parameters, err = b.mergeBody(context, operation, input, parameters)
+				if err != nil {
+					return newValidationError(err)
+				}
+				err = b.validateArguments(context, b.missingBodyParameters(requiredParameters, parameters), *config)
+				if err != nil {
+					return newValidationError(err)
+				}
+				input = nil
 			}
 
 			organization := context.String(organizationFlagName)
@@ -361,75 +747,188 @@ func (b CommandBuilder) createOperationCommand(operation parser.Operation) *cli.
 			if tenant == "" {
 				tenant = config.Tenant
 			}
-			insecure := context.Bool(insecureFlagName) || config.Insecure
 			debug := context.Bool(debugFlagName) || config.Debug
-			executionContext := executor.NewExecutionContext(
-				organization,
-				tenant,
-				operation.Method,
-				baseUri,
-				operation.Route,
-				operation.ContentType,
-				input,
-				parameters,
-				config.Auth,
-				insecure,
-				debug,
-				operation.Plugin)
-
-			if wait != "" {
-				return b.executeWait(*executionContext, outputFormat, query, wait, waitTimeout)
-			}
-			return b.execute(*executionContext, outputFormat, query, nil)
+			fail := (context.Bool(failFlagName) || config.Fail) && !context.Bool(noFailFlagName)
+			useCache := (context.Bool(cacheFlagName) || config.Cache) && !context.Bool(noCacheFlagName)
+			timeout := b.timeout(*config, context)
+			ctx, cancel := b.cancellationContext(context.Context, timeout)
+			defer cancel()
+			executionContext := &executor.ExecutionContext{
+				Organization:    organization,
+				Tenant:          tenant,
+				Method:          operation.Method,
+				BaseUri:         baseUri,
+				Route:           operation.Route,
+				ContentType:     operation.ContentType,
+				Input:           input,
+				Parameters:      parameters,
+				AuthConfig:      config.Auth,
+				Network:         *httpClientSettings,
+				Debug:           debug,
+				Fail:            fail,
+				DryRun:          dryRun,
+				AsCurl:          asCurl,
+				Stream:          outFile != "",
+				CompressRequest: context.Bool(compressRequestFlagName),
+				Cache:           useCache,
+				Profile:         profileName,
+				RequestId:       context.String(requestIdFlagName),
+				RetryPolicy:     *retryPolicy,
+				Context:         ctx,
+				Plugin:          operation.Plugin,
+			}
+
+			err = b.validateExecutionMode(operation, wait, paginate, outFile, forEach, dryRun, asCurl)
+			if err != nil {
+				return newValidationError(err)
+			}
+			var forEachValues []string
+			if forEachParameter != nil {
+				forEachValues, err = b.forEachValues(context)
+				if err != nil {
+					return newValidationError(err)
+				}
+			}
+
+			traceContext, tracer, span := b.startTrace(ctx, context.Command.HelpName)
+			span.SetAttribute("uipath.profile", profileName)
+			executionContext.Context = traceContext
+			if forEachParameter != nil {
+				err = b.executeForEach(*executionContext, *forEachParameter, forEachValues, context.Int(forEachConcurrencyFlagName), b.forEachFormat(context), outputFormat, query)
+			} else if dryRun {
+				err = b.execute(*executionContext, outputFormat, query, "", nil)
+			} else if wait != "" {
+				handler := newWaitHandler(b.outputWriter(b.StdOut, outputFormat, query), log.NewDefaultLogger(b.StdErr), b.executeInto)
+				err = handler.Execute(*executionContext, *waitOptions)
+			} else if paginate {
+				handler := newPaginateHandler(b.outputWriter(b.StdOut, outputFormat, query), b.executeInto)
+				err = handler.Execute(*executionContext, pageSize, maxItems)
+			} else {
+				err = b.execute(*executionContext, outputFormat, query, outFile, nil)
+			}
+			err = b.finishTrace(tracer, span, context.String(traceFileFlagName), err)
+			return b.cancellationError(ctx, timeout, err)
 		},
 		HideHelp: true,
 		Hidden:   operation.Hidden,
 	}
 }
 
-func (b CommandBuilder) executeWait(executionContext executor.ExecutionContext, outputFormat string, query string, wait string, waitTimeout int) error {
-	logger := log.NewDefaultLogger(b.StdErr)
-	outputWriter := output.NewMemoryOutputWriter()
-	for start := time.Now(); time.Since(start) < time.Duration(waitTimeout)*time.Second; {
-		err := b.execute(executionContext, "json", "", outputWriter)
-		result, evaluationErr := b.evaluateWaitCondition(outputWriter.Response(), wait)
-		if evaluationErr != nil {
-			return evaluationErr
+// validateExecutionMode rejects combinations of arguments which change how
+// the operation is executed but cannot be used together.
+func (b CommandBuilder) validateExecutionMode(operation parser.Operation, wait string, paginate bool, outFile string, forEach string, dryRun bool, asCurl bool) error {
+	if paginate && !supportsPagination(operation) {
+		return fmt.Errorf("Operation '%s' does not support pagination", operation.Name)
+	}
+	if outFile != "" && (wait != "" || paginate) {
+		return fmt.Errorf("The --%s parameter cannot be combined with --%s or --%s", outFileFlagName, waitFlagName, paginateFlagName)
+	}
+	if forEach != "" && (wait != "" || paginate || outFile != "") {
+		return fmt.Errorf("The --%s parameter cannot be combined with --%s, --%s or --%s", forEachFlagName, waitFlagName, paginateFlagName, outFileFlagName)
+	}
+	if asCurl && operation.Plugin != nil {
+		return fmt.Errorf("Curl output is not supported for command '%s'", operation.Name)
+	}
+	if dryRun && operation.Plugin != nil && !operation.Plugin.Command().DryRun {
+		return fmt.Errorf("Dry run is not supported for command '%s'", operation.Name)
+	}
+	return nil
+}
+
+// startTrace starts the span of the executed command. Operations executed by
+// the batch command are recorded as part of the batch trace, otherwise a new
+// trace is started which is owned by the command.
+func (b CommandBuilder) startTrace(ctx context.Context, name string) (context.Context, *tracing.Tracer, *tracing.Span) {
+	if tracing.SpanFromContext(ctx) != nil {
+		ctx, span := tracing.Start(ctx, name, tracing.SpanKindInternal)
+		return ctx, nil, span
+	}
+	tracer := tracing.NewTracer()
+	ctx, span := tracer.Start(ctx, name)
+	return ctx, tracer, span
+}
+
+// finishTrace ends the span of the command and writes the trace to the
+// trace file in case the command owns the trace.
+func (b CommandBuilder) finishTrace(tracer *tracing.Tracer, span *tracing.Span, path string, err error) error {
+	span.End(err)
+	if tracer == nil || path == "" {
+		return err
+	}
+	writer := tracing.NewOtlpWriter(path, "uipathcli")
+	writeErr := writer.Write(tracer.Spans())
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// waitOptions reads the --wait arguments and looks up the probe operation
+// in the definition of the executed service.
+func (b CommandBuilder) waitOptions(context *cli.Context, definition parser.Definition) (*waitOptions, error) {
+	for _, name := range []string{waitFailFlagName, waitProgressFlagName, waitProbeFlagName} {
+		if context.String(name) != "" && context.String(waitFlagName) == "" {
+			return nil, fmt.Errorf("The --%s parameter requires --%s", name, waitFlagName)
 		}
-		if result {
-			resultWriter := b.outputWriter(b.StdOut, outputFormat, query)
-			_ = resultWriter.WriteResponse(outputWriter.Response())
-			return err
+	}
+	interval := context.Duration(waitIntervalFlagName)
+	if interval <= 0 {
+		return nil, fmt.Errorf("Invalid value for --%s: needs to be greater than 0", waitIntervalFlagName)
+	}
+	var probe *parser.Operation
+	probeName := context.String(waitProbeFlagName)
+	if probeName != "" {
+		probe = b.findOperation(definition, strings.Fields(probeName))
+		if probe == nil {
+			return nil, fmt.Errorf("Unknown operation '%s' for service '%s'", probeName, definition.Name)
+		}
+		if probe.Method != http.MethodGet || probe.Plugin != nil {
+			return nil, fmt.Errorf("The --%s operation '%s' needs to be a GET operation", waitProbeFlagName, probeName)
+		}
+		if len(probePathParameters(*probe)) != 1 {
+			return nil, fmt.Errorf("The --%s operation '%s' needs to have exactly one path parameter", waitProbeFlagName, probeName)
 		}
-		logger.LogError("Condition is not met yet. Waiting...\n")
-		time.Sleep(1 * time.Second)
 	}
-	return errors.New("Timed out waiting for condition")
+	return &waitOptions{
+		Condition:     context.String(waitFlagName),
+		FailCondition: context.String(waitFailFlagName),
+		Progress:      context.String(waitProgressFlagName),
+		Timeout:       time.Duration(context.Int(waitTimeoutFlagName)) * time.Second,
+		Interval:      interval,
+		Backoff:       context.Bool(waitBackoffFlagName),
+		Probe:         probe,
+		ProbeId:       context.String(waitProbeIdFlagName),
+	}, nil
 }
 
-func (b CommandBuilder) evaluateWaitCondition(response output.ResponseInfo, wait string) (bool, error) {
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return false, nil
+func (b CommandBuilder) timeout(config config.Config, context *cli.Context) time.Duration {
+	if context.IsSet(timeoutFlagName) {
+		return context.Duration(timeoutFlagName)
 	}
-	var data interface{}
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		return false, nil
+	return config.Timeout
+}
+
+func (b CommandBuilder) cancellationContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
 	}
-	transformer := output.NewJmesPathTransformer(wait)
-	result, err := transformer.Execute(data)
-	if err != nil {
-		return false, err
+	return context.WithCancel(ctx)
+}
+
+func (b CommandBuilder) cancellationError(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
 	}
-	value, ok := result.(bool)
-	if !ok {
-		return false, fmt.Errorf("Error in wait condition: JMESPath expression needs to return boolean")
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return newCancelledError(fmt.Sprintf("Operation timed out after %s", timeout), ctx.Err())
 	}
-	return value, nil
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return newCancelledError("Operation cancelled", ctx.Err())
+	}
+	return err
 }
 
-func (b CommandBuilder) execute(executionContext executor.ExecutionContext, outputFormat string, query string, outputWriter output.OutputWriter) error {
+func (b CommandBuilder) execute(executionContext executor.ExecutionContext, outputFormat string, query string, outFile string, outputWriter output.OutputWriter) error {
 	var wg sync.WaitGroup
 	wg.Add(3)
 	reader, writer := io.Pipe()
@@ -450,10 +949,15 @@ func (b CommandBuilder) execute(executionContext executor.ExecutionContext, outp
 		defer wg.Done()
 		defer writer.Close()
 		defer errorWriter.Close()
-		if outputWriter == nil {
+		if outputWriter == nil && outFile != "" {
+			outputWriter = b.fileOutputWriter(writer, outFile, outputFormat, query)
+		} else if outputWriter == nil {
 			outputWriter = b.outputWriter(writer, outputFormat, query)
 		}
 		logger := b.logger(executionContext, errorWriter)
+		if executionContext.Network.Timing != nil {
+			executionContext.Network.Timing = executionContext.Network.Timing.WithWriter(errorWriter)
+		}
 		err = b.executeCommand(executionContext, outputWriter, logger)
 	}()
 
@@ -461,6 +965,12 @@ func (b CommandBuilder) execute(executionContext executor.ExecutionContext, outp
 	return err
 }
 
+// executeInto executes the operation and writes the response into the given
+// output writer, e.g. to evaluate it before it is printed.
+func (b CommandBuilder) executeInto(executionContext executor.ExecutionContext, outputWriter output.OutputWriter) error {
+	return b.execute(executionContext, outputFormatJson, "", "", outputWriter)
+}
+
 func (b CommandBuilder) createCategoryCommand(operation parser.Operation) *cli.Command {
 	return &cli.Command{
 		Name:        operation.Category.Name,
@@ -473,9 +983,9 @@ func (b CommandBuilder) createCategoryCommand(operation parser.Operation) *cli.C
 	}
 }
 
-func (b CommandBuilder) createServiceCommandCategory(operation parser.Operation, categories map[string]*cli.Command) (bool, *cli.Command) {
+func (b CommandBuilder) createServiceCommandCategory(definition parser.Definition, operation parser.Operation, categories map[string]*cli.Command) (bool, *cli.Command) {
 	isNewCategory := false
-	operationCommand := b.createOperationCommand(operation)
+	operationCommand := b.createOperationCommand(definition, operation)
 	command, found := categories[operation.Category.Name]
 	if !found {
 		command = b.createCategoryCommand(operation)
@@ -491,11 +1001,11 @@ func (b CommandBuilder) createServiceCommand(definition parser.Definition) *cli.
 	commands := []*cli.Command{}
 	for _, operation := range definition.Operations {
 		if operation.Category == nil {
-			command := b.createOperationCommand(operation)
+			command := b.createOperationCommand(definition, operation)
 			commands = append(commands, command)
 			continue
 		}
-		isNewCategory, command := b.createServiceCommandCategory(operation, categories)
+		isNewCategory, command := b.createServiceCommandCategory(definition, operation, categories)
 		if isNewCategory {
 			commands = append(commands, command)
 		}
@@ -528,9 +1038,8 @@ func (b CommandBuilder) createAutoCompleteEnableCommand() *cli.Command {
 		Description: "Enables auto complete in your shell",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     shellFlagName,
-				Usage:    fmt.Sprintf("%s, %s", powershellFlagValue, bashFlagValue),
-				Required: true,
+				Name:  shellFlagName,
+				Usage: fmt.Sprintf("%s, %s", powershellFlagValue, bashFlagValue),
 			},
 			&cli.StringFlag{
 				Name:   fileFlagName,
@@ -539,6 +1048,10 @@ func (b CommandBuilder) createAutoCompleteEnableCommand() *cli.Command {
 			b.HelpFlag(),
 		},
 		Action: func(context *cli.Context) error {
+			err := b.validateRequiredFlags(context, shellFlagName)
+			if err != nil {
+				return err
+			}
 			shell := context.String(shellFlagName)
 			filePath := context.String(fileFlagName)
 			handler := newAutoCompleteHandler()
@@ -559,13 +1072,16 @@ func (b CommandBuilder) createAutoCompleteCompleteCommand(version string) *cli.C
 		Description: "Returns the autocomplete suggestions",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "command",
-				Usage:    "The command to autocomplete",
-				Required: true,
+				Name:  "command",
+				Usage: "The command to autocomplete",
 			},
 			b.HelpFlag(),
 		},
 		Action: func(context *cli.Context) error {
+			err := b.validateRequiredFlags(context, "command")
+			if err != nil {
+				return err
+			}
 			commandText := context.String("command")
 			exclude := []string{}
 			for _, flagName := range predefinedFlags {
@@ -645,14 +1161,12 @@ func (b CommandBuilder) createConfigSetCommand() *cli.Command {
 	valueFlagName := "value"
 	flags := []cli.Flag{
 		&cli.StringFlag{
-			Name:     keyFlagName,
-			Usage:    "The key",
-			Required: true,
+			Name:  keyFlagName,
+			Usage: "The key",
 		},
 		&cli.StringFlag{
-			Name:     valueFlagName,
-			Usage:    "The value to set",
-			Required: true,
+			Name:  valueFlagName,
+			Usage: "The value to set",
 		},
 		&cli.StringFlag{
 			Name:    profileFlagName,
@@ -667,6 +1181,10 @@ func (b CommandBuilder) createConfigSetCommand() *cli.Command {
 		Description: "Set config parameters",
 		Flags:       flags,
 		Action: func(context *cli.Context) error {
+			err := b.validateRequiredFlags(context, keyFlagName, valueFlagName)
+			if err != nil {
+				return err
+			}
 			profileName := context.String(profileFlagName)
 			key := context.String(keyFlagName)
 			value := context.String(valueFlagName)
@@ -681,6 +1199,59 @@ func (b CommandBuilder) createConfigSetCommand() *cli.Command {
 	}
 }
 
+func (b CommandBuilder) hasOperation(definition parser.Definition, names []string) bool {
+	return b.findOperation(definition, names) != nil
+}
+
+func (b CommandBuilder) findOperation(definition parser.Definition, names []string) *parser.Operation {
+	for _, operation := range definition.Operations {
+		if operation.Category == nil && len(names) == 1 && operation.Name == names[0] {
+			return &operation
+		}
+		if operation.Category != nil && len(names) == 2 && operation.Category.Name == names[0] && operation.Name == names[1] {
+			return &operation
+		}
+	}
+	return nil
+}
+
+func (b CommandBuilder) createCacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "cache",
+		Description: "Commands to manage the HTTP response cache",
+		Flags: []cli.Flag{
+			b.HelpFlag(),
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:        "info",
+				Description: "Shows the location and size of the response cache",
+				Flags: []cli.Flag{
+					b.HelpFlag(),
+				},
+				Action: func(context *cli.Context) error {
+					handler := newCacheCommandHandler(b.StdOut, cache.NewFileResponseCache())
+					return handler.Info()
+				},
+				HideHelp: true,
+			},
+			{
+				Name:        "clear",
+				Description: "Removes all cached responses",
+				Flags: []cli.Flag{
+					b.HelpFlag(),
+				},
+				Action: func(context *cli.Context) error {
+					handler := newCacheCommandHandler(b.StdOut, cache.NewFileResponseCache())
+					return handler.Clear()
+				},
+				HideHelp: true,
+			},
+		},
+		HideHelp: true,
+	}
+}
+
 func (b CommandBuilder) loadDefinitions(args []string, version string) ([]parser.Definition, error) {
 	if len(args) <= 1 || strings.HasPrefix(args[1], "--") {
 		return b.DefinitionProvider.Index(version)
@@ -740,7 +1311,9 @@ func (b CommandBuilder) Create(args []string) ([]*cli.Command, error) {
 	servicesCommands := b.createServiceCommands(definitions)
 	autocompleteCommand := b.createAutoCompleteCommand(version)
 	configCommand := b.createConfigCommand()
-	commands := append(servicesCommands, autocompleteCommand, configCommand)
+	batchCommand := b.createBatchCommand()
+	cacheCommand := b.createCacheCommand()
+	commands := append(servicesCommands, autocompleteCommand, configCommand, batchCommand, cacheCommand)
 	return commands, nil
 }
 
@@ -785,9 +1358,33 @@ func (b CommandBuilder) CreateDefaultFlags(hidden bool) []cli.Flag {
 			Value:   false,
 			Hidden:  hidden,
 		},
+		&cli.StringFlag{
+			Name:    proxyFlagName,
+			Usage:   "Proxy URL, overrides HTTPS_PROXY and NO_PROXY",
+			EnvVars: []string{"UIPATH_PROXY"},
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:    caCertFlagName,
+			Usage:   "PEM file with additional trusted CA certificates",
+			EnvVars: []string{"UIPATH_CA_CERT"},
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:    clientCertFlagName,
+			Usage:   "PEM file with the client certificate for mutual TLS",
+			EnvVars: []string{"UIPATH_CLIENT_CERT"},
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:    clientKeyFlagName,
+			Usage:   "PEM file with the private key of the client certificate",
+			EnvVars: []string{"UIPATH_CLIENT_KEY"},
+			Hidden:  hidden,
+		},
 		&cli.StringFlag{
 			Name:    outputFormatFlagName,
-			Usage:   fmt.Sprintf("Set output format: %s (default), %s", outputFormatJson, outputFormatText),
+			Usage:   fmt.Sprintf("Set output format: %s (default), %s, %s", outputFormatJson, outputFormatText, outputFormatCurl),
 			EnvVars: []string{"UIPATH_OUTPUT"},
 			Value:   "",
 			Hidden:  hidden,
@@ -810,12 +1407,208 @@ func (b CommandBuilder) CreateDefaultFlags(hidden bool) []cli.Flag {
 			Value:  30,
 			Hidden: hidden,
 		},
+		&cli.DurationFlag{
+			Name:   waitIntervalFlagName,
+			Usage:  "Delay between polls while waiting for condition",
+			Value:  1 * time.Second,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:   waitBackoffFlagName,
+			Usage:  "Double the delay between polls up to 30s",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   waitFailFlagName,
+			Usage:  "Stops waiting when the provided condition (JMESPath expression) is met",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   waitProgressFlagName,
+			Usage:  "Prints the result of the JMESPath expression while waiting",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   waitProbeFlagName,
+			Usage:  "GET operation which is polled instead of re-sending the request",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   waitProbeIdFlagName,
+			Usage:  "JMESPath expression extracting the id for --wait-probe from the response",
+			Value:  "id || Id",
+			Hidden: hidden,
+		},
 		&cli.StringFlag{
 			Name:   fileFlagName,
 			Usage:  "Provide input from file (use - for stdin)",
 			Value:  "",
 			Hidden: hidden,
 		},
+		&cli.BoolFlag{
+			Name:   paginateFlagName,
+			Usage:  "Fetch all pages of OData list operations",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.IntFlag{
+			Name:   pageSizeFlagName,
+			Usage:  "Number of items to request per page when paginating",
+			Value:  100,
+			Hidden: hidden,
+		},
+		&cli.IntFlag{
+			Name:   maxItemsFlagName,
+			Usage:  "Maximum number of items to return when paginating",
+			Value:  0,
+			Hidden: hidden,
+		},
+		&cli.IntFlag{
+			Name:    maxRetriesFlagName,
+			Usage:   fmt.Sprintf("Maximum number of retries for failed requests (default: %d)", utils.DefaultMaxAttempts-1),
+			EnvVars: []string{"UIPATH_MAX_RETRIES"},
+			Hidden:  hidden,
+		},
+		&cli.DurationFlag{
+			Name:   retryBackoffFlagName,
+			Usage:  fmt.Sprintf("Initial delay between retries which doubles with every attempt (default: %s)", utils.DefaultBackoff),
+			Hidden: hidden,
+		},
+		&cli.DurationFlag{
+			Name:   retryMaxBackoffFlagName,
+			Usage:  fmt.Sprintf("Maximum delay between retries (default: %s)", utils.DefaultMaxBackoff),
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:   retryJitterFlagName,
+			Usage:  "Randomize the delay between retries",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   retryStatusCodesFlagName,
+			Usage:  "Comma-separated list of HTTP status codes to retry (default: 429, 5xx)",
+			Value:  "",
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:    failFlagName,
+			Usage:   "Return a non-zero exit code when the service responds with a non-2xx status code",
+			EnvVars: []string{"UIPATH_FAIL"},
+			Value:   false,
+			Hidden:  hidden,
+		},
+		&cli.BoolFlag{
+			Name:   noFailFlagName,
+			Usage:  "Return exit code 0 even when the service responds with a non-2xx status code",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.DurationFlag{
+			Name:    timeoutFlagName,
+			Usage:   "Maximum time the command is allowed to take, e.g. 30s or 5m (default: no timeout)",
+			EnvVars: []string{"UIPATH_TIMEOUT"},
+			Hidden:  hidden,
+		},
+		&cli.BoolFlag{
+			Name:   dryRunFlagName,
+			Usage:  "Print the resolved request instead of sending it",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:   asCurlFlagName,
+			Usage:  "Print an equivalent curl command instead of sending the request",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   outFileFlagName,
+			Usage:  "Stream the response body to the given file, use - for raw standard output",
+			Value:  "",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:    recordFlagName,
+			Usage:   "Record all HTTP requests and responses in the given directory",
+			EnvVars: []string{"UIPATH_RECORD"},
+			Value:   "",
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:    replayFlagName,
+			Usage:   "Replay the HTTP responses recorded in the given directory instead of sending requests",
+			EnvVars: []string{"UIPATH_REPLAY"},
+			Value:   "",
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:   forEachFlagName,
+			Usage:  "Execute the operation for every value from standard input or --for-each-file using the given parameter",
+			Value:  "",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   forEachFileFlagName,
+			Usage:  "File with newline-separated or json array values for --for-each",
+			Value:  "",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:   forEachFormatFlagName,
+			Usage:  fmt.Sprintf("Set --for-each output format: %s (default), %s", forEachFormatJson, forEachFormatNdjson),
+			Value:  "",
+			Hidden: hidden,
+		},
+		&cli.IntFlag{
+			Name:   forEachConcurrencyFlagName,
+			Usage:  "Number of --for-each operations to execute in parallel",
+			Value:  1,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:    cacheFlagName,
+			Usage:   "Cache GET responses and revalidate them using ETag and Last-Modified",
+			EnvVars: []string{"UIPATH_CACHE"},
+			Value:   false,
+			Hidden:  hidden,
+		},
+		&cli.BoolFlag{
+			Name:   noCacheFlagName,
+			Usage:  "Do not use the response cache even when it is enabled in the profile",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:   mergeFlagName,
+			Usage:  "Merge the JSON body from --file with the body parameters provided as arguments",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:   compressRequestFlagName,
+			Usage:  "Compress JSON and file request bodies using gzip",
+			Value:  false,
+			Hidden: hidden,
+		},
+		&cli.BoolFlag{
+			Name:    timingFlagName,
+			Usage:   "Print the timing breakdown of every request",
+			EnvVars: []string{"UIPATH_TIMING"},
+			Value:   false,
+			Hidden:  hidden,
+		},
+		&cli.StringFlag{
+			Name:   requestIdFlagName,
+			Usage:  "Request id sent in the x-request-id header",
+			Hidden: hidden,
+		},
+		&cli.StringFlag{
+			Name:    traceFileFlagName,
+			Usage:   "Write the trace spans to the given file in OTLP/JSON format",
+			EnvVars: []string{"UIPATH_TRACE_FILE"},
+			Hidden:  hidden,
+		},
 		b.VersionFlag(hidden),
 	}
 }
@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/UiPath/uipathcli/config"
+	"github.com/UiPath/uipathcli/network"
 )
 
 // The ConfigCommandHandler implements commands for configuring the CLI.
@@ -65,6 +68,17 @@ func (h ConfigCommandHandler) setConfigValue(config *config.Config, key string,
 		}
 		config.SetInsecure(insecure)
 		return nil
+	} else if key == "proxy" {
+		return config.SetProxy(value)
+	} else if key == "caCert" {
+		config.SetCaCert(value)
+		return nil
+	} else if key == "clientCert" {
+		config.SetClientCert(value)
+		return nil
+	} else if key == "clientKey" {
+		config.SetClientKey(value)
+		return nil
 	} else if key == "debug" {
 		debug, err := h.convertToBool(value)
 		if err != nil {
@@ -72,6 +86,83 @@ func (h ConfigCommandHandler) setConfigValue(config *config.Config, key string,
 		}
 		config.SetDebug(debug)
 		return nil
+	} else if key == "fail" {
+		fail, err := h.convertToBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'fail': %w", err)
+		}
+		config.SetFail(fail)
+		return nil
+	} else if key == "timeout" {
+		timeout, err := h.convertToDuration(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'timeout': %w", err)
+		}
+		config.SetTimeout(timeout)
+		return nil
+	} else if key == "cache" {
+		cache, err := h.convertToBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'cache': %w", err)
+		}
+		config.SetCache(cache)
+		return nil
+	} else if key == "retry.maxRetries" {
+		maxRetries, err := h.convertToInt(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'retry.maxRetries': %w", err)
+		}
+		config.SetRetryMaxRetries(maxRetries)
+		return nil
+	} else if key == "retry.backoff" {
+		backoff, err := h.convertToDuration(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'retry.backoff': %w", err)
+		}
+		config.SetRetryBackoff(backoff)
+		return nil
+	} else if key == "retry.maxBackoff" {
+		maxBackoff, err := h.convertToDuration(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'retry.maxBackoff': %w", err)
+		}
+		config.SetRetryMaxBackoff(maxBackoff)
+		return nil
+	} else if key == "retry.jitter" {
+		jitter, err := h.convertToBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'retry.jitter': %w", err)
+		}
+		config.SetRetryJitter(jitter)
+		return nil
+	} else if key == "retry.statusCodes" {
+		statusCodes, err := h.convertToIntArray(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'retry.statusCodes': %w", err)
+		}
+		config.SetRetryStatusCodes(statusCodes)
+		return nil
+	} else if key == "connection.maxIdleConns" {
+		maxIdleConns, err := h.convertToInt(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'connection.maxIdleConns': %w", err)
+		}
+		config.SetConnectionMaxIdleConns(maxIdleConns)
+		return nil
+	} else if key == "connection.maxIdleConnsPerHost" {
+		maxIdleConnsPerHost, err := h.convertToInt(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'connection.maxIdleConnsPerHost': %w", err)
+		}
+		config.SetConnectionMaxIdleConnsPerHost(maxIdleConnsPerHost)
+		return nil
+	} else if key == "connection.idleTimeout" {
+		idleTimeout, err := h.convertToDuration(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for 'connection.idleTimeout': %w", err)
+		}
+		config.SetConnectionIdleTimeout(idleTimeout)
+		return nil
 	} else if key == "auth.grantType" {
 		config.SetAuthGrantType(value)
 		return nil
@@ -84,6 +175,13 @@ func (h ConfigCommandHandler) setConfigValue(config *config.Config, key string,
 	} else if h.isParameterKey(keyParts) {
 		config.SetParameter(keyParts[1], value)
 		return nil
+	} else if h.isRateLimitKey(keyParts) {
+		_, err := network.ParseRateLimit(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for '%s': %w", key, err)
+		}
+		config.SetRateLimit(keyParts[1], value)
+		return nil
 	} else if h.isAuthPropertyKey(keyParts) {
 		config.SetAuthProperty(keyParts[2], value)
 		return nil
@@ -99,6 +197,10 @@ func (h ConfigCommandHandler) isParameterKey(keyParts []string) bool {
 	return len(keyParts) == 2 && keyParts[0] == "parameter"
 }
 
+func (h ConfigCommandHandler) isRateLimitKey(keyParts []string) bool {
+	return len(keyParts) == 2 && keyParts[0] == "rateLimit"
+}
+
 func (h ConfigCommandHandler) isAuthPropertyKey(keyParts []string) bool {
 	return len(keyParts) == 3 && keyParts[0] == "auth" && keyParts[1] == "properties"
 }
@@ -113,6 +215,34 @@ func (h ConfigCommandHandler) convertToBool(value string) (bool, error) {
 	return false, fmt.Errorf("Invalid boolean value: %s", value)
 }
 
+func (h ConfigCommandHandler) convertToInt(value string) (int, error) {
+	result, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("Invalid integer value: %s", value)
+	}
+	return result, nil
+}
+
+func (h ConfigCommandHandler) convertToIntArray(value string) ([]int, error) {
+	result := []int{}
+	for _, item := range strings.Split(value, ",") {
+		number, err := h.convertToInt(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, number)
+	}
+	return result, nil
+}
+
+func (h ConfigCommandHandler) convertToDuration(value string) (time.Duration, error) {
+	result, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("Invalid duration value: %s", value)
+	}
+	return result, nil
+}
+
 func (h ConfigCommandHandler) Configure(auth string, profileName string) error {
 	switch auth {
 	case CredentialsAuth:
@@ -0,0 +1,19 @@
+package commandline
+
+// ConfigError is returned when the configuration file cannot be loaded
+// or the selected profile does not exist.
+type ConfigError struct {
+	err error
+}
+
+func (e ConfigError) Error() string {
+	return e.err.Error()
+}
+
+func (e ConfigError) Unwrap() error {
+	return e.err
+}
+
+func newConfigError(err error) *ConfigError {
+	return &ConfigError{err}
+}
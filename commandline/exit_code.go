@@ -0,0 +1,87 @@
+package commandline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/UiPath/uipathcli/auth"
+	"github.com/UiPath/uipathcli/plugin"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// Exit codes returned by the CLI process.
+const (
+	ExitCodeSuccess           = 0
+	ExitCodeError             = 1
+	ExitCodeValidationError   = 2
+	ExitCodeAuthError         = 3
+	ExitCodeClientError       = 4
+	ExitCodeServerError       = 5
+	ExitCodeWaitTimeout       = 6
+	ExitCodeTimeout           = 7
+	ExitCodeOperationFailed   = 8
+	ExitCodeOperationStopped  = 9
+	ExitCodeCancelled         = 130
+	ExitCodeConfigError       = 131
+	ExitCodePluginConfigError = 132
+)
+
+// ExitCode converts the error returned by Cli.Run into the process exit code
+// so that scripts can distinguish between the different failure classes.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.exitCode
+	}
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		return ExitCodeConfigError
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ExitCodeValidationError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitCodeTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitCodeCancelled
+	}
+	var waitTimeoutErr *WaitTimeoutError
+	if errors.As(err, &waitTimeoutErr) {
+		return ExitCodeWaitTimeout
+	}
+	var operationErr *plugin.OperationError
+	if errors.As(err, &operationErr) {
+		if operationErr.Stopped {
+			return ExitCodeOperationStopped
+		}
+		return ExitCodeOperationFailed
+	}
+	var authErr *auth.AuthenticationError
+	if errors.As(err, &authErr) {
+		return ExitCodeAuthError
+	}
+	var statusErr *utils.HttpStatusError
+	if errors.As(err, &statusErr) {
+		return exitCodeFromStatus(statusErr.StatusCode)
+	}
+	return ExitCodeError
+}
+
+func exitCodeFromStatus(statusCode int) int {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return ExitCodeAuthError
+	}
+	if statusCode >= 500 {
+		return ExitCodeServerError
+	}
+	if statusCode >= 400 {
+		return ExitCodeClientError
+	}
+	return ExitCodeError
+}
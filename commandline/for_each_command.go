@@ -0,0 +1,97 @@
+package commandline
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/UiPath/uipathcli/executor"
+	"github.com/UiPath/uipathcli/parser"
+	"github.com/UiPath/uipathcli/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// forEachParameter looks up the parameter which receives the --for-each values
+// and returns the remaining parameters which still need to be validated.
+func (b CommandBuilder) forEachParameter(parameters []parser.Parameter, name string) (*parser.Parameter, []parser.Parameter, error) {
+	if name == "" {
+		return nil, parameters, nil
+	}
+	for i, parameter := range parameters {
+		if parameter.Name == name {
+			remaining := append(append([]parser.Parameter{}, parameters[:i]...), parameters[i+1:]...)
+			return &parameters[i], remaining, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("Unknown parameter '%s' for --%s", name, forEachFlagName)
+}
+
+func (b CommandBuilder) forEachValues(context *cli.Context) ([]string, error) {
+	handler := newForEachHandler(b.StdOut, b.StdErr, nil)
+	var stream utils.Stream = b.Input
+	if path := context.String(forEachFileFlagName); path != "" {
+		stream = utils.NewFileStream(path)
+	} else if context.String(fileFlagName) == FromStdIn {
+		return nil, fmt.Errorf("The --%s values and --%s cannot both be read from standard input, use --%s instead", forEachFlagName, fileFlagName, forEachFileFlagName)
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("The --%s parameter requires values from standard input or --%s", forEachFlagName, forEachFileFlagName)
+	}
+	reader, err := stream.Data()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return handler.ReadValues(reader)
+}
+
+func (b CommandBuilder) forEachFormat(context *cli.Context) string {
+	format := context.String(forEachFormatFlagName)
+	if format == "" {
+		return forEachFormatJson
+	}
+	return format
+}
+
+// executeForEach executes the operation once for every value. Non-2xx
+// responses always fail the individual call.
+func (b CommandBuilder) executeForEach(executionContext executor.ExecutionContext, parameter parser.Parameter, values []string, concurrency int, format string, outputFormat string, query string) error {
+	run := func(ctx context.Context, value string, stdOut io.Writer, stdErr io.Writer) error {
+		parameters, err := b.forEachExecutionParameters(executionContext.Parameters, parameter, value)
+		if err != nil {
+			return newValidationError(err)
+		}
+		itemContext := executionContext
+		itemContext.Parameters = parameters
+		itemContext.Fail = true
+		builder := b
+		builder.StdOut = stdOut
+		builder.StdErr = stdErr
+		return builder.execute(itemContext, outputFormat, query, "", nil)
+	}
+	handler := newForEachHandler(b.StdOut, b.StdErr, run)
+	return handler.Execute(executionContext.Context, values, concurrency, format)
+}
+
+func (b CommandBuilder) forEachExecutionParameters(parameters executor.ExecutionParameters, parameter parser.Parameter, value string) (executor.ExecutionParameters, error) {
+	typeConverter := newTypeConverter()
+	var converted interface{}
+	var err error
+	if parameter.IsArray() {
+		converted, err = typeConverter.ConvertArray([]string{value}, parameter)
+	} else {
+		converted, err = typeConverter.Convert(value, parameter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := []executor.ExecutionParameter{}
+	for _, p := range parameters {
+		if p.Name != parameter.FieldName || p.In != parameter.In {
+			result = append(result, p)
+		}
+	}
+	result = append(result, *executor.NewExecutionParameter(parameter.FieldName, converted, parameter.In))
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package commandline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// forEachRunFunc executes the operation once with the given value substituted
+// into the --for-each parameter.
+type forEachRunFunc func(ctx context.Context, value string, stdOut io.Writer, stdErr io.Writer) error
+
+// The forEachHandler fans out a single operation over a list of values which
+// are read from standard input or a file when --for-each is provided.
+//
+// The values are either newline-separated or a json array. The results of all
+// calls are aggregated into a json array or printed as NDJSON as soon as each
+// call completes.
+type forEachHandler struct {
+	StdOut io.Writer
+	StdErr io.Writer
+	Run    forEachRunFunc
+}
+
+type forEachResult struct {
+	Index    int         `json:"index"`
+	Value    string      `json:"value"`
+	Status   string      `json:"status"`
+	ExitCode int         `json:"exitCode"`
+	Body     interface{} `json:"body,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+const forEachFormatJson = "json"
+const forEachFormatNdjson = "ndjson"
+
+func (h forEachHandler) Execute(ctx context.Context, values []string, concurrency int, format string) error {
+	if concurrency < 1 {
+		return newValidationError(fmt.Errorf("Invalid value for for-each-concurrency: '%d', must be at least 1", concurrency))
+	}
+	if format != forEachFormatJson && format != forEachFormatNdjson {
+		return newValidationError(fmt.Errorf("Invalid for-each format '%s', allowed values: %s, %s", format, forEachFormatJson, forEachFormatNdjson))
+	}
+
+	indexChan := make(chan int)
+	resultChan := make(chan forEachResult)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexChan {
+				resultChan <- h.execute(ctx, index, values[index])
+			}
+		}()
+	}
+	go func() {
+		for index := range values {
+			indexChan <- index
+		}
+		close(indexChan)
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	results := make([]forEachResult, len(values))
+	for result := range resultChan {
+		results[result.Index] = result
+		if format == forEachFormatNdjson {
+			data, _ := json.Marshal(result)
+			fmt.Fprintln(h.StdOut, string(data))
+		}
+	}
+	if format == forEachFormatJson {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Error formatting for-each results: %w", err)
+		}
+		fmt.Fprintln(h.StdOut, string(data))
+	}
+
+	failed := 0
+	var firstFailure *forEachResult
+	for i, result := range results {
+		if result.Status == batchStatusFailed {
+			failed++
+			if firstFailure == nil {
+				firstFailure = &results[i]
+			}
+		}
+	}
+	if firstFailure != nil {
+		return newBatchError(fmt.Sprintf("%d of %d for-each operations failed", failed, len(values)), firstFailure.ExitCode)
+	}
+	return nil
+}
+
+func (h forEachHandler) execute(ctx context.Context, index int, value string) forEachResult {
+	result := forEachResult{
+		Index:  index,
+		Value:  value,
+		Status: batchStatusSucceeded,
+	}
+	if ctx.Err() != nil {
+		result.Status = batchStatusFailed
+		result.ExitCode = ExitCode(ctx.Err())
+		result.Error = "Operation cancelled"
+		return result
+	}
+
+	stdOut := &bytes.Buffer{}
+	stdErr := &bytes.Buffer{}
+	err := h.Run(ctx, value, stdOut, stdErr)
+	if stdErr.Len() > 0 {
+		_, _ = h.StdErr.Write(stdErr.Bytes())
+	}
+	result.Body = parseResultBody(stdOut.Bytes())
+	if err != nil {
+		result.Status = batchStatusFailed
+		result.ExitCode = ExitCode(err)
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// ReadValues parses the newline-separated list or json array of values.
+func (h forEachHandler) ReadValues(reader io.Reader) ([]string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading for-each values: %w", err)
+	}
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "[") {
+		return h.parseJsonArray([]byte(text))
+	}
+	values := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		value := strings.TrimSpace(line)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (h forEachHandler) parseJsonArray(data []byte) ([]string, error) {
+	var items []interface{}
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing for-each values: %w", err)
+	}
+	values := []string{}
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			values = append(values, v)
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		case nil:
+			continue
+		default:
+			value, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing for-each values: %w", err)
+			}
+			values = append(values, string(value))
+		}
+	}
+	return values, nil
+}
+
+func newForEachHandler(stdOut io.Writer, stdErr io.Writer, run forEachRunFunc) *forEachHandler {
+	return &forEachHandler{stdOut, stdErr, run}
+}
@@ -0,0 +1,42 @@
+package commandline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadValuesSplitsLines(t *testing.T) {
+	handler := newForEachHandler(nil, nil, nil)
+
+	values, err := handler.ReadValues(strings.NewReader("1\r\n  2 \n\n3"))
+	if err != nil {
+		t.Errorf("Unexpected error, got: %v", err)
+	}
+	expected := []string{"1", "2", "3"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected values %v, but got: %v", expected, values)
+	}
+}
+
+func TestReadValuesParsesJsonArray(t *testing.T) {
+	handler := newForEachHandler(nil, nil, nil)
+
+	values, err := handler.ReadValues(strings.NewReader(`["a", 2, 1.5, true, null, {"id": 1}]`))
+	if err != nil {
+		t.Errorf("Unexpected error, got: %v", err)
+	}
+	expected := []string{"a", "2", "1.5", "true", `{"id":1}`}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected values %v, but got: %v", expected, values)
+	}
+}
+
+func TestReadValuesInvalidJsonArrayReturnsError(t *testing.T) {
+	handler := newForEachHandler(nil, nil, nil)
+
+	_, err := handler.ReadValues(strings.NewReader(`["a",`))
+	if err == nil || !strings.HasPrefix(err.Error(), "Error parsing for-each values") {
+		t.Errorf("Expected parsing error, but got: %v", err)
+	}
+}
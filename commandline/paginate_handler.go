@@ -0,0 +1,201 @@
+package commandline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/UiPath/uipathcli/executor"
+	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/parser"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+const odataTopParameterName = "$top"
+const odataSkipParameterName = "$skip"
+const odataValueProperty = "value"
+const odataCountProperty = "@odata.count"
+
+// paginateRunFunc executes the operation for a single page and writes the
+// response into the given output writer.
+type paginateRunFunc func(context executor.ExecutionContext, writer output.OutputWriter) error
+
+// The paginateHandler fetches all pages of an OData list operation when
+// --paginate is provided.
+//
+// The pages are requested using the $top and $skip query parameters and the
+// items of all pages are merged into the value array of the first page. The
+// merged result is written to the Output once all pages are fetched or a
+// later page fails.
+type paginateHandler struct {
+	Output output.OutputWriter
+	Run    paginateRunFunc
+}
+
+func (h paginateHandler) Execute(context executor.ExecutionContext, pageSize int, maxItems int) error {
+	if pageSize <= 0 {
+		return newValidationError(fmt.Errorf("Invalid value for --%s: page size needs to be greater than 0", pageSizeFlagName))
+	}
+	skip := h.skipParameter(context.Parameters)
+	top, found := h.topParameter(context.Parameters)
+	if found && (maxItems <= 0 || top < maxItems) {
+		maxItems = top
+	}
+	var result map[string]interface{}
+	items := []interface{}{}
+	for {
+		top := pageSize
+		if maxItems > 0 && maxItems-len(items) < top {
+			top = maxItems - len(items)
+		}
+		outputWriter := output.NewMemoryOutputWriter()
+		pageContext := context
+		pageContext.Parameters = h.pageParameters(context.Parameters, top, skip)
+		err := h.Run(pageContext, outputWriter)
+		response := outputWriter.Response()
+		if err != nil && result != nil {
+			_ = h.writePages(result, items)
+			return err
+		}
+		if err != nil {
+			if response.StatusCode != 0 {
+				_ = h.Output.WriteResponse(response)
+			}
+			return err
+		}
+		if result != nil && (response.StatusCode < 200 || response.StatusCode >= 300) {
+			_ = h.writePages(result, items)
+			return h.statusError(response)
+		}
+		page, values := h.parsePage(response)
+		if values == nil && result != nil {
+			err = h.writePages(result, items)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("Pagination stopped after %d items: the page at $skip=%d does not contain a '%s' array", len(items), skip, odataValueProperty)
+		}
+		if values == nil {
+			return h.Output.WriteResponse(outputWriter.Response())
+		}
+		if result == nil {
+			result = page
+		}
+		items = append(items, values...)
+		skip += len(values)
+		if h.lastPage(page, len(values), top, skip) || (maxItems > 0 && len(items) >= maxItems) {
+			break
+		}
+	}
+	return h.writePages(result, items)
+}
+
+func (h paginateHandler) statusError(response output.ResponseInfo) error {
+	body, _ := io.ReadAll(response.Body)
+	return utils.NewHttpStatusError(response.StatusCode, fmt.Errorf("Service returned status code '%v' and body '%v'", response.StatusCode, string(body)))
+}
+
+// writePages writes the merged items of all pages fetched so far. It is also
+// used when a later page fails so that the already collected items are not
+// lost.
+func (h paginateHandler) writePages(result map[string]interface{}, items []interface{}) error {
+	result[odataValueProperty] = items
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("Error merging pages: %w", err)
+	}
+	response := output.NewResponseInfo(http.StatusOK, "200 OK", "", map[string][]string{}, bytes.NewReader(body))
+	return h.Output.WriteResponse(*response)
+}
+
+// topParameter returns the $top value provided by the user which limits the
+// total number of items when paginating.
+func (h paginateHandler) topParameter(parameters executor.ExecutionParameters) (int, bool) {
+	for _, parameter := range parameters.Query() {
+		if parameter.Name == odataTopParameterName {
+			if value, ok := parameter.Value.(int); ok && value > 0 {
+				return value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (h paginateHandler) skipParameter(parameters executor.ExecutionParameters) int {
+	for _, parameter := range parameters.Query() {
+		if parameter.Name == odataSkipParameterName {
+			if value, ok := parameter.Value.(int); ok {
+				return value
+			}
+		}
+	}
+	return 0
+}
+
+func (h paginateHandler) pageParameters(parameters executor.ExecutionParameters, top int, skip int) executor.ExecutionParameters {
+	result := []executor.ExecutionParameter{}
+	for _, parameter := range parameters {
+		if parameter.In == parser.ParameterInQuery && (parameter.Name == odataTopParameterName || parameter.Name == odataSkipParameterName) {
+			continue
+		}
+		result = append(result, parameter)
+	}
+	result = append(result, *executor.NewExecutionParameter(odataTopParameterName, top, parser.ParameterInQuery))
+	result = append(result, *executor.NewExecutionParameter(odataSkipParameterName, skip, parser.ParameterInQuery))
+	return result
+}
+
+func (h paginateHandler) parsePage(response output.ResponseInfo) (map[string]interface{}, []interface{}) {
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, nil
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil
+	}
+	var page map[string]interface{}
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return nil, nil
+	}
+	values, ok := page[odataValueProperty].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return page, values
+}
+
+// lastPage uses the total @odata.count when available because services may
+// return less items than requested, e.g. orchestrator caps the $top value.
+func (h paginateHandler) lastPage(page map[string]interface{}, count int, top int, skip int) bool {
+	if count == 0 {
+		return true
+	}
+	total, ok := page[odataCountProperty].(float64)
+	if ok {
+		return skip >= int(total)
+	}
+	return count < top
+}
+
+// supportsPagination checks whether the operation accepts the $top and $skip
+// query parameters which are needed to request the individual pages.
+func supportsPagination(operation parser.Operation) bool {
+	top := false
+	skip := false
+	for _, parameter := range operation.Parameters {
+		if parameter.In == parser.ParameterInQuery && parameter.FieldName == odataTopParameterName {
+			top = true
+		}
+		if parameter.In == parser.ParameterInQuery && parameter.FieldName == odataSkipParameterName {
+			skip = true
+		}
+	}
+	return top && skip
+}
+
+func newPaginateHandler(outputWriter output.OutputWriter, run paginateRunFunc) *paginateHandler {
+	return &paginateHandler{outputWriter, run}
+}
@@ -0,0 +1,51 @@
+package commandline
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/UiPath/uipathcli/executor"
+	"github.com/UiPath/uipathcli/output"
+)
+
+func TestPaginateMergesItemsUntilCountIsReached(t *testing.T) {
+	requests := []string{}
+	run := func(context executor.ExecutionContext, writer output.OutputWriter) error {
+		top := context.Parameters.Query()[0].Value
+		skip := context.Parameters.Query()[1].Value
+		requests = append(requests, fmt.Sprintf("%v/%v", top, skip))
+		body := fmt.Sprintf(`{"@odata.count":3,"value":[%v,%v]}`, skip, skip.(int)+1)
+		if skip == 2 {
+			body = `{"@odata.count":3,"value":[2]}`
+		}
+		return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", "", map[string][]string{}, bytes.NewReader([]byte(body))))
+	}
+	result := output.NewMemoryOutputWriter()
+	handler := newPaginateHandler(result, run)
+
+	err := handler.Execute(executor.ExecutionContext{}, 5, 0)
+
+	if err != nil {
+		t.Errorf("Expected no error, but got: %v", err)
+	}
+	if fmt.Sprint(requests) != "[5/0 5/2]" {
+		t.Errorf("Expected two page requests, but got: %v", requests)
+	}
+	body := new(bytes.Buffer)
+	_, _ = body.ReadFrom(result.Response().Body)
+	if body.String() != `{"@odata.count":3,"value":[0,1,2]}` {
+		t.Errorf("Expected merged items, but got: %v", body.String())
+	}
+}
+
+func TestPaginateInvalidPageSizeReturnsValidationError(t *testing.T) {
+	handler := newPaginateHandler(output.NewMemoryOutputWriter(), nil)
+
+	err := handler.Execute(executor.ExecutionContext{}, 0, 0)
+
+	if ExitCode(err) != ExitCodeValidationError {
+		t.Errorf("Expected validation error, but got: %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package commandline
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -164,12 +166,19 @@ func (c typeConverter) initArray(name string, value interface{}, index int) ([]i
 	return c.initArrayItem(array, index)
 }
 
+// convertJsonToObject keeps numbers as json.Number so that large ids are sent
+// without losing precision.
 func (c typeConverter) convertJsonToObject(value string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
 	var data interface{}
-	err := json.Unmarshal([]byte(value), &data)
+	err := decoder.Decode(&data)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
 	return data, nil
 }
 
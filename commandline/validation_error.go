@@ -0,0 +1,19 @@
+package commandline
+
+// ValidationError is returned when the provided command line arguments
+// are missing or invalid.
+type ValidationError struct {
+	err error
+}
+
+func (e ValidationError) Error() string {
+	return e.err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.err
+}
+
+func newValidationError(err error) *ValidationError {
+	return &ValidationError{err}
+}
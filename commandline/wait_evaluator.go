@@ -0,0 +1,78 @@
+package commandline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/UiPath/uipathcli/output"
+)
+
+const waitStatusField = "_status"
+const waitHeadersField = "_headers"
+
+// waitEvaluator evaluates the JMESPath expressions of the --wait arguments.
+//
+// The expressions are evaluated on the response body. The status code and
+// the response headers with lowercase names are available in the _status and
+// _headers fields in case the body is a JSON object or empty.
+type waitEvaluator struct{}
+
+func (e waitEvaluator) Document(response output.ResponseInfo) interface{} {
+	var data interface{}
+	body, err := io.ReadAll(response.Body)
+	if err == nil && len(body) > 0 {
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			data = nil
+		}
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	headers := map[string]interface{}{}
+	for name, values := range response.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	object[waitStatusField] = float64(response.StatusCode)
+	object[waitHeadersField] = headers
+	return object
+}
+
+func (e waitEvaluator) Condition(document interface{}, expression string) (bool, error) {
+	result, err := e.Value(document, expression)
+	if err != nil {
+		return false, err
+	}
+	value, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("Error in wait condition: JMESPath expression needs to return boolean")
+	}
+	return value, nil
+}
+
+func (e waitEvaluator) Value(document interface{}, expression string) (interface{}, error) {
+	transformer := output.NewJmesPathTransformer(expression)
+	return transformer.Execute(document)
+}
+
+// Format converts the value into a short string for the progress output.
+func (e waitEvaluator) Format(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func newWaitEvaluator() *waitEvaluator {
+	return &waitEvaluator{}
+}
@@ -0,0 +1,43 @@
+package commandline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/UiPath/uipathcli/output"
+)
+
+func TestWaitEvaluatorAddsStatusAndHeaders(t *testing.T) {
+	evaluator := newWaitEvaluator()
+	response := output.NewResponseInfo(202, "202 Accepted", "HTTP/1.1", map[string][]string{"Location": {"/jobs/1"}}, bytes.NewReader([]byte{}))
+
+	document := evaluator.Document(*response)
+
+	result, err := evaluator.Condition(document, "_status == `202` && _headers.location == '/jobs/1'")
+	if err != nil || !result {
+		t.Errorf("Expected condition on status and headers to be met, but got: %v, %v", result, err)
+	}
+}
+
+func TestWaitEvaluatorKeepsArrayBody(t *testing.T) {
+	evaluator := newWaitEvaluator()
+	response := output.NewResponseInfo(200, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader([]byte(`[{"state":"Running"}]`)))
+
+	document := evaluator.Document(*response)
+
+	value, err := evaluator.Value(document, "[0].state")
+	if err != nil || value != "Running" {
+		t.Errorf("Expected value from array body, but got: %v, %v", value, err)
+	}
+}
+
+func TestWaitEvaluatorFormatsValues(t *testing.T) {
+	evaluator := newWaitEvaluator()
+
+	if evaluator.Format("Running") != "Running" {
+		t.Errorf("Expected string without quotes, but got: %v", evaluator.Format("Running"))
+	}
+	if evaluator.Format(map[string]interface{}{"progress": 50.0}) != `{"progress":50}` {
+		t.Errorf("Expected json value, but got: %v", evaluator.Format(map[string]interface{}{"progress": 50.0}))
+	}
+}
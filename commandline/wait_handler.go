@@ -0,0 +1,149 @@
+package commandline
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/UiPath/uipathcli/executor"
+	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/parser"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// waitRunFunc executes the polled operation once and writes the response
+// into the given output writer.
+type waitRunFunc func(context executor.ExecutionContext, writer output.OutputWriter) error
+
+// The waitHandler polls an operation until the --wait condition is met.
+//
+// Responses are never served from the cache while waiting. The response which
+// met the condition, or the --wait-fail condition, is written to the Output.
+// The progress of every unsuccessful poll is reported using the Logger.
+type waitHandler struct {
+	Output output.OutputWriter
+	Logger log.Logger
+	Run    waitRunFunc
+}
+
+func (h waitHandler) Execute(context executor.ExecutionContext, options waitOptions) error {
+	evaluator := newWaitEvaluator()
+	context.Cache = false
+	start := time.Now()
+	if options.Probe != nil {
+		probeContext, err := h.probeContext(context, options)
+		if err != nil {
+			return err
+		}
+		context = *probeContext
+	}
+	interval := options.Interval
+	for {
+		outputWriter := output.NewMemoryOutputWriter()
+		err := h.Run(context, outputWriter)
+		document := evaluator.Document(outputWriter.Response())
+		result, evaluationErr := evaluator.Condition(document, options.Condition)
+		if evaluationErr != nil {
+			return evaluationErr
+		}
+		if result {
+			h.writeResponse(outputWriter.Response())
+			return err
+		}
+		if options.FailCondition != "" {
+			failed, evaluationErr := evaluator.Condition(document, options.FailCondition)
+			if evaluationErr != nil {
+				return evaluationErr
+			}
+			if failed {
+				h.writeResponse(outputWriter.Response())
+				return fmt.Errorf("Stopped waiting because the --%s condition is met", waitFailFlagName)
+			}
+		}
+		remaining := options.Timeout - time.Since(start)
+		if remaining <= 0 {
+			return newWaitTimeoutError("Timed out waiting for condition")
+		}
+		h.Logger.LogError(h.progress(evaluator, document, options))
+		if interval > remaining {
+			interval = remaining
+		}
+		err = utils.Sleep(context.Context, interval)
+		if err != nil {
+			return err
+		}
+		interval = options.NextInterval(interval)
+	}
+}
+
+// probeContext sends the request once and returns the execution context for
+// polling the probe operation with the id extracted from the response.
+func (h waitHandler) probeContext(context executor.ExecutionContext, options waitOptions) (*executor.ExecutionContext, error) {
+	outputWriter := output.NewMemoryOutputWriter()
+	err := h.Run(context, outputWriter)
+	if err != nil {
+		return nil, err
+	}
+	response := outputWriter.Response()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		h.writeResponse(response)
+		return nil, utils.NewHttpStatusError(response.StatusCode, fmt.Errorf("Service returned status code '%v'", response.StatusCode))
+	}
+	evaluator := newWaitEvaluator()
+	id, err := evaluator.Value(evaluator.Document(response), options.ProbeId)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, fmt.Errorf("Could not find the id for --%s using the expression '%s'", waitProbeFlagName, options.ProbeId)
+	}
+	if number, ok := id.(float64); ok && number == math.Trunc(number) {
+		id = int64(number)
+	}
+
+	pathParameter := probePathParameters(*options.Probe)[0]
+	parameters := context.Parameters.Header()
+	parameters = append(parameters, *executor.NewExecutionParameter(pathParameter.FieldName, id, parser.ParameterInPath))
+	probeContext := context
+	probeContext.Method = options.Probe.Method
+	probeContext.Route = options.Probe.Route
+	probeContext.ContentType = ""
+	probeContext.Input = nil
+	probeContext.Parameters = parameters
+	probeContext.CompressRequest = false
+	probeContext.Plugin = nil
+	return &probeContext, nil
+}
+
+func (h waitHandler) writeResponse(response output.ResponseInfo) {
+	_ = h.Output.WriteResponse(response)
+}
+
+func (h waitHandler) progress(evaluator *waitEvaluator, document interface{}, options waitOptions) string {
+	if options.Progress == "" {
+		return "Condition is not met yet. Waiting...\n"
+	}
+	value, err := evaluator.Value(document, options.Progress)
+	if err != nil {
+		return fmt.Sprintf("Condition is not met yet (%v). Waiting...\n", err)
+	}
+	return fmt.Sprintf("Condition is not met yet (%s). Waiting...\n", evaluator.Format(value))
+}
+
+// probePathParameters returns the path parameters of the --wait-probe
+// operation. The probe operation needs exactly one path parameter which
+// receives the extracted id.
+func probePathParameters(operation parser.Operation) []parser.Parameter {
+	result := []parser.Parameter{}
+	for _, parameter := range operation.Parameters {
+		if parameter.In == parser.ParameterInPath {
+			result = append(result, parameter)
+		}
+	}
+	return result
+}
+
+func newWaitHandler(outputWriter output.OutputWriter, logger log.Logger, run waitRunFunc) *waitHandler {
+	return &waitHandler{outputWriter, logger, run}
+}
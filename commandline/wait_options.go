@@ -0,0 +1,39 @@
+package commandline
+
+import (
+	"time"
+
+	"github.com/UiPath/uipathcli/parser"
+)
+
+const waitMaxInterval = 30 * time.Second
+
+// waitOptions control how the CLI polls until the --wait condition is met.
+//
+// The Interval is the delay between two polls. With Backoff enabled, the
+// interval doubles after every poll up to a maximum of 30s. The FailCondition
+// stops waiting as soon as the operation reached a terminal failure state.
+// Instead of re-sending the executed request, the Probe operation is polled
+// using the id extracted from the first response with the ProbeId expression.
+type waitOptions struct {
+	Condition     string
+	FailCondition string
+	Progress      string
+	Timeout       time.Duration
+	Interval      time.Duration
+	Backoff       bool
+	Probe         *parser.Operation
+	ProbeId       string
+}
+
+// NextInterval returns the delay before the next poll.
+func (o waitOptions) NextInterval(interval time.Duration) time.Duration {
+	if !o.Backoff {
+		return interval
+	}
+	next := interval * 2
+	if next > waitMaxInterval {
+		return waitMaxInterval
+	}
+	return next
+}
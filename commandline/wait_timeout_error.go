@@ -0,0 +1,15 @@
+package commandline
+
+// WaitTimeoutError is returned when the --wait condition was not met
+// within the provided --wait-timeout.
+type WaitTimeoutError struct {
+	message string
+}
+
+func (e WaitTimeoutError) Error() string {
+	return e.message
+}
+
+func newWaitTimeoutError(message string) *WaitTimeoutError {
+	return &WaitTimeoutError{message}
+}
@@ -5,6 +5,7 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"time"
 )
 
 // The Config structure holds the config data from the selected profile.
@@ -16,9 +17,19 @@ type Config struct {
 	Header       map[string]string
 	Auth         AuthConfig
 	Insecure     bool
+	Proxy        *url.URL
+	CaCert       string
+	ClientCert   string
+	ClientKey    string
 	Debug        bool
+	Fail         bool
+	Timeout      time.Duration
+	Cache        bool
 	Output       string
 	Version      string
+	Retry        RetryConfig
+	Connection   ConnectionConfig
+	RateLimit    map[string]string
 }
 
 // AuthConfig with metadata used for authenticating the caller.
@@ -27,6 +38,24 @@ type AuthConfig struct {
 	Config map[string]interface{}
 }
 
+// RetryConfig defines how failed requests are retried.
+// Unset values fall back to the default retry policy.
+type RetryConfig struct {
+	MaxRetries  *int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Jitter      bool
+	StatusCodes []int
+}
+
+// ConnectionConfig limits the pool of keep-alive connections.
+// Unset values fall back to the default limits.
+type ConnectionConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleTimeout         time.Duration
+}
+
 const clientIdKey = "clientId"
 const clientSecretKey = "clientSecret"
 const redirectUriKey = "redirectUri"
@@ -140,10 +169,43 @@ func (c *Config) SetInsecure(insecure bool) {
 	c.Insecure = insecure
 }
 
+func (c *Config) SetProxy(proxy string) error {
+	parsedProxy, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("Invalid value for 'proxy': %w", err)
+	}
+	c.Proxy = parsedProxy
+	return nil
+}
+
+func (c *Config) SetCaCert(caCert string) {
+	c.CaCert = caCert
+}
+
+func (c *Config) SetClientCert(clientCert string) {
+	c.ClientCert = clientCert
+}
+
+func (c *Config) SetClientKey(clientKey string) {
+	c.ClientKey = clientKey
+}
+
 func (c *Config) SetDebug(debug bool) {
 	c.Debug = debug
 }
 
+func (c *Config) SetFail(fail bool) {
+	c.Fail = fail
+}
+
+func (c *Config) SetTimeout(timeout time.Duration) {
+	c.Timeout = timeout
+}
+
+func (c *Config) SetCache(cache bool) {
+	c.Cache = cache
+}
+
 func (c Config) SetHeader(key string, value string) {
 	c.Header[key] = value
 }
@@ -152,6 +214,10 @@ func (c Config) SetParameter(key string, value string) {
 	c.Parameter[key] = value
 }
 
+func (c Config) SetRateLimit(service string, rateLimit string) {
+	c.RateLimit[service] = rateLimit
+}
+
 func (c Config) SetAuthGrantType(grantType string) {
 	c.Auth.Config["grantType"] = grantType
 }
@@ -172,3 +238,35 @@ func (c Config) SetAuthProperty(key string, value string) {
 func (c *Config) SetVersion(version string) {
 	c.Version = version
 }
+
+func (c *Config) SetRetryMaxRetries(maxRetries int) {
+	c.Retry.MaxRetries = &maxRetries
+}
+
+func (c *Config) SetRetryBackoff(backoff time.Duration) {
+	c.Retry.Backoff = backoff
+}
+
+func (c *Config) SetRetryMaxBackoff(maxBackoff time.Duration) {
+	c.Retry.MaxBackoff = maxBackoff
+}
+
+func (c *Config) SetRetryJitter(jitter bool) {
+	c.Retry.Jitter = jitter
+}
+
+func (c *Config) SetRetryStatusCodes(statusCodes []int) {
+	c.Retry.StatusCodes = statusCodes
+}
+
+func (c *Config) SetConnectionMaxIdleConns(maxIdleConns int) {
+	c.Connection.MaxIdleConns = maxIdleConns
+}
+
+func (c *Config) SetConnectionMaxIdleConnsPerHost(maxIdleConnsPerHost int) {
+	c.Connection.MaxIdleConnsPerHost = maxIdleConnsPerHost
+}
+
+func (c *Config) SetConnectionIdleTimeout(idleTimeout time.Duration) {
+	c.Connection.IdleTimeout = idleTimeout
+}
@@ -41,13 +41,33 @@ func (p *ConfigProvider) Update(profileName string, config Config) error {
 	}
 	profile.Uri = urlYaml{config.Uri}
 	profile.Insecure = config.Insecure
+	profile.Proxy = urlYaml{config.Proxy}
+	profile.CaCert = config.CaCert
+	profile.ClientCert = config.ClientCert
+	profile.ClientKey = config.ClientKey
 	profile.Debug = config.Debug
+	profile.Fail = config.Fail
+	profile.Timeout = durationYaml{config.Timeout}
+	profile.Cache = config.Cache
 	profile.Organization = config.Organization
 	profile.Tenant = config.Tenant
 	profile.Auth = config.Auth.Config
 	profile.Header = config.Header
 	profile.Parameter = config.Parameter
 	profile.Version = config.Version
+	profile.Retry = retryYaml{
+		MaxRetries:  config.Retry.MaxRetries,
+		Backoff:     durationYaml{config.Retry.Backoff},
+		MaxBackoff:  durationYaml{config.Retry.MaxBackoff},
+		Jitter:      config.Retry.Jitter,
+		StatusCodes: config.Retry.StatusCodes,
+	}
+	profile.Connection = connectionYaml{
+		MaxIdleConns:        config.Connection.MaxIdleConns,
+		MaxIdleConnsPerHost: config.Connection.MaxIdleConnsPerHost,
+		IdleTimeout:         durationYaml{config.Connection.IdleTimeout},
+	}
+	profile.RateLimit = config.RateLimit
 
 	if index == -1 {
 		p.profiles = append(p.profiles, profile)
@@ -72,6 +92,9 @@ func (p ConfigProvider) convertToConfig(profile profileYaml) Config {
 	if profile.Header == nil {
 		profile.Header = map[string]string{}
 	}
+	if profile.RateLimit == nil {
+		profile.RateLimit = map[string]string{}
+	}
 	return Config{
 		Organization: profile.Organization,
 		Tenant:       profile.Tenant,
@@ -82,10 +105,30 @@ func (p ConfigProvider) convertToConfig(profile profileYaml) Config {
 			Type:   fmt.Sprintf("%v", profile.Auth["type"]),
 			Config: profile.Auth,
 		},
-		Insecure: profile.Insecure,
-		Debug:    profile.Debug,
-		Output:   profile.Output,
-		Version:  profile.Version,
+		Insecure:   profile.Insecure,
+		Proxy:      profile.Proxy.URL,
+		CaCert:     profile.CaCert,
+		ClientCert: profile.ClientCert,
+		ClientKey:  profile.ClientKey,
+		Debug:      profile.Debug,
+		Fail:       profile.Fail,
+		Timeout:    profile.Timeout.Duration,
+		Cache:      profile.Cache,
+		Output:     profile.Output,
+		Version:    profile.Version,
+		Retry: RetryConfig{
+			MaxRetries:  profile.Retry.MaxRetries,
+			Backoff:     profile.Retry.Backoff.Duration,
+			MaxBackoff:  profile.Retry.MaxBackoff.Duration,
+			Jitter:      profile.Retry.Jitter,
+			StatusCodes: profile.Retry.StatusCodes,
+		},
+		Connection: ConnectionConfig{
+			MaxIdleConns:        profile.Connection.MaxIdleConns,
+			MaxIdleConnsPerHost: profile.Connection.MaxIdleConnsPerHost,
+			IdleTimeout:         profile.Connection.IdleTimeout.Duration,
+		},
+		RateLimit: profile.RateLimit,
 	}
 }
 
@@ -0,0 +1,7 @@
+package config
+
+type connectionYaml struct {
+	MaxIdleConns        int          `yaml:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost int          `yaml:"maxIdleConnsPerHost,omitempty"`
+	IdleTimeout         durationYaml `yaml:"idleTimeout,omitempty"`
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type durationYaml struct {
+	time.Duration
+}
+
+func (d durationYaml) MarshalYAML() (interface{}, error) {
+	return d.Duration.String(), nil
+}
+
+func (d *durationYaml) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	err := unmarshal(&s)
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("Invalid duration '%s': %w", s, err)
+	}
+	d.Duration = duration
+	return nil
+}
@@ -9,7 +9,17 @@ type profileYaml struct {
 	Header       map[string]string      `yaml:"header,omitempty"`
 	Auth         map[string]interface{} `yaml:"auth,omitempty"`
 	Insecure     bool                   `yaml:"insecure,omitempty"`
+	Proxy        urlYaml                `yaml:"proxy,omitempty"`
+	CaCert       string                 `yaml:"caCert,omitempty"`
+	ClientCert   string                 `yaml:"clientCert,omitempty"`
+	ClientKey    string                 `yaml:"clientKey,omitempty"`
 	Debug        bool                   `yaml:"debug,omitempty"`
+	Fail         bool                   `yaml:"fail,omitempty"`
+	Timeout      durationYaml           `yaml:"timeout,omitempty"`
+	Cache        bool                   `yaml:"cache,omitempty"`
 	Output       string                 `yaml:"output,omitempty"`
 	Version      string                 `yaml:"version,omitempty"`
+	Retry        retryYaml              `yaml:"retry,omitempty"`
+	Connection   connectionYaml         `yaml:"connection,omitempty"`
+	RateLimit    map[string]string      `yaml:"rateLimit,omitempty"`
 }
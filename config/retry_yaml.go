@@ -0,0 +1,9 @@
+package config
+
+type retryYaml struct {
+	MaxRetries  *int         `yaml:"maxRetries,omitempty"`
+	Backoff     durationYaml `yaml:"backoff,omitempty"`
+	MaxBackoff  durationYaml `yaml:"maxBackoff,omitempty"`
+	Jitter      bool         `yaml:"jitter,omitempty"`
+	StatusCodes []int        `yaml:"statusCodes,omitempty"`
+}
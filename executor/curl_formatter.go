@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+const curlAccessTokenVariable = "UIPATH_ACCESS_TOKEN"
+
+// curlFormatter converts the fully resolved HTTP request into an equivalent
+// curl command which is printed when --output curl is provided.
+//
+// Multipart file fields reference the file on disk and the bearer token is
+// replaced with an environment variable placeholder so that the command can
+// be shared safely.
+type curlFormatter struct{}
+
+func (f curlFormatter) Format(request *http.Request, context ExecutionContext, body []byte) string {
+	args := []string{"curl", "-X", request.Method, f.quote(request.URL.String())}
+	formParameters := context.Parameters.Form()
+	args = append(args, f.formatHeader(request.Header, len(formParameters) > 0)...)
+	args = append(args, "-H", fmt.Sprintf(`"Authorization: Bearer $%s"`, curlAccessTokenVariable))
+
+	if context.Input != nil {
+		args = append(args, "--data-binary", f.quote("@"+f.streamPath(context.Input)))
+	} else if len(formParameters) > 0 {
+		args = append(args, f.formatForm(formParameters)...)
+	} else if len(body) > 0 {
+		args = append(args, "--data-raw", f.quote(string(body)))
+	}
+	return f.join(args)
+}
+
+func (f curlFormatter) formatHeader(header http.Header, multipart bool) []string {
+	names := []string{}
+	for name := range header {
+		if multipart && strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []string{}
+	for _, name := range names {
+		value := network.RedactHeader(name, strings.Join(header.Values(name), ", "))
+		result = append(result, "-H", f.quote(name+": "+value))
+	}
+	return result
+}
+
+func (f curlFormatter) formatForm(parameters []ExecutionParameter) []string {
+	result := []string{}
+	for _, parameter := range parameters {
+		switch v := parameter.Value.(type) {
+		case string:
+			result = append(result, "-F", f.quote(parameter.Name+"="+v))
+		case utils.Stream:
+			result = append(result, "-F", f.quote(parameter.Name+"=@"+f.streamPath(v)))
+		}
+	}
+	return result
+}
+
+func (f curlFormatter) streamPath(stream utils.Stream) string {
+	if fileStream, ok := stream.(*utils.FileStream); ok {
+		return fileStream.Path()
+	}
+	return "-"
+}
+
+func (f curlFormatter) join(args []string) string {
+	builder := strings.Builder{}
+	builder.WriteString(strings.Join(args[0:4], " "))
+	for i := 4; i < len(args); i += 2 {
+		builder.WriteString(" \\\n  ")
+		builder.WriteString(args[i] + " " + args[i+1])
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func (f curlFormatter) quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func newCurlFormatter() *curlFormatter {
+	return &curlFormatter{}
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/UiPath/uipathcli/network"
+)
+
+// dryRunFormatter converts the fully resolved HTTP request into a JSON document
+// which is printed instead of sending the request when --dry-run is provided.
+//
+// Sensitive headers like the Authorization header are redacted so that the
+// output can be safely shared.
+type dryRunFormatter struct{}
+
+type dryRunRequest struct {
+	Method string            `json:"method"`
+	Url    string            `json:"url"`
+	Header map[string]string `json:"header"`
+	Body   interface{}       `json:"body,omitempty"`
+}
+
+func (f dryRunFormatter) Format(request *http.Request, body []byte) ([]byte, error) {
+	result := dryRunRequest{
+		Method: request.Method,
+		Url:    request.URL.String(),
+		Header: f.formatHeader(request.Header),
+		Body:   f.formatBody(request.Header.Get("Content-Type"), body),
+	}
+	return json.Marshal(result)
+}
+
+func (f dryRunFormatter) formatHeader(header http.Header) map[string]string {
+	result := map[string]string{}
+	for name, values := range header {
+		result[name] = network.RedactHeader(name, strings.Join(values, ", "))
+	}
+	return result
+}
+
+func (f dryRunFormatter) formatBody(contentType string, body []byte) interface{} {
+	if len(body) == 0 {
+		return nil
+	}
+	if strings.Contains(contentType, "json") {
+		var data interface{}
+		err := json.Unmarshal(body, &data)
+		if err == nil {
+			return data
+		}
+	}
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("<binary data, %d bytes>", len(body))
+	}
+	return string(body)
+}
+
+func newDryRunFormatter() *dryRunFormatter {
+	return &dryRunFormatter{}
+}
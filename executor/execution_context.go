@@ -1,42 +1,55 @@
 package executor
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/UiPath/uipathcli/config"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/plugin"
 	"github.com/UiPath/uipathcli/utils"
 )
 
 // The ExecutionContext provides all the data needed by the executor to construct the HTTP
 // request including URL, headers and body.
+//
+// DryRun prints the request instead of sending it. In combination with AsCurl
+// the request is printed as an equivalent curl command.
+//
+// Stream passes successful responses to the output writer without buffering
+// them in memory, e.g. when the response is written to a file.
+//
+// CompressRequest gzips JSON and raw file request bodies before sending them.
+//
+// Cache enables the conditional response cache for GET requests. Cached
+// responses are scoped to the Profile.
+//
+// The RequestId is sent as x-request-id header. A new id is generated for
+// every call when it is empty, retries of the same call keep their id.
+//
+// The Context is used to cancel the execution, e.g. when the user presses Ctrl+C
+// or the configured timeout expires.
 type ExecutionContext struct {
-	Organization string
-	Tenant       string
-	Method       string
-	BaseUri      url.URL
-	Route        string
-	ContentType  string
-	Input        utils.Stream
-	Parameters   ExecutionParameters
-	AuthConfig   config.AuthConfig
-	Insecure     bool
-	Debug        bool
-	Plugin       plugin.CommandPlugin
-}
-
-func NewExecutionContext(
-	organization string,
-	tenant string,
-	method string,
-	uri url.URL,
-	route string,
-	contentType string,
-	input utils.Stream,
-	parameters []ExecutionParameter,
-	authConfig config.AuthConfig,
-	insecure bool,
-	debug bool,
-	plugin plugin.CommandPlugin) *ExecutionContext {
-	return &ExecutionContext{organization, tenant, method, uri, route, contentType, input, parameters, authConfig, insecure, debug, plugin}
+	Organization    string
+	Tenant          string
+	Method          string
+	BaseUri         url.URL
+	Route           string
+	ContentType     string
+	Input           utils.Stream
+	Parameters      ExecutionParameters
+	AuthConfig      config.AuthConfig
+	Network         network.HttpClientSettings
+	Debug           bool
+	Fail            bool
+	DryRun          bool
+	AsCurl          bool
+	Stream          bool
+	CompressRequest bool
+	Cache           bool
+	Profile         string
+	RequestId       string
+	RetryPolicy     utils.RetryPolicy
+	Context         context.Context
+	Plugin          plugin.CommandPlugin
 }
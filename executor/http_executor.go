@@ -2,22 +2,27 @@ package executor
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/rand"
-	"crypto/tls"
+	"crypto/sha256"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/UiPath/uipathcli/auth"
+	"github.com/UiPath/uipathcli/cache"
 	"github.com/UiPath/uipathcli/config"
 	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/tracing"
 	"github.com/UiPath/uipathcli/utils"
 )
 
@@ -32,13 +37,23 @@ For more information you can view the help:
 
 // The HttpExecutor implements the Executor interface and constructs HTTP request
 // from the given command line parameters and configurations.
+//
+// GET requests are served from the response cache when caching is enabled
+// and the cached response is still fresh. Stale responses are revalidated
+// by sending their ETag and Last-Modified validators.
 type HttpExecutor struct {
 	authenticators []auth.Authenticator
+	responseCache  cache.ResponseCache
 }
 
 func (e HttpExecutor) Call(context ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
-	return utils.Retry(func() error {
-		return e.call(context, writer, logger)
+	if context.RequestId == "" {
+		context.RequestId = e.requestId()
+	}
+	return utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		attemptContext := context
+		attemptContext.Context = utils.WithRetryAttempt(context.Context, attempt)
+		return e.call(attemptContext, writer, logger)
 	})
 }
 
@@ -48,9 +63,9 @@ func (e HttpExecutor) requestId() string {
 	return fmt.Sprintf("%x%x%x%x%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:])
 }
 
-func (e HttpExecutor) addHeaders(request *http.Request, headerParameters []ExecutionParameter) {
+func (e HttpExecutor) addHeaders(request *http.Request, requestId string, headerParameters []ExecutionParameter) {
 	formatter := newParameterFormatter()
-	request.Header.Add("x-request-id", e.requestId())
+	request.Header.Add("x-request-id", requestId)
 	for _, parameter := range headerParameters {
 		headerValue := formatter.Format(parameter)
 		request.Header.Add(parameter.Name, headerValue)
@@ -144,19 +159,23 @@ func (e HttpExecutor) formatUri(baseUri url.URL, route string, pathParameters []
 	return e.validateUri(formatter.Uri())
 }
 
-func (e HttpExecutor) executeAuthenticators(authConfig config.AuthConfig, debug bool, insecure bool, request *http.Request) (*auth.AuthenticatorResult, error) {
+func (e HttpExecutor) executeAuthenticators(authConfig config.AuthConfig, debug bool, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy, request *http.Request) (*auth.AuthenticatorResult, error) {
+	traceContext, span := tracing.Start(request.Context(), "auth", tracing.SpanKindInternal)
 	authRequest := *auth.NewAuthenticatorRequest(request.URL.String(), map[string]string{})
-	ctx := *auth.NewAuthenticatorContext(authConfig.Type, authConfig.Config, debug, insecure, authRequest)
+	ctx := *auth.NewAuthenticatorContext(authConfig.Type, authConfig.Config, debug, settings.Insecure, authRequest, retryPolicy, traceContext, settings)
 	for _, authProvider := range e.authenticators {
 		result := authProvider.Auth(ctx)
 		if result.Error != "" {
-			return nil, errors.New(result.Error)
+			err := auth.NewAuthenticationError(result.Error)
+			span.End(err)
+			return nil, err
 		}
 		ctx.Config = result.Config
 		for k, v := range result.RequestHeader {
 			ctx.Request.Header[k] = v
 		}
 	}
+	span.End(nil)
 	return auth.AuthenticatorSuccess(ctx.Request.Header, ctx.Config), nil
 }
 
@@ -310,15 +329,40 @@ func (e HttpExecutor) call(context ExecutionContext, writer output.OutputWriter,
 	uploadBar := utils.NewProgressBar(logger)
 	uploadReader := e.progressReader("uploading...", "completing  ", bodyReader, contentLength, uploadBar)
 	defer uploadBar.Remove()
-	request, err := http.NewRequest(context.Method, uri.String(), uploadReader)
+	request, err := http.NewRequestWithContext(context.Context, context.Method, uri.String(), uploadReader)
 	if err != nil {
 		return fmt.Errorf("Error preparing request: %w", err)
 	}
 	if contentType != "" {
 		request.Header.Add("Content-Type", contentType)
 	}
-	e.addHeaders(request, context.Parameters.Header())
-	auth, err := e.executeAuthenticators(context.AuthConfig, context.Debug, context.Insecure, request)
+	e.addHeaders(request, context.RequestId, context.Parameters.Header())
+	if context.DryRun {
+		return e.writeDryRun(context, request, requestError, writer)
+	}
+	decoder := newResponseDecoder()
+	if request.Header.Get("Accept-Encoding") == "" {
+		request.Header.Set("Accept-Encoding", decoder.AcceptEncoding())
+	}
+	compress := e.compressRequest(context)
+	if compress {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+	cacheKey := ""
+	var cached *cache.CachedResponse
+	requestHeader := request.Header.Clone()
+	if e.cacheable(context) {
+		cacheKey = e.cacheKey(context, request)
+		cached = e.responseCache.Get(cacheKey)
+		if cached != nil && !cached.Matches(requestHeader) {
+			cached = nil
+		}
+		if cached != nil && cached.Fresh(time.Now()) {
+			return e.writeCachedResponse(context, *cached, writer, logger)
+		}
+		e.addValidators(request, cached)
+	}
+	auth, err := e.executeAuthenticators(context.AuthConfig, context.Debug, context.Network, context.RetryPolicy, request)
 	if err != nil {
 		return err
 	}
@@ -326,37 +370,207 @@ func (e HttpExecutor) call(context ExecutionContext, writer output.OutputWriter,
 		request.Header.Add(k, v)
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: context.Insecure}, //nolint // This is user configurable and disabled by default
-		ResponseHeaderTimeout: 60 * time.Second,
+	client, err := network.NewHttpClient(context.Network)
+	if err != nil {
+		return err
 	}
-	client := &http.Client{Transport: transport}
 	if context.Debug {
 		e.logRequest(logger, request)
 	}
+	if compress {
+		request.Body = e.compressBody(request.Body)
+	}
 	response, err := e.send(client, request, requestError)
 	if err != nil {
 		return utils.Retryable(fmt.Errorf("Error sending request: %w", err))
 	}
 	defer response.Body.Close()
+	err = decoder.Decode(response)
+	if err != nil {
+		return err
+	}
 	downloadBar := utils.NewProgressBar(logger)
 	downloadReader := e.progressReader("downloading...", "completing    ", response.Body, response.ContentLength, downloadBar)
 	defer downloadBar.Remove()
+	if context.Stream && response.StatusCode >= 200 && response.StatusCode < 300 {
+		e.logResponse(logger, response, []byte{})
+		return writer.WriteResponse(*output.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, downloadReader))
+	}
 	body, err := io.ReadAll(downloadReader)
 	if err != nil {
 		return utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
 	}
 	e.logResponse(logger, response, body)
+	if context.RetryPolicy.IsRetryableStatusCode(response.StatusCode) {
+		retryAfter := utils.ParseRetryAfter(response.Header.Get("Retry-After"))
+		return utils.RetryableAfter(e.statusError(response, body), retryAfter)
+	}
 	if response.StatusCode >= 500 {
-		return utils.Retryable(fmt.Errorf("Service returned status code '%v' and body '%v'", response.StatusCode, string(body)))
+		return e.statusError(response, body)
+	}
+	if cacheKey != "" && cached != nil && response.StatusCode == http.StatusNotModified {
+		revalidated := cached.Revalidated(response.Header, time.Now())
+		e.responseCache.Set(cacheKey, revalidated)
+		return e.writeCachedResponse(context, revalidated, writer, logger)
+	}
+	if cacheKey != "" {
+		entry := cache.NewCachedResponse(response.StatusCode, response.Status, response.Header, body, requestHeader, time.Now())
+		if entry != nil {
+			e.responseCache.Set(cacheKey, *entry)
+		}
 	}
 	err = writer.WriteResponse(*output.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, bytes.NewReader(body)))
 	if err != nil {
 		return err
 	}
+	if context.Fail && (response.StatusCode < 200 || response.StatusCode >= 300) {
+		return utils.NewHttpStatusError(response.StatusCode, fmt.Errorf("Service returned status code '%v'", response.StatusCode))
+	}
 	return nil
 }
 
-func NewHttpExecutor(authenticators []auth.Authenticator) *HttpExecutor {
-	return &HttpExecutor{authenticators}
+func (e HttpExecutor) compressRequest(context ExecutionContext) bool {
+	if !context.CompressRequest {
+		return false
+	}
+	if context.Input != nil {
+		return true
+	}
+	return len(context.Parameters.Form()) == 0 &&
+		len(context.Parameters.Body()) > 0 &&
+		context.ContentType != "application/x-www-form-urlencoded"
+}
+
+// compressBody gzips the request body while it is being sent.
+func (e HttpExecutor) compressBody(body io.ReadCloser) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		defer body.Close()
+		gzipWriter := gzip.NewWriter(writer)
+		_, err := io.Copy(gzipWriter, body)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
+
+func (e HttpExecutor) cacheable(context ExecutionContext) bool {
+	return context.Cache &&
+		e.responseCache != nil &&
+		context.Method == http.MethodGet &&
+		!context.Stream &&
+		context.Input == nil &&
+		len(context.Parameters.Body()) == 0 &&
+		len(context.Parameters.Form()) == 0
+}
+
+// cacheKey scopes the cached responses to the profile, the configured
+// identity and the header parameters so that responses are never shared
+// between different callers or e.g. different orchestrator folders.
+func (e HttpExecutor) cacheKey(context ExecutionContext, request *http.Request) string {
+	identity := fmt.Sprintf("%s|%v|%s|%s",
+		context.AuthConfig.Type,
+		context.AuthConfig.Config,
+		os.Getenv(auth.ClientIdEnvVarName),
+		os.Getenv(auth.PatEnvVarName))
+	hash := sha256.Sum256([]byte(identity))
+	formatter := newParameterFormatter()
+	headers := []string{}
+	for _, parameter := range context.Parameters.Header() {
+		headers = append(headers, strings.ToLower(parameter.Name)+"="+formatter.Format(parameter))
+	}
+	sort.Strings(headers)
+	return fmt.Sprintf("response|%s|%s|%s|%x", context.Profile, request.URL.String(), strings.Join(headers, "&"), hash)
+}
+
+func (e HttpExecutor) addValidators(request *http.Request, cached *cache.CachedResponse) {
+	if cached == nil {
+		return
+	}
+	if cached.ETag() != "" {
+		request.Header.Set("If-None-Match", cached.ETag())
+	}
+	if cached.LastModified() != "" {
+		request.Header.Set("If-Modified-Since", cached.LastModified())
+	}
+}
+
+func (e HttpExecutor) writeCachedResponse(context ExecutionContext, cached cache.CachedResponse, writer output.OutputWriter, logger log.Logger) error {
+	if context.Debug {
+		logger.LogError("Using cached response\n")
+	}
+	response := output.NewResponseInfo(cached.StatusCode, cached.Status, "HTTP/1.1", cached.Header, bytes.NewReader(cached.Body))
+	return writer.WriteResponse(*response)
+}
+
+func (e HttpExecutor) writeDryRun(context ExecutionContext, request *http.Request, errorChan chan error, writer output.OutputWriter) error {
+	if context.AsCurl {
+		return e.writeCurl(context, request, errorChan, writer)
+	}
+	body, err := e.readBody(request, errorChan)
+	if err != nil {
+		return err
+	}
+	formatter := newDryRunFormatter()
+	result, err := formatter.Format(request, body)
+	if err != nil {
+		return fmt.Errorf("Error formatting request: %w", err)
+	}
+	return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", request.Proto, map[string][]string{}, bytes.NewReader(result)))
+}
+
+func (e HttpExecutor) writeCurl(context ExecutionContext, request *http.Request, errorChan chan error, writer output.OutputWriter) error {
+	var body []byte
+	var err error
+	if context.Input != nil || len(context.Parameters.Form()) > 0 {
+		// Files are referenced by path in the curl command, there is no need
+		// to buffer their content.
+		err = e.copyBody(request, errorChan, io.Discard)
+	} else {
+		body, err = e.readBody(request, errorChan)
+	}
+	if err != nil {
+		return err
+	}
+	formatter := newCurlFormatter()
+	result := formatter.Format(request, context, body)
+	return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", request.Proto, map[string][]string{}, strings.NewReader(result)))
+}
+
+func (e HttpExecutor) readBody(request *http.Request, errorChan chan error) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	err := e.copyBody(request, errorChan, &buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (e HttpExecutor) copyBody(request *http.Request, errorChan chan error, writer io.Writer) error {
+	doneChan := make(chan struct{})
+	go func(request *http.Request) {
+		_, err := io.Copy(writer, request.Body)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		close(doneChan)
+	}(request)
+
+	select {
+	case err := <-errorChan:
+		return err
+	case <-doneChan:
+		return nil
+	}
+}
+
+func (e HttpExecutor) statusError(response *http.Response, body []byte) error {
+	return utils.NewHttpStatusError(response.StatusCode, fmt.Errorf("Service returned status code '%v' and body '%v'", response.StatusCode, string(body)))
+}
+
+func NewHttpExecutor(authenticators []auth.Authenticator, responseCache cache.ResponseCache) *HttpExecutor {
+	return &HttpExecutor{authenticators, responseCache}
 }
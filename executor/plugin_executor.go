@@ -1,14 +1,18 @@
 package executor
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"net/url"
 
 	"github.com/UiPath/uipathcli/auth"
 	"github.com/UiPath/uipathcli/config"
 	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/output"
 	"github.com/UiPath/uipathcli/plugin"
+	"github.com/UiPath/uipathcli/tracing"
+	"github.com/UiPath/uipathcli/utils"
 )
 
 // The PluginExecutor implements the Executor interface and invokes the
@@ -19,19 +23,23 @@ type PluginExecutor struct {
 	authenticators []auth.Authenticator
 }
 
-func (e PluginExecutor) executeAuthenticators(baseUri url.URL, authConfig config.AuthConfig, debug bool, insecure bool) (*auth.AuthenticatorResult, error) {
+func (e PluginExecutor) executeAuthenticators(baseUri url.URL, authConfig config.AuthConfig, debug bool, settings network.HttpClientSettings, retryPolicy utils.RetryPolicy, context context.Context) (*auth.AuthenticatorResult, error) {
+	traceContext, span := tracing.Start(context, "auth", tracing.SpanKindInternal)
 	authRequest := *auth.NewAuthenticatorRequest(baseUri.String(), map[string]string{})
-	ctx := *auth.NewAuthenticatorContext(authConfig.Type, authConfig.Config, debug, insecure, authRequest)
+	ctx := *auth.NewAuthenticatorContext(authConfig.Type, authConfig.Config, debug, settings.Insecure, authRequest, retryPolicy, traceContext, settings)
 	for _, authProvider := range e.authenticators {
 		result := authProvider.Auth(ctx)
 		if result.Error != "" {
-			return nil, errors.New(result.Error)
+			err := auth.NewAuthenticationError(result.Error)
+			span.End(err)
+			return nil, err
 		}
 		ctx.Config = result.Config
 		for k, v := range result.RequestHeader {
 			ctx.Request.Header[k] = v
 		}
 	}
+	span.End(nil)
 	return auth.AuthenticatorSuccess(ctx.Request.Header, ctx.Config), nil
 }
 
@@ -51,23 +59,33 @@ func (e PluginExecutor) pluginAuth(auth *auth.AuthenticatorResult) plugin.AuthRe
 }
 
 func (e PluginExecutor) Call(context ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
-	auth, err := e.executeAuthenticators(context.BaseUri, context.AuthConfig, context.Debug, context.Insecure)
+	auth, err := e.executeAuthenticators(context.BaseUri, context.AuthConfig, context.Debug, context.Network, context.RetryPolicy, context.Context)
 	if err != nil {
 		return err
 	}
 
+	command := context.Plugin.Command()
+	pluginCtx, span := tracing.Start(context.Context, fmt.Sprintf("plugin %s %s", command.Service, command.Name), tracing.SpanKindInternal)
 	pluginAuth := e.pluginAuth(auth)
 	pluginParams := e.convertToPluginParameters(context.Parameters)
-	pluginContext := plugin.NewExecutionContext(
-		context.Organization,
-		context.Tenant,
-		context.BaseUri,
-		pluginAuth,
-		context.Input,
-		pluginParams,
-		context.Insecure,
-		context.Debug)
-	return context.Plugin.Execute(*pluginContext, writer, logger)
+	pluginContext := plugin.ExecutionContext{
+		Organization: context.Organization,
+		Tenant:       context.Tenant,
+		BaseUri:      context.BaseUri,
+		Auth:         pluginAuth,
+		Input:        context.Input,
+		Parameters:   pluginParams,
+		Insecure:     context.Network.Insecure,
+		Debug:        context.Debug,
+		DryRun:       context.DryRun,
+		Fail:         context.Fail,
+		RetryPolicy:  context.RetryPolicy,
+		Context:      pluginCtx,
+		Network:      context.Network,
+	}
+	err = context.Plugin.Execute(pluginContext, writer, logger)
+	span.End(err)
+	return err
 }
 
 func NewPluginExecutor(authenticators []auth.Authenticator) *PluginExecutor {
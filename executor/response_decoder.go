@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// decoderFunc creates a reader which decompresses the given reader.
+type decoderFunc func(reader io.Reader) (io.ReadCloser, error)
+
+// The responseDecoder transparently decompresses response bodies based on
+// their Content-Encoding header so that the output writers and the debug
+// logger always see the decompressed body.
+//
+// Only encodings which can be decoded are advertised in the Accept-Encoding
+// request header.
+type responseDecoder struct {
+	decoders map[string]decoderFunc
+}
+
+var supportedEncodings = []string{"gzip", "deflate", "br"}
+
+func (d responseDecoder) AcceptEncoding() string {
+	return strings.Join(supportedEncodings, ", ")
+}
+
+// Decode replaces the response body with the decompressed body and removes
+// the headers which describe the compressed body.
+func (d responseDecoder) Decode(response *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(response.Header.Get("Content-Encoding")))
+	decoder, found := d.decoders[encoding]
+	if !found {
+		return nil
+	}
+	body := bufio.NewReader(response.Body)
+	_, err := body.Peek(1)
+	if err == io.EOF {
+		return nil
+	}
+	reader, err := decoder(body)
+	if err != nil {
+		return fmt.Errorf("Error decompressing %s response body: %w", encoding, err)
+	}
+	response.Body = &decodedBody{reader, response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return nil
+}
+
+// decodedBody reads from the decompressing reader and closes both, the
+// decompressing reader and the original response body.
+type decodedBody struct {
+	reader io.ReadCloser
+	body   io.ReadCloser
+}
+
+func (b decodedBody) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+func (b decodedBody) Close() error {
+	b.reader.Close()
+	return b.body.Close()
+}
+
+func newResponseDecoder() *responseDecoder {
+	return &responseDecoder{
+		decoders: map[string]decoderFunc{
+			"gzip": func(reader io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(reader)
+			},
+			"x-gzip": func(reader io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(reader)
+			},
+			"deflate": func(reader io.Reader) (io.ReadCloser, error) {
+				return zlib.NewReader(reader)
+			},
+			"br": func(reader io.Reader) (io.ReadCloser, error) {
+				return io.NopCloser(brotli.NewReader(reader)), nil
+			},
+		},
+	}
+}
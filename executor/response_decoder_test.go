@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestDecodeGzipResponse(t *testing.T) {
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	_, _ = writer.Write([]byte("hello"))
+	writer.Close()
+	response := newEncodedResponse("gzip", body.Bytes())
+
+	err := newResponseDecoder().Decode(response)
+
+	data, _ := io.ReadAll(response.Body)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("Should decompress response body, but got: %v, %v", string(data), err)
+	}
+	if response.Header.Get("Content-Encoding") != "" || response.ContentLength != -1 {
+		t.Errorf("Should remove compressed body headers, but got: %v", response.Header)
+	}
+}
+
+func TestDecodeEmptyResponseWithContentEncoding(t *testing.T) {
+	response := newEncodedResponse("gzip", []byte{})
+
+	err := newResponseDecoder().Decode(response)
+
+	data, _ := io.ReadAll(response.Body)
+	if err != nil || len(data) != 0 {
+		t.Errorf("Should ignore empty response body, but got: %v, %v", string(data), err)
+	}
+}
+
+func TestDecodeUnsupportedEncodingKeepsBody(t *testing.T) {
+	response := newEncodedResponse("zstd", []byte("compressed"))
+
+	err := newResponseDecoder().Decode(response)
+
+	data, _ := io.ReadAll(response.Body)
+	if err != nil || string(data) != "compressed" || response.Header.Get("Content-Encoding") != "zstd" {
+		t.Errorf("Should keep response with unsupported encoding, but got: %v, %v", string(data), err)
+	}
+}
+
+func TestDecodeInvalidGzipReturnsError(t *testing.T) {
+	response := newEncodedResponse("gzip", []byte("not-gzip"))
+
+	err := newResponseDecoder().Decode(response)
+
+	if err == nil {
+		t.Errorf("Should return error for invalid gzip body")
+	}
+}
+
+func newEncodedResponse(encoding string, body []byte) *http.Response {
+	header := http.Header{}
+	header.Set("Content-Encoding", encoding)
+	return &http.Response{
+		StatusCode:    200,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
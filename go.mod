@@ -3,9 +3,11 @@ module github.com/UiPath/uipathcli
 go 1.20
 
 require (
+	github.com/andybalholm/brotli v1.1.1
 	github.com/getkin/kin-openapi v0.115.0
 	github.com/jmespath/go-jmespath v0.4.0
 	github.com/urfave/cli/v2 v2.25.1
+	golang.org/x/net v0.8.0
 	golang.org/x/sys v0.6.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -21,5 +23,6 @@ require (
 	github.com/perimeterx/marshmallow v1.1.4 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
+	golang.org/x/text v0.8.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,14 @@
+package log
+
+// The QuietLogger suppresses the error and progress output of operations
+// which run in parallel while still logging requests and responses.
+type QuietLogger struct {
+	Logger
+}
+
+func (l QuietLogger) LogError(message string) {
+}
+
+func NewQuietLogger(logger Logger) *QuietLogger {
+	return &QuietLogger{logger}
+}
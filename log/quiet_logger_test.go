@@ -0,0 +1,29 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQuietLoggerSuppressesErrors(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewQuietLogger(NewDebugLogger(&output))
+
+	logger.LogError("\r50% |██████████          |")
+
+	if output.String() != "" {
+		t.Errorf("Standard error should be empty, but got: %v", output.String())
+	}
+}
+
+func TestQuietLoggerLogsRequests(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewQuietLogger(NewDebugLogger(&output))
+
+	logger.LogRequest(*NewRequestInfo("GET", "https://cloud.uipath.com/my-service", "HTTP/1.1", map[string][]string{}, bytes.NewBufferString("")))
+
+	if !strings.HasPrefix(output.String(), "GET https://cloud.uipath.com/my-service HTTP/1.1") {
+		t.Errorf("Standard output should contain request, but got: %v", output.String())
+	}
+}
@@ -4,9 +4,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 
 	"github.com/UiPath/uipathcli/auth"
 	"github.com/UiPath/uipathcli/cache"
@@ -45,6 +48,18 @@ func stdIn() utils.Stream {
 	return utils.NewReaderStream(parser.RawBodyParameterName, os.Stdin)
 }
 
+// Cancels the returned context when the user presses Ctrl+C or the process
+// receives SIGTERM so that in-flight requests are aborted gracefully.
+// A second signal terminates the process immediately.
+func cancellationContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
 func main() {
 	configProvider := config.NewConfigProvider(
 		config.NewConfigFileStore(os.Getenv("UIPATH_CONFIGURATION_PATH")),
@@ -52,7 +67,7 @@ func main() {
 	err := configProvider.Load()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(131)
+		os.Exit(commandline.ExitCodeConfigError)
 	}
 	pluginConfigProvider := config.NewPluginConfigProvider(
 		config.NewPluginConfigFileStore(os.Getenv("UIPATH_PLUGINS_PATH")),
@@ -60,7 +75,7 @@ func main() {
 	err = pluginConfigProvider.Load()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(132)
+		os.Exit(commandline.ExitCodePluginConfigError)
 	}
 
 	pluginConfig := pluginConfigProvider.Config()
@@ -77,16 +92,20 @@ func main() {
 				plugin_digitizer.DigitizeCommand{},
 				plugin_orchestrator.UploadCommand{},
 				plugin_orchestrator.DownloadCommand{},
+				plugin_orchestrator.RunJobCommand{},
+				plugin_orchestrator.SyncCommand{},
 			},
 		),
 		*configProvider,
-		executor.NewHttpExecutor(authenticators),
+		executor.NewHttpExecutor(authenticators, cache.NewFileResponseCache()),
 		executor.NewPluginExecutor(authenticators),
 	)
 
+	ctx, cancel := cancellationContext()
 	input := stdIn()
-	err = cli.Run(os.Args, input)
+	err = cli.RunContext(ctx, os.Args, input)
+	cancel()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(commandline.ExitCode(err))
 	}
 }
@@ -0,0 +1,334 @@
+package network
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const cassetteBodyEncodingBase64 = "base64"
+
+var sensitiveBodyProperties = []string{"access_token", "refresh_token", "id_token"}
+var sensitiveFormFields = []string{"code", "code_verifier"}
+var sensitiveQueryParameters = []string{"sig", "code", "token", "access_token", "id_token", "refresh_token", "client_secret", "password"}
+
+// sensitiveUrlQuery matches sensitive query parameters of urls inside of
+// response bodies, e.g. the SAS signature of blob storage read and write urls.
+var sensitiveUrlQuery = regexp.MustCompile(`(?i)((?:\?|&|\\u0026)(?:` + strings.Join(sensitiveQueryParameters, "|") + `)=)[^&"'\s\\]+`)
+
+// Cassette stores HTTP interactions in a directory so that CLI sessions can
+// be recorded against a real tenant and replayed later without network access.
+//
+// Every interaction is saved in a separate json file. Requests are matched on
+// method, path, query string and a hash of the request body. Sensitive query
+// parameters like SAS signatures are redacted in the stored url and ignored
+// when matching requests. Response bodies are stored verbatim except for
+// tokens and sensitive query parameters of urls. Multiple
+// interactions with the same request, e.g. when polling for a status, are
+// replayed in the order they were recorded. Once all of them have been served,
+// the last response is repeated.
+type Cassette struct {
+	directory string
+	replay    bool
+	counters  map[string]int
+	mutex     sync.Mutex
+}
+
+type cassetteEntry struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method   string              `json:"method"`
+	Url      string              `json:"url"`
+	Header   map[string][]string `json:"header"`
+	BodyHash string              `json:"bodyHash"`
+}
+
+type cassetteResponse struct {
+	StatusCode   int                 `json:"statusCode"`
+	Status       string              `json:"status"`
+	Header       map[string][]string `json:"header"`
+	Body         string              `json:"body"`
+	BodyEncoding string              `json:"bodyEncoding,omitempty"`
+}
+
+func (c *Cassette) Replaying() bool {
+	return c.replay
+}
+
+// Record saves the request and response in the cassette directory.
+func (c *Cassette) Record(request *http.Request, requestBody []byte, response *http.Response, responseBody []byte) error {
+	key := c.key(request, requestBody)
+	c.mutex.Lock()
+	index := c.counters[key]
+	c.counters[key] = index + 1
+	c.mutex.Unlock()
+
+	body, encoding := c.encodeBody(c.redactBody(responseBody))
+	entry := cassetteEntry{
+		Request: cassetteRequest{
+			Method:   request.Method,
+			Url:      c.redactUrl(request.URL),
+			Header:   c.redactHeader(request.Header),
+			BodyHash: c.bodyHash(request, requestBody),
+		},
+		Response: cassetteResponse{
+			StatusCode:   response.StatusCode,
+			Status:       response.Status,
+			Header:       c.redactHeader(response.Header),
+			Body:         body,
+			BodyEncoding: encoding,
+		},
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error recording response: %w", err)
+	}
+	err = os.MkdirAll(c.directory, 0700)
+	if err != nil {
+		return fmt.Errorf("Error creating record directory '%s': %w", c.directory, err)
+	}
+	err = os.WriteFile(c.path(key, index), data, 0600)
+	if err != nil {
+		return fmt.Errorf("Error recording response: %w", err)
+	}
+	return nil
+}
+
+// Replay returns the recorded response for the given request.
+func (c *Cassette) Replay(request *http.Request, requestBody []byte) (*http.Response, error) {
+	key := c.key(request, requestBody)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	index := c.counters[key]
+	entry, err := c.read(key, index)
+	if errors.Is(err, os.ErrNotExist) && index > 0 {
+		entry, err = c.read(key, index-1)
+	} else if err == nil {
+		c.counters[key] = index + 1
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, newReplayError(fmt.Sprintf("No recorded response found for request '%s %s' in '%s'", request.Method, c.redactUrl(request.URL), c.directory))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.response(request, entry.Response)
+}
+
+func (c *Cassette) read(key string, index int) (*cassetteEntry, error) {
+	data, err := os.ReadFile(c.path(key, index))
+	if err != nil {
+		return nil, err
+	}
+	var entry cassetteEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading recorded response '%s': %w", c.path(key, index), err)
+	}
+	return &entry, nil
+}
+
+func (c *Cassette) response(request *http.Request, recorded cassetteResponse) (*http.Response, error) {
+	body := []byte(recorded.Body)
+	if recorded.BodyEncoding == cassetteBodyEncodingBase64 {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(recorded.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding recorded response body: %w", err)
+		}
+	}
+	return &http.Response{
+		StatusCode:    recorded.StatusCode,
+		Status:        recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+func (c *Cassette) key(request *http.Request, body []byte) string {
+	data := strings.Join([]string{
+		request.Method,
+		request.URL.Path,
+		c.redactQuery(request.URL.Query()).Encode(),
+		c.bodyHash(request, body),
+	}, "\n")
+	return c.hash([]byte(data))[:16]
+}
+
+func (c *Cassette) path(key string, index int) string {
+	return filepath.Join(c.directory, fmt.Sprintf("%s-%d.json", key, index))
+}
+
+// Credentials in form bodies, e.g. the client secret of token requests,
+// are redacted before hashing so that recordings can be replayed without
+// knowing the original credentials.
+func (c *Cassette) bodyHash(request *http.Request, body []byte) string {
+	contentType := request.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/") {
+		return c.multipartHash(contentType, body)
+	}
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return c.hash(body)
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return c.hash(body)
+	}
+	for name := range form {
+		if c.isSensitiveFormField(name) {
+			form.Set(name, redactedValue)
+		}
+	}
+	return c.hash([]byte(form.Encode()))
+}
+
+// multipartHash hashes the headers and content of all parts without the
+// boundary which is randomly generated for every request.
+func (c *Cassette) multipartHash(contentType string, body []byte) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["boundary"] == "" {
+		return c.hash(body)
+	}
+	hash := sha256.New()
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return c.hash(body)
+		}
+		names := []string{}
+		for name := range part.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(hash, "%s: %s\n", name, strings.Join(part.Header[name], ", "))
+		}
+		_, err = io.Copy(hash, part)
+		if err != nil {
+			return c.hash(body)
+		}
+		fmt.Fprint(hash, "\n")
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *Cassette) redactUrl(uri *url.URL) string {
+	if uri.RawQuery == "" {
+		return uri.EscapedPath()
+	}
+	return uri.EscapedPath() + "?" + c.redactQuery(uri.Query()).Encode()
+}
+
+func (c *Cassette) redactQuery(query url.Values) url.Values {
+	for name := range query {
+		if c.isSensitiveQueryParameter(name) {
+			query.Set(name, redactedValue)
+		}
+	}
+	return query
+}
+
+func (c *Cassette) isSensitiveQueryParameter(name string) bool {
+	for _, parameter := range sensitiveQueryParameters {
+		if strings.EqualFold(name, parameter) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cassette) isSensitiveFormField(name string) bool {
+	for _, field := range sensitiveFormFields {
+		if name == field {
+			return true
+		}
+	}
+	return isSensitiveHeader(name)
+}
+
+func (c *Cassette) hash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *Cassette) encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), cassetteBodyEncodingBase64
+}
+
+func (c *Cassette) redactHeader(header http.Header) map[string][]string {
+	result := map[string][]string{}
+	for name, values := range header {
+		redacted := []string{}
+		for _, value := range values {
+			redacted = append(redacted, RedactHeader(name, value))
+		}
+		result[name] = redacted
+	}
+	return result
+}
+
+func (c *Cassette) redactBody(body []byte) []byte {
+	if utf8.Valid(body) {
+		body = sensitiveUrlQuery.ReplaceAll(body, []byte("${1}"+redactedValue))
+	}
+	var data map[string]interface{}
+	err := json.Unmarshal(body, &data)
+	if err != nil {
+		return body
+	}
+	redacted := false
+	for _, property := range sensitiveBodyProperties {
+		if _, found := data[property]; found {
+			data[property] = redactedValue
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	result, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	return result
+}
+
+func NewCassette(directory string, replay bool) *Cassette {
+	return &Cassette{
+		directory: directory,
+		replay:    replay,
+		counters:  map[string]int{},
+	}
+}
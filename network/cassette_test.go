@@ -0,0 +1,179 @@
+package network
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteReplaysRecordedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+	directory := t.TempDir()
+
+	send(t, NewCassette(directory, false), "POST", server.URL+"/users?$top=5", `{"name":"John"}`)
+	response, body := send(t, NewCassette(directory, true), "POST", "https://cloud.uipath.com/users?$top=5", `{"name":"John"}`)
+
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("Expected recorded status code, but got: %v", response.StatusCode)
+	}
+	if response.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected recorded header, but got: %v", response.Header)
+	}
+	if body != `{"id":1}` {
+		t.Errorf("Expected recorded body, but got: %v", body)
+	}
+}
+
+func TestCassetteReplaysResponsesInRecordedOrder(t *testing.T) {
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		_, _ = w.Write([]byte(strings.Repeat("a", count)))
+	}))
+	defer server.Close()
+	directory := t.TempDir()
+
+	recorder := NewCassette(directory, false)
+	send(t, recorder, "GET", server.URL+"/status", "")
+	send(t, recorder, "GET", server.URL+"/status", "")
+
+	replayer := NewCassette(directory, true)
+	_, first := send(t, replayer, "GET", server.URL+"/status", "")
+	_, second := send(t, replayer, "GET", server.URL+"/status", "")
+	_, third := send(t, replayer, "GET", server.URL+"/status", "")
+
+	if first != "a" || second != "aa" || third != "aa" {
+		t.Errorf("Expected responses in recorded order, but got: %v, %v, %v", first, second, third)
+	}
+}
+
+func TestCassetteReplayWithoutRecordingReturnsError(t *testing.T) {
+	transport := NewCassetteTransport(HttpClientSettings{Cassette: NewCassette(t.TempDir(), true)}, http.DefaultTransport)
+	client := &http.Client{Transport: transport}
+
+	_, err := client.Get("https://cloud.uipath.com/ping")
+
+	var replayErr *ReplayError
+	if !errors.As(err, &replayErr) {
+		t.Fatalf("Expected replay error, but got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "No recorded response found for request 'GET /ping'") {
+		t.Errorf("Expected error message for missing recording, but got: %v", err)
+	}
+}
+
+func TestCassetteRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=my-session")
+		_, _ = w.Write([]byte(`{"access_token":"my-access-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+	directory := t.TempDir()
+
+	request, _ := http.NewRequest("POST", server.URL+"/identity_/connect/token", strings.NewReader("client_id=my-id&client_secret=my-secret"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "Bearer my-token")
+	sendRequest(t, NewCassette(directory, false), request)
+
+	files, _ := os.ReadDir(directory)
+	data, _ := os.ReadFile(filepath.Join(directory, files[0].Name()))
+	for _, secret := range []string{"my-token", "my-access-token", "my-secret", "my-session"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %v to be redacted, but got: %v", secret, string(data))
+		}
+	}
+
+	request, _ = http.NewRequest("POST", server.URL+"/identity_/connect/token", strings.NewReader("client_id=my-id&client_secret=other-secret"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, _ := sendRequest(t, NewCassette(directory, true), request)
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected token request to match recording regardless of secret, but got: %v", response.StatusCode)
+	}
+}
+
+func TestCassetteRedactsSensitiveQueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Uri":"https://my-storage.blob.core.windows.net/file.txt?sv=2020-01-01&sig=my-response-signature"}`))
+	}))
+	defer server.Close()
+	directory := t.TempDir()
+
+	send(t, NewCassette(directory, false), "PUT", server.URL+"/file.txt?sv=2020-01-01&sig=my-request-signature", "")
+
+	files, _ := os.ReadDir(directory)
+	data, _ := os.ReadFile(filepath.Join(directory, files[0].Name()))
+	for _, secret := range []string{"my-request-signature", "my-response-signature"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %v to be redacted, but got: %v", secret, string(data))
+		}
+	}
+	response, _ := send(t, NewCassette(directory, true), "PUT", server.URL+"/file.txt?sv=2020-01-01&sig=other-signature", "")
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected request to match recording regardless of signature, but got: %v", response.StatusCode)
+	}
+}
+
+func TestCassetteReplaysMultipartRequestWithDifferentBoundary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"documentId":"1"}`))
+	}))
+	defer server.Close()
+	directory := t.TempDir()
+
+	sendRequest(t, NewCassette(directory, false), newMultipartRequest(t, server.URL+"/digitize", "hello-world"))
+	response, body := sendRequest(t, NewCassette(directory, true), newMultipartRequest(t, server.URL+"/digitize", "hello-world"))
+
+	if response.StatusCode != http.StatusOK || body != `{"documentId":"1"}` {
+		t.Errorf("Expected recorded response for multipart request, but got: %v %v", response.StatusCode, body)
+	}
+	_, err := (&http.Client{Transport: NewCassetteTransport(HttpClientSettings{Cassette: NewCassette(directory, true)}, http.DefaultTransport)}).
+		Do(newMultipartRequest(t, server.URL+"/digitize", "other-content"))
+	if err == nil {
+		t.Errorf("Expected multipart request with different content not to match recording")
+	}
+}
+
+func newMultipartRequest(t *testing.T, url string, content string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "file.txt")
+	_, _ = part.Write([]byte(content))
+	writer.Close()
+	request, err := http.NewRequest("POST", url, &body)
+	if err != nil {
+		t.Fatalf("Unexpected error creating request: %v", err)
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func send(t *testing.T, cassette *Cassette, method string, url string, body string) (*http.Response, string) {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected error creating request: %v", err)
+	}
+	return sendRequest(t, cassette, request)
+}
+
+func sendRequest(t *testing.T, cassette *Cassette, request *http.Request) (*http.Response, string) {
+	client := &http.Client{Transport: NewCassetteTransport(HttpClientSettings{Cassette: cassette}, http.DefaultTransport)}
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	return response, string(body)
+}
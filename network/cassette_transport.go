@@ -0,0 +1,64 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// cassetteTransport records the requests and responses of the underlying
+// transport or replays them from the cassette without sending any request.
+type cassetteTransport struct {
+	transport http.RoundTripper
+	cassette  *Cassette
+}
+
+func (t cassetteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	requestBody, err := t.readRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+	if t.cassette.Replaying() {
+		return t.cassette.Replay(request, requestBody)
+	}
+
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	err = t.cassette.Record(request, requestBody, response, responseBody)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (t cassetteTransport) readRequestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return []byte{}, nil
+	}
+	body, err := io.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	return body, nil
+}
+
+// NewCassetteTransport wraps the transport so that all requests are recorded
+// to or replayed from the cassette configured in the settings. The transport
+// is returned unchanged when neither recording nor replaying is enabled.
+func NewCassetteTransport(settings HttpClientSettings, transport http.RoundTripper) http.RoundTripper {
+	if settings.Cassette == nil {
+		return transport
+	}
+	return &cassetteTransport{transport, settings.Cassette}
+}
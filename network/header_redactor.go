@@ -0,0 +1,36 @@
+package network
+
+import "strings"
+
+const redactedValue = "***"
+
+var sensitiveHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie"}
+var sensitiveHeaderKeywords = []string{"secret", "token", "password", "key", "license"}
+
+// RedactHeader hides the value of headers which contain credentials.
+// The authorization scheme (e.g. Bearer) is kept to ease troubleshooting.
+func RedactHeader(name string, value string) string {
+	if !isSensitiveHeader(name) {
+		return value
+	}
+	scheme, _, found := strings.Cut(value, " ")
+	if found && strings.HasSuffix(strings.ToLower(name), "authorization") {
+		return scheme + " " + redactedValue
+	}
+	return redactedValue
+}
+
+func isSensitiveHeader(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, header := range sensitiveHeaders {
+		if lowerName == header {
+			return true
+		}
+	}
+	for _, keyword := range sensitiveHeaderKeywords {
+		if strings.Contains(lowerName, keyword) {
+			return true
+		}
+	}
+	return false
+}
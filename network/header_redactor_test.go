@@ -0,0 +1,24 @@
+package network
+
+import "testing"
+
+func TestRedactHeaderKeepsAuthorizationScheme(t *testing.T) {
+	value := RedactHeader("Authorization", "Bearer my-token")
+	if value != "Bearer ***" {
+		t.Errorf("Did not redact authorization header, got: %v", value)
+	}
+}
+
+func TestRedactHeaderHidesSensitiveHeaders(t *testing.T) {
+	value := RedactHeader("X-UIPATH-License", "my-license")
+	if value != "***" {
+		t.Errorf("Did not redact license header, got: %v", value)
+	}
+}
+
+func TestRedactHeaderKeepsOtherHeaders(t *testing.T) {
+	value := RedactHeader("Content-Type", "application/json")
+	if value != "application/json" {
+		t.Errorf("Should not redact content type header, got: %v", value)
+	}
+}
@@ -0,0 +1,84 @@
+package network
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The httpClientFactory hands out HTTP clients which share their transports.
+//
+// Creating a new transport for every request means that every request pays
+// for a new TCP connection and TLS handshake. The factory keeps a single
+// transport per distinct TLS, proxy and connection pool configuration so that
+// retries, --wait polling and multi-step plugin commands reuse the pooled
+// keep-alive connections.
+type httpClientFactory struct {
+	transports map[transportKey]*http.Transport
+	mutex      sync.Mutex
+}
+
+type transportKey struct {
+	insecure            bool
+	proxy               string
+	caCert              string
+	clientCert          string
+	clientKey           string
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+}
+
+var defaultHttpClientFactory = newHttpClientFactory()
+
+// NewHttpClient returns an HTTP client for the given settings which reuses
+// the pooled connections of all clients created with the same settings.
+func NewHttpClient(settings HttpClientSettings) (*http.Client, error) {
+	return defaultHttpClientFactory.Client(settings)
+}
+
+func (f *httpClientFactory) Client(settings HttpClientSettings) (*http.Client, error) {
+	transport, err := f.transport(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: NewTracingTransport(NewCassetteTransport(settings, NewRateLimitTransport(settings, NewTimingTransport(settings, transport))))}, nil
+}
+
+func (f *httpClientFactory) transport(settings HttpClientSettings) (*http.Transport, error) {
+	key := f.key(settings)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if transport, found := f.transports[key]; found {
+		return transport, nil
+	}
+	transport, err := NewTransport(settings)
+	if err != nil {
+		return nil, err
+	}
+	f.transports[key] = transport
+	return transport, nil
+}
+
+func (f *httpClientFactory) key(settings HttpClientSettings) transportKey {
+	proxy := ""
+	if settings.Proxy != nil {
+		proxy = settings.Proxy.String()
+	}
+	return transportKey{
+		insecure:            settings.Insecure,
+		proxy:               proxy,
+		caCert:              settings.CaCert,
+		clientCert:          settings.ClientCert,
+		clientKey:           settings.ClientKey,
+		maxIdleConns:        settings.MaxIdleConns,
+		maxIdleConnsPerHost: settings.MaxIdleConnsPerHost,
+		idleConnTimeout:     settings.IdleConnTimeout,
+	}
+}
+
+func newHttpClientFactory() *httpClientFactory {
+	return &httpClientFactory{
+		transports: map[transportKey]*http.Transport{},
+	}
+}
@@ -0,0 +1,133 @@
+package network
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpClientReusesConnections(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	for i := 0; i < 5; i++ {
+		client, err := NewHttpClient(HttpClientSettings{})
+		if err != nil {
+			t.Fatalf("Unexpected error creating client: %v", err)
+		}
+		getAndDiscard(t, client, server.URL)
+	}
+
+	if atomic.LoadInt32(&connections) != 1 {
+		t.Errorf("Expected a single reused connection, but got: %v", connections)
+	}
+}
+
+func TestHttpClientReusesConnectionsFaster(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		client := &http.Client{Transport: newInsecureTransport(t)}
+		getAndDiscard(t, client, server.URL)
+	}
+	newConnections := time.Since(start)
+
+	client, err := NewHttpClient(HttpClientSettings{Insecure: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating client: %v", err)
+	}
+	getAndDiscard(t, client, server.URL)
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		client, _ := NewHttpClient(HttpClientSettings{Insecure: true})
+		getAndDiscard(t, client, server.URL)
+	}
+	reusedConnections := time.Since(start)
+
+	if reusedConnections >= newConnections {
+		t.Errorf("Expected reused connections (%v) to be faster than new connections (%v)", reusedConnections, newConnections)
+	}
+}
+
+func TestHttpClientNegotiatesHttp2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := NewHttpClient(HttpClientSettings{Insecure: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating client: %v", err)
+	}
+	response := getAndDiscard(t, client, server.URL)
+
+	if response.ProtoMajor != 2 {
+		t.Errorf("Expected HTTP/2, but got: %v", response.Proto)
+	}
+}
+
+func TestHttpClientSeparatesTransportsBySettings(t *testing.T) {
+	factory := newHttpClientFactory()
+
+	first, _ := factory.transport(HttpClientSettings{})
+	second, _ := factory.transport(HttpClientSettings{})
+	insecure, _ := factory.transport(HttpClientSettings{Insecure: true})
+
+	if first != second {
+		t.Errorf("Expected same transport for identical settings")
+	}
+	if first == insecure {
+		t.Errorf("Expected different transport for different TLS settings")
+	}
+}
+
+func TestHttpClientAppliesIdleLimits(t *testing.T) {
+	settings := HttpClientSettings{MaxIdleConns: 5, MaxIdleConnsPerHost: 2, IdleConnTimeout: 10 * time.Second}
+
+	transport, err := NewTransport(settings)
+	if err != nil {
+		t.Fatalf("Unexpected error creating transport: %v", err)
+	}
+	if transport.MaxIdleConns != 5 || transport.MaxIdleConnsPerHost != 2 || transport.IdleConnTimeout != 10*time.Second {
+		t.Errorf("Expected configured idle limits, but got: %v, %v, %v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+func newInsecureTransport(t *testing.T) *http.Transport {
+	transport, err := NewTransport(HttpClientSettings{Insecure: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating transport: %v", err)
+	}
+	transport.DisableKeepAlives = true
+	return transport
+}
+
+func getAndDiscard(t *testing.T, client *http.Client, url string) *http.Response {
+	response, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+	return response
+}
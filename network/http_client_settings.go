@@ -0,0 +1,34 @@
+// Package network contains the settings and helpers for creating the HTTP
+// clients which are used to call the UiPath services.
+package network
+
+import (
+	"net/url"
+	"time"
+)
+
+// HttpClientSettings control how connections to the services are established.
+//
+// The Proxy overrides the proxy from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// environment variables. The CaCert is a PEM bundle which is appended to the
+// system certificate pool. ClientCert and ClientKey are PEM files used for
+// mutual TLS authentication. The Cassette is used to record or replay
+// the HTTP interactions and is nil when neither is enabled.
+// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout limit the pool of
+// keep-alive connections; zero values use the defaults. The RateLimiter
+// queues requests which exceed the configured rate limit and is nil when no
+// rate limit is configured. The Timing reporter prints the timing breakdown
+// of every request and is nil when timing is disabled.
+type HttpClientSettings struct {
+	Insecure            bool
+	Proxy               *url.URL
+	CaCert              string
+	ClientCert          string
+	ClientKey           string
+	Cassette            *Cassette
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	RateLimiter         *RateLimiter
+	Timing              *TimingReporter
+}
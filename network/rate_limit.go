@@ -0,0 +1,53 @@
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is the maximum number of requests which are sent within the
+// interval, e.g. 20/s allows 20 requests per second.
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Interval)
+}
+
+// ParseRateLimit parses rate limits in the format <requests>/<interval>.
+// The interval is either a unit (s, m, h) or a duration like 10s.
+func ParseRateLimit(value string) (*RateLimit, error) {
+	requestsValue, intervalValue, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return nil, fmt.Errorf("Invalid rate limit '%s', expected format: <requests>/<interval>, e.g. 20/s", value)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsValue))
+	if err != nil || requests < 1 {
+		return nil, fmt.Errorf("Invalid rate limit '%s', number of requests must be at least 1", value)
+	}
+	interval, err := parseRateLimitInterval(strings.TrimSpace(intervalValue))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("Invalid rate limit '%s', interval must be s, m, h or a positive duration", value)
+	}
+	return NewRateLimit(requests, interval), nil
+}
+
+func parseRateLimitInterval(value string) (time.Duration, error) {
+	switch value {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func NewRateLimit(requests int, interval time.Duration) *RateLimit {
+	return &RateLimit{requests, interval}
+}
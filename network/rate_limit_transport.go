@@ -0,0 +1,30 @@
+package network
+
+import (
+	"net/http"
+)
+
+// rateLimitTransport waits for the rate limiter before every request is
+// passed to the underlying transport.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *RateLimiter
+}
+
+func (t rateLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	err := t.limiter.Wait(request.Context())
+	if err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(request)
+}
+
+// NewRateLimitTransport wraps the transport so that all requests respect the
+// rate limiter configured in the settings. The transport is returned
+// unchanged when no rate limit is configured.
+func NewRateLimitTransport(settings HttpClientSettings, transport http.RoundTripper) http.RoundTripper {
+	if settings.RateLimiter == nil {
+		return transport
+	}
+	return &rateLimitTransport{transport, settings.RateLimiter}
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// The RateLimiter is a token bucket which limits the rate of outgoing requests.
+//
+// The bucket holds up to the number of requests of the rate limit and is
+// refilled continuously. Requests which exceed the limit are queued: every
+// caller reserves the next free token and waits until it becomes available
+// instead of failing the request.
+type RateLimiter struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	mutex  sync.Mutex
+}
+
+var rateLimiters = map[string]*RateLimiter{}
+var rateLimitersMutex sync.Mutex
+
+// Wait blocks until the request is allowed to be sent or the context is
+// cancelled.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	err := utils.Sleep(ctx, delay)
+	if err != nil {
+		l.cancel()
+	}
+	return err
+}
+
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	burst := float64(l.limit.Requests)
+	l.tokens += now.Sub(l.last).Seconds() * l.rate()
+	if l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate() * float64(time.Second))
+}
+
+// cancel returns the reserved token to the bucket when the caller stops
+// waiting so that queued requests are not delayed unnecessarily.
+func (l *RateLimiter) cancel() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.tokens++
+}
+
+func (l *RateLimiter) rate() float64 {
+	return float64(l.limit.Requests) / l.limit.Interval.Seconds()
+}
+
+func newRateLimiter(limit RateLimit, now time.Time) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		tokens: float64(limit.Requests),
+		last:   now,
+	}
+}
+
+// NewSharedRateLimiter returns the rate limiter for the given key. All
+// callers in the process using the same key and limit share the same bucket,
+// e.g. the concurrent operations of a batch calling the same service.
+func NewSharedRateLimiter(key string, limit RateLimit) *RateLimiter {
+	key = key + "|" + limit.String()
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+	if limiter, found := rateLimiters[key]; found {
+		return limiter
+	}
+	limiter := newRateLimiter(limit, time.Now())
+	rateLimiters[key] = limiter
+	return limiter
+}
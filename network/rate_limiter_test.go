@@ -0,0 +1,99 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowsBurstUpToLimit(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(*NewRateLimit(2, time.Second), now)
+
+	first := limiter.reserve(now)
+	second := limiter.reserve(now)
+
+	if first != 0 || second != 0 {
+		t.Errorf("Should not delay requests within the limit, but got: %v, %v", first, second)
+	}
+}
+
+func TestRateLimiterQueuesRequestsExceedingLimit(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(*NewRateLimit(2, time.Second), now)
+	limiter.reserve(now)
+	limiter.reserve(now)
+
+	third := limiter.reserve(now)
+	fourth := limiter.reserve(now)
+
+	if third != 500*time.Millisecond || fourth != time.Second {
+		t.Errorf("Should queue requests exceeding the limit, but got: %v, %v", third, fourth)
+	}
+}
+
+func TestRateLimiterRefillsTokens(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(*NewRateLimit(2, time.Second), now)
+	limiter.reserve(now)
+	limiter.reserve(now)
+
+	delay := limiter.reserve(now.Add(time.Second))
+
+	if delay != 0 {
+		t.Errorf("Should refill tokens over time, but got: %v", delay)
+	}
+}
+
+func TestRateLimiterWaitReturnsErrorWhenCancelled(t *testing.T) {
+	limiter := newRateLimiter(*NewRateLimit(1, time.Hour), time.Now())
+	_ = limiter.Wait(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := limiter.Wait(ctx)
+
+	if err != context.Canceled {
+		t.Errorf("Should return cancellation error, but got: %v", err)
+	}
+	if limiter.tokens < 0 {
+		t.Errorf("Should return reserved token when cancelled, but got: %v", limiter.tokens)
+	}
+}
+
+func TestSharedRateLimiterReturnsSameLimiterForKey(t *testing.T) {
+	limit := *NewRateLimit(20, time.Second)
+
+	first := NewSharedRateLimiter("profile|service", limit)
+	second := NewSharedRateLimiter("profile|service", limit)
+	other := NewSharedRateLimiter("profile|other", limit)
+
+	if first != second || first == other {
+		t.Errorf("Should share limiter for the same key only")
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := map[string]RateLimit{
+		"20/s":   {20, time.Second},
+		"100/m":  {100, time.Minute},
+		"1000/h": {1000, time.Hour},
+		"5/10s":  {5, 10 * time.Second},
+		" 3 / s": {3, time.Second},
+	}
+	for value, expected := range tests {
+		rateLimit, err := ParseRateLimit(value)
+		if err != nil || *rateLimit != expected {
+			t.Errorf("Should parse rate limit %v, but got: %v, %v", value, rateLimit, err)
+		}
+	}
+}
+
+func TestParseInvalidRateLimit(t *testing.T) {
+	for _, value := range []string{"20", "abc/s", "0/s", "5/x", "5/-1s"} {
+		_, err := ParseRateLimit(value)
+		if err == nil {
+			t.Errorf("Should return error for invalid rate limit %v", value)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package network
+
+// ReplayError is returned when no recorded response matches the request
+// during replay. It is permanent and therefore never retried.
+type ReplayError struct {
+	message string
+}
+
+func (e ReplayError) Error() string {
+	return e.message
+}
+
+func (e ReplayError) Permanent() bool {
+	return true
+}
+
+func newReplayError(message string) *ReplayError {
+	return &ReplayError{message}
+}
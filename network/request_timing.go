@@ -0,0 +1,35 @@
+package network
+
+import (
+	"time"
+)
+
+// RequestTiming is the timing breakdown of a single HTTP request.
+//
+// The phases are measured using httptrace. DNS, Connect and TlsHandshake are
+// zero when an existing keep-alive connection is reused. TimeToFirstByte is
+// measured from the start of the request and Transfer is the time it took to
+// read the response body.
+type RequestTiming struct {
+	Method          string        `json:"method"`
+	Url             string        `json:"url"`
+	StatusCode      int           `json:"status,omitempty"`
+	Attempt         int           `json:"attempt"`
+	Reused          bool          `json:"reused"`
+	DNS             time.Duration `json:"-"`
+	Connect         time.Duration `json:"-"`
+	TlsHandshake    time.Duration `json:"-"`
+	TimeToFirstByte time.Duration `json:"-"`
+	Transfer        time.Duration `json:"-"`
+	Total           time.Duration `json:"-"`
+	Bytes           int64         `json:"bytes"`
+	Error           string        `json:"error,omitempty"`
+}
+
+func NewRequestTiming(method string, url string, attempt int) *RequestTiming {
+	return &RequestTiming{
+		Method:  method,
+		Url:     url,
+		Attempt: attempt,
+	}
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// The TimingReporter writes the timing breakdown of every request, either as
+// a single line or as a JSON block when debug output is enabled.
+type TimingReporter struct {
+	writer io.Writer
+	json   bool
+	mutex  *sync.Mutex
+}
+
+type requestTimingJson struct {
+	RequestTiming
+	DnsMs             float64 `json:"dnsMs"`
+	ConnectMs         float64 `json:"connectMs"`
+	TlsHandshakeMs    float64 `json:"tlsHandshakeMs"`
+	TimeToFirstByteMs float64 `json:"timeToFirstByteMs"`
+	TransferMs        float64 `json:"transferMs"`
+	TotalMs           float64 `json:"totalMs"`
+}
+
+func (r TimingReporter) Report(timing RequestTiming) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.json {
+		r.writeJson(timing)
+		return
+	}
+	status := "failed"
+	if timing.StatusCode != 0 {
+		status = fmt.Sprintf("%d", timing.StatusCode)
+	}
+	fmt.Fprintf(r.writer, "Timing: %s %s %s attempt=%d dns=%s connect=%s tls=%s ttfb=%s transfer=%s total=%s bytes=%d\n",
+		timing.Method,
+		timing.Url,
+		status,
+		timing.Attempt,
+		r.format(timing.DNS),
+		r.format(timing.Connect),
+		r.format(timing.TlsHandshake),
+		r.format(timing.TimeToFirstByte),
+		r.format(timing.Transfer),
+		r.format(timing.Total),
+		timing.Bytes)
+}
+
+// WithWriter returns a reporter which writes to the given writer, e.g. the
+// same stream the logger writes to, so that the output is not interleaved.
+func (r TimingReporter) WithWriter(writer io.Writer) *TimingReporter {
+	return &TimingReporter{writer, r.json, r.mutex}
+}
+
+func (r TimingReporter) writeJson(timing RequestTiming) {
+	data, err := json.MarshalIndent(requestTimingJson{
+		RequestTiming:     timing,
+		DnsMs:             r.milliseconds(timing.DNS),
+		ConnectMs:         r.milliseconds(timing.Connect),
+		TlsHandshakeMs:    r.milliseconds(timing.TlsHandshake),
+		TimeToFirstByteMs: r.milliseconds(timing.TimeToFirstByte),
+		TransferMs:        r.milliseconds(timing.Transfer),
+		TotalMs:           r.milliseconds(timing.Total),
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(r.writer, "%s\n\n", data)
+}
+
+func (r TimingReporter) format(duration time.Duration) string {
+	return fmt.Sprintf("%.1fms", r.milliseconds(duration))
+}
+
+func (r TimingReporter) milliseconds(duration time.Duration) float64 {
+	return float64(duration.Microseconds()) / 1000
+}
+
+func NewTimingReporter(writer io.Writer, json bool) *TimingReporter {
+	return &TimingReporter{writer, json, &sync.Mutex{}}
+}
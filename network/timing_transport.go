@@ -0,0 +1,156 @@
+package network
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// timingTransport instruments every request with httptrace and reports the
+// timing breakdown once the response body has been read and closed.
+type timingTransport struct {
+	transport http.RoundTripper
+	reporter  *TimingReporter
+}
+
+func (t timingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	trace := newRequestTrace(request)
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace.ClientTrace()))
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		t.reporter.Report(trace.Finish(0, err))
+		return nil, err
+	}
+	response.Body = &timingBody{
+		body:       response.Body,
+		trace:      trace,
+		reporter:   t.reporter,
+		statusCode: response.StatusCode,
+	}
+	return response, nil
+}
+
+// requestTrace collects the timestamps of the httptrace callbacks. The
+// callbacks can be invoked concurrently, e.g. when dialing multiple addresses.
+type requestTrace struct {
+	timing       RequestTiming
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	firstByte    time.Time
+	mutex        sync.Mutex
+}
+
+func (t *requestTrace) ClientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timing.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timing.Connect = time.Since(t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timing.TlsHandshake = time.Since(t.tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timing.Reused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.firstByte = time.Now()
+			t.timing.TimeToFirstByte = t.firstByte.Sub(t.start)
+		},
+	}
+}
+
+func (t *requestTrace) Finish(statusCode int, err error) RequestTiming {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := time.Now()
+	if !t.firstByte.IsZero() {
+		t.timing.Transfer = now.Sub(t.firstByte)
+	}
+	t.timing.Total = now.Sub(t.start)
+	t.timing.StatusCode = statusCode
+	if err != nil {
+		t.timing.Error = err.Error()
+	}
+	return t.timing
+}
+
+func (t *requestTrace) AddBytes(n int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.timing.Bytes += int64(n)
+}
+
+func newRequestTrace(request *http.Request) *requestTrace {
+	attempt := utils.RetryAttempt(request.Context())
+	return &requestTrace{
+		timing: *NewRequestTiming(request.Method, request.URL.String(), attempt),
+		start:  time.Now(),
+	}
+}
+
+// timingBody counts the bytes of the response body and reports the timing
+// when the body is closed.
+type timingBody struct {
+	body       io.ReadCloser
+	trace      *requestTrace
+	reporter   *TimingReporter
+	statusCode int
+	once       sync.Once
+}
+
+func (b *timingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.trace.AddBytes(n)
+	return n, err
+}
+
+func (b *timingBody) Close() error {
+	err := b.body.Close()
+	b.once.Do(func() {
+		b.reporter.Report(b.trace.Finish(b.statusCode, nil))
+	})
+	return err
+}
+
+// NewTimingTransport wraps the transport so that the timing of all requests
+// is reported. The transport is returned unchanged when timing is disabled.
+func NewTimingTransport(settings HttpClientSettings, transport http.RoundTripper) http.RoundTripper {
+	if settings.Timing == nil {
+		return transport
+	}
+	return &timingTransport{transport, settings.Timing}
+}
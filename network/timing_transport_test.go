@@ -0,0 +1,100 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+func TestTimingReportsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	output := &bytes.Buffer{}
+	settings := HttpClientSettings{Timing: NewTimingReporter(output, false)}
+
+	timedGet(t, settings, context.Background(), server.URL)
+
+	expected := "Timing: GET " + server.URL + " 200 attempt=1 dns="
+	if !strings.HasPrefix(output.String(), expected) {
+		t.Errorf("Expected timing line %v, but got: %v", expected, output.String())
+	}
+	if !strings.HasSuffix(output.String(), " bytes=5\n") {
+		t.Errorf("Expected received bytes in timing line, but got: %v", output.String())
+	}
+}
+
+func TestTimingReportsRetryAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	output := &bytes.Buffer{}
+	settings := HttpClientSettings{Timing: NewTimingReporter(output, false)}
+
+	timedGet(t, settings, utils.WithRetryAttempt(context.Background(), 3), server.URL)
+
+	if !strings.Contains(output.String(), " attempt=3 ") {
+		t.Errorf("Expected retry attempt in timing line, but got: %v", output.String())
+	}
+}
+
+func TestTimingReportsJsonBlock(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	output := &bytes.Buffer{}
+	settings := HttpClientSettings{Insecure: true, Timing: NewTimingReporter(output, true)}
+
+	timedGet(t, settings, context.Background(), server.URL)
+
+	timing := map[string]interface{}{}
+	err := json.Unmarshal(output.Bytes(), &timing)
+	if err != nil {
+		t.Fatalf("Expected json timing block, but got: %v", output.String())
+	}
+	if timing["status"] != 200.0 || timing["bytes"] != 5.0 || timing["attempt"] != 1.0 {
+		t.Errorf("Expected request details in timing block, but got: %v", timing)
+	}
+	if timing["tlsHandshakeMs"].(float64) <= 0 || timing["totalMs"].(float64) <= 0 {
+		t.Errorf("Expected tls handshake and total time, but got: %v", timing)
+	}
+}
+
+func TestTimingReportsFailedRequest(t *testing.T) {
+	output := &bytes.Buffer{}
+	settings := HttpClientSettings{Timing: NewTimingReporter(output, false)}
+	client, _ := NewHttpClient(settings)
+
+	_, err := client.Get("http://127.0.0.1:1")
+
+	if err == nil {
+		t.Fatalf("Expected connection error")
+	}
+	if !strings.HasPrefix(output.String(), "Timing: GET http://127.0.0.1:1 failed attempt=1") {
+		t.Errorf("Expected failed timing line, but got: %v", output.String())
+	}
+}
+
+func timedGet(t *testing.T, settings HttpClientSettings, ctx context.Context, url string) {
+	client, err := NewHttpClient(settings)
+	if err != nil {
+		t.Fatalf("Unexpected error creating client: %v", err)
+	}
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+}
@@ -0,0 +1,48 @@
+package network
+
+import (
+	"net/http"
+
+	"github.com/UiPath/uipathcli/tracing"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// tracingTransport records a client span for every request and propagates
+// the trace context to the service using the W3C traceparent header.
+type tracingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t tracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(request.Context(), "HTTP "+request.Method, tracing.SpanKindClient)
+	if span == nil {
+		return t.transport.RoundTrip(request)
+	}
+	request = request.Clone(ctx)
+	if request.Header.Get("traceparent") == "" {
+		request.Header.Set("traceparent", span.Traceparent())
+	}
+	span.SetAttribute("http.request.method", request.Method)
+	span.SetAttribute("url.full", request.URL.String())
+	span.SetAttribute("server.address", request.URL.Hostname())
+	if attempt := utils.RetryAttempt(request.Context()); attempt > 1 {
+		span.SetAttribute("http.request.resend_count", attempt-1)
+	}
+	if requestId := request.Header.Get("x-request-id"); requestId != "" {
+		span.SetAttribute("http.request.header.x-request-id", requestId)
+	}
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	span.SetAttribute("http.response.status_code", response.StatusCode)
+	span.End(nil)
+	return response, nil
+}
+
+// NewTracingTransport wraps the transport so that the requests which are
+// sent as part of a trace are recorded and carry the trace context.
+func NewTracingTransport(transport http.RoundTripper) http.RoundTripper {
+	return &tracingTransport{transport}
+}
@@ -0,0 +1,121 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const DefaultMaxIdleConns = 100
+const DefaultMaxIdleConnsPerHost = 10
+const DefaultIdleConnTimeout = 90 * time.Second
+
+const responseHeaderTimeout = 60 * time.Second
+
+// NewTransport creates an HTTP transport which uses the proxy and TLS
+// configuration from the provided settings.
+//
+// Connections are kept alive and pooled according to the idle limits of the
+// settings. HTTP/2 is negotiated when the server supports it.
+func NewTransport(settings HttpClientSettings) (*http.Transport, error) {
+	tlsConfig, err := newTlsConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	proxy := http.ProxyFromEnvironment
+	if settings.Proxy != nil {
+		proxy = explicitProxy(*settings.Proxy)
+	}
+	maxIdleConns := settings.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = DefaultMaxIdleConns
+	}
+	maxIdleConnsPerHost := settings.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	idleConnTimeout := settings.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = DefaultIdleConnTimeout
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}, nil
+}
+
+// explicitProxy sends all requests through the provided proxy except for the
+// hosts excluded by the NO_PROXY environment variable.
+func explicitProxy(proxyUrl url.URL) func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	config.HTTPProxy = proxyUrl.String()
+	config.HTTPSProxy = proxyUrl.String()
+	proxyFunc := config.ProxyFunc()
+	return func(request *http.Request) (*url.URL, error) {
+		return proxyFunc(request.URL)
+	}
+}
+
+func newTlsConfig(settings HttpClientSettings) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: settings.Insecure} //nolint // This is user configurable and disabled by default
+	if settings.CaCert != "" {
+		rootCAs, err := loadCaCert(settings.CaCert)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = rootCAs
+	}
+	if settings.ClientCert != "" {
+		certificate, err := loadClientCert(settings.ClientCert, settings.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{*certificate}
+	}
+	return config, nil
+}
+
+func loadCaCert(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading CA certificate file '%s': %w", path, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("Error reading CA certificate file '%s': no PEM encoded certificates found", path)
+	}
+	return pool, nil
+}
+
+func loadClientCert(certPath string, keyPath string) (*tls.Certificate, error) {
+	if keyPath == "" {
+		keyPath = certPath
+	}
+	certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading client certificate '%s' with key '%s': %w", certPath, keyPath, err)
+	}
+	return &certificate, nil
+}
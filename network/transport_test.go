@@ -0,0 +1,193 @@
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportUsesExplicitProxy(t *testing.T) {
+	requestUrl := ""
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUrl = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+	proxyUrl, _ := url.Parse(proxy.URL)
+
+	transport, err := NewTransport(HttpClientSettings{Proxy: proxyUrl})
+	if err != nil {
+		t.Fatalf("Unexpected error creating transport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	response, err := client.Get("http://my-service.invalid/ping")
+	if err != nil {
+		t.Fatalf("Unexpected error sending request: %v", err)
+	}
+	defer response.Body.Close()
+
+	if requestUrl != "http://my-service.invalid/ping" {
+		t.Errorf("Expected request to be sent through proxy, but got: %v", requestUrl)
+	}
+}
+
+func TestTransportExplicitProxyHonorsNoProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "my-service.invalid")
+	t.Setenv("no_proxy", "my-service.invalid")
+	proxyUrl, _ := url.Parse("http://my-proxy.invalid:8080")
+
+	transport, err := NewTransport(HttpClientSettings{Proxy: proxyUrl})
+	if err != nil {
+		t.Fatalf("Unexpected error creating transport: %v", err)
+	}
+	request, _ := http.NewRequest(http.MethodGet, "http://my-service.invalid/ping", nil)
+	proxy, _ := transport.Proxy(request)
+	if proxy != nil {
+		t.Errorf("Expected request to bypass proxy, but got: %v", proxy)
+	}
+	request, _ = http.NewRequest(http.MethodGet, "http://other-service.invalid/ping", nil)
+	proxy, _ = transport.Proxy(request)
+	if proxy == nil || proxy.String() != "http://my-proxy.invalid:8080" {
+		t.Errorf("Expected request to use explicit proxy, but got: %v", proxy)
+	}
+}
+
+func TestTransportTrustsProvidedCaCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caCert := writePem(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	response, err := get(t, server.URL, HttpClientSettings{CaCert: caCert})
+	if err != nil {
+		t.Fatalf("Expected server certificate to be trusted, but got: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, but got: %v", response.StatusCode)
+	}
+}
+
+func TestTransportRejectsUnknownCertificateWithoutCaCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := get(t, server.URL, HttpClientSettings{})
+	if err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Expected certificate error, but got: %v", err)
+	}
+}
+
+func TestTransportInvalidCaCertReturnsError(t *testing.T) {
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	_ = os.WriteFile(caCert, []byte("invalid"), 0600)
+
+	_, err := NewTransport(HttpClientSettings{CaCert: caCert})
+
+	if err == nil || !strings.Contains(err.Error(), "no PEM encoded certificates found") {
+		t.Errorf("Expected invalid CA certificate error, but got: %v", err)
+	}
+}
+
+func TestTransportMissingClientCertReturnsError(t *testing.T) {
+	clientCert := filepath.Join(t.TempDir(), "not-found.pem")
+
+	_, err := NewTransport(HttpClientSettings{ClientCert: clientCert})
+
+	if err == nil || !strings.HasPrefix(err.Error(), "Error loading client certificate") {
+		t.Errorf("Expected client certificate error, but got: %v", err)
+	}
+}
+
+func TestTransportSendsClientCertificate(t *testing.T) {
+	certificate, key := createCertificate(t)
+	clientCert := writePem(t, "client.pem", "CERTIFICATE", certificate.Raw)
+	clientKey := writePem(t, "client.key", "EC PRIVATE KEY", key)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(certificate)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	response, err := get(t, server.URL, HttpClientSettings{Insecure: true, ClientCert: clientCert, ClientKey: clientKey})
+	if err != nil {
+		t.Fatalf("Expected client certificate to be accepted, but got: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, but got: %v", response.StatusCode)
+	}
+}
+
+func get(t *testing.T, url string, settings HttpClientSettings) (*http.Response, error) {
+	transport, err := NewTransport(settings)
+	if err != nil {
+		t.Fatalf("Unexpected error creating transport: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	return response, nil
+}
+
+func createCertificate(t *testing.T) (*x509.Certificate, []byte) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "uipathcli"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	data, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate, key
+}
+
+func writePem(t *testing.T, name string, blockType string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	content := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+	err := os.WriteFile(path, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
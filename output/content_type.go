@@ -0,0 +1,21 @@
+package output
+
+import (
+	"net/http"
+	"strings"
+)
+
+// isRawContent returns true for responses which are neither json nor text,
+// e.g. binary files. These responses are written as-is without trying to
+// parse them as json. Newline delimited json streams are written as-is as
+// well so that every record stays on a single line.
+func isRawContent(header map[string][]string) bool {
+	contentType := strings.ToLower(http.Header(header).Get("Content-Type"))
+	if contentType == "" {
+		return false
+	}
+	if strings.Contains(contentType, "ndjson") {
+		return true
+	}
+	return !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/")
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// The FileOutputWriter streams the response body to a file on disk.
+//
+// It is used when the --out-file parameter is provided. The body is written to
+// a temporary file in the target directory which is renamed once the response
+// has been fully received, so the target file never contains partial data.
+// Error responses are passed to the fallback writer and printed as usual.
+type FileOutputWriter struct {
+	path     string
+	fallback OutputWriter
+}
+
+func (w FileOutputWriter) WriteResponse(response ResponseInfo) error {
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return w.fallback.WriteResponse(response)
+	}
+	file, err := os.CreateTemp(filepath.Dir(w.path), "."+filepath.Base(w.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("Error creating file '%s': %w", w.path, err)
+	}
+	err = w.write(file, response.Body)
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("Error writing file '%s': %w", w.path, err)
+	}
+	err = os.Rename(file.Name(), w.path)
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("Error writing file '%s': %w", w.path, err)
+	}
+	return nil
+}
+
+func (w FileOutputWriter) write(file *os.File, body io.Reader) error {
+	_, err := io.Copy(file, body)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Chmod(0644)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func NewFileOutputWriter(path string, fallback OutputWriter) *FileOutputWriter {
+	return &FileOutputWriter{path, fallback}
+}
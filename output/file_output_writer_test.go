@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileWriterWritesResponseBodyToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.bin")
+	writer := NewFileOutputWriter(path, NewJsonOutputWriter(&bytes.Buffer{}, NewDefaultTransformer()))
+
+	err := writer.WriteResponse(*NewResponseInfo(200, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader([]byte{0, 1, 2})))
+
+	if err != nil {
+		t.Errorf("Writing response failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.Equal(data, []byte{0, 1, 2}) {
+		t.Errorf("Should write response body to file, but got: %v", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Should not leave temporary files behind, but got: %v", entries)
+	}
+}
+
+func TestFileWriterPassesErrorResponseToFallback(t *testing.T) {
+	var output bytes.Buffer
+	path := filepath.Join(t.TempDir(), "result.bin")
+	writer := NewFileOutputWriter(path, NewJsonOutputWriter(&output, NewDefaultTransformer()))
+
+	err := writer.WriteResponse(*NewResponseInfo(404, "404 NotFound", "HTTP/1.1", map[string][]string{}, bytes.NewReader([]byte(`{"message":"Not found"}`))))
+
+	if err != nil {
+		t.Errorf("Writing response failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Should not create file for error response, but got: %v", err)
+	}
+	if output.String() != "{\n  \"message\": \"Not found\"\n}\n" {
+		t.Errorf("Should write error response to fallback writer, but got: %v", output.String())
+	}
+}
+
+func TestFileWriterDirectoryDoesNotExistReturnsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unknown", "result.bin")
+	writer := NewFileOutputWriter(path, NewJsonOutputWriter(&bytes.Buffer{}, NewDefaultTransformer()))
+
+	err := writer.WriteResponse(*NewResponseInfo(200, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader([]byte("data"))))
+
+	if err == nil {
+		t.Errorf("Should return error when directory does not exist")
+	}
+}
@@ -41,6 +41,10 @@ func (w JsonOutputWriter) writeBody(body []byte) error {
 }
 
 func (w JsonOutputWriter) WriteResponse(response ResponseInfo) error {
+	if isRawContent(response.Header) && response.StatusCode < 400 {
+		_, err := io.Copy(w.output, response.Body)
+		return err
+	}
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		return err
@@ -49,3 +49,33 @@ func TestJsonWriterOutputsPlainBodyOnJsonParsingError(t *testing.T) {
 		t.Errorf("Should show response plain body, but got: %v", output.String())
 	}
 }
+
+func TestJsonWriterOutputsBinaryContentUnmodified(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewJsonOutputWriter(&output, NewDefaultTransformer())
+
+	header := map[string][]string{"Content-Type": {"application/octet-stream"}}
+	err := writer.WriteResponse(*NewResponseInfo(200, "200 OK", "HTTP/1.1", header, bytes.NewReader([]byte(`{"hello":"world"}`))))
+
+	if err != nil {
+		t.Errorf("Writing response failed: %v", err)
+	}
+	if output.String() != `{"hello":"world"}` {
+		t.Errorf("Should output binary content unmodified, but got: %v", output.String())
+	}
+}
+
+func TestJsonWriterOutputsNdjsonAsIs(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewJsonOutputWriter(&output, NewDefaultTransformer())
+
+	header := map[string][]string{"Content-Type": {"application/x-ndjson"}}
+	err := writer.WriteResponse(*NewResponseInfo(200, "200 OK", "HTTP/1.1", header, bytes.NewReader([]byte("{\"hello\":\"world\"}\n"))))
+
+	if err != nil {
+		t.Errorf("Writing response failed: %v", err)
+	}
+	if output.String() != "{\"hello\":\"world\"}\n" {
+		t.Errorf("Should show ndjson record on a single line, but got: %v", output.String())
+	}
+}
@@ -0,0 +1,22 @@
+package output
+
+import (
+	"io"
+)
+
+// The RawOutputWriter streams the response body unmodified to standard output.
+//
+// It is used when the --out-file - parameter is provided and avoids buffering
+// or reformatting binary responses.
+type RawOutputWriter struct {
+	output io.Writer
+}
+
+func (w RawOutputWriter) WriteResponse(response ResponseInfo) error {
+	_, err := io.Copy(w.output, response.Body)
+	return err
+}
+
+func NewRawOutputWriter(output io.Writer) *RawOutputWriter {
+	return &RawOutputWriter{output}
+}
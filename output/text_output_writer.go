@@ -136,6 +136,10 @@ func (w TextOutputWriter) writeBody(body []byte) error {
 }
 
 func (w TextOutputWriter) WriteResponse(response ResponseInfo) error {
+	if isRawContent(response.Header) && response.StatusCode < 400 {
+		_, err := io.Copy(w.output, response.Body)
+		return err
+	}
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		return err
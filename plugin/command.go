@@ -3,12 +3,15 @@ package plugin
 // Command is used to define the metadata of the plugin.
 //
 // Command defines the service name, command name and its available parameters.
+// Commands which set DryRun handle the --dry-run flag themselves by only
+// reporting what they would do.
 type Command struct {
 	Service     string
 	Name        string
 	Description string
 	Parameters  []CommandParameter
 	Hidden      bool
+	DryRun      bool
 	Category    *CommandCategory
 }
 
@@ -34,6 +37,11 @@ func (c *Command) IsHidden() *Command {
 	return c
 }
 
+func (c *Command) SupportsDryRun() *Command {
+	c.DryRun = true
+	return c
+}
+
 func NewCommand(service string) *Command {
 	return &Command{
 		Service:    service,
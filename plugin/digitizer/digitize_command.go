@@ -2,26 +2,41 @@ package digitzer
 
 import (
 	"bytes"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/network"
 	"github.com/UiPath/uipathcli/output"
 	"github.com/UiPath/uipathcli/plugin"
 	"github.com/UiPath/uipathcli/utils"
 )
 
+const (
+	digitizeDefaultConcurrency = 4
+	digitizeResultSuffix       = ".digitization.json"
+	digitizeFilePermissions    = 0644
+)
+
 // The DigitizeCommand is a convenient wrapper over the async digitizer API
 // to make it seem like it is a single sync call.
+//
+// With --file-glob, all matching files are digitized concurrently. The result
+// of every file is saved next to the source file or into --output-dir and an
+// NDJSON status line is written for each document.
 type DigitizeCommand struct{}
 
 func (c DigitizeCommand) Command() plugin.Command {
@@ -29,25 +44,254 @@ func (c DigitizeCommand) Command() plugin.Command {
 		WithCategory("digitization", "Document Digitization").
 		WithOperation("digitize", "Digitize the given file").
 		WithParameter("project-id", plugin.ParameterTypeString, "The project id", true).
-		WithParameter("file", plugin.ParameterTypeBinary, "The file to digitize", true).
-		WithParameter("content-type", plugin.ParameterTypeString, "The content type", false)
+		WithParameter("file", plugin.ParameterTypeBinary, "The file to digitize", false).
+		WithParameter("content-type", plugin.ParameterTypeString, "The content type", false).
+		WithParameter("file-glob", plugin.ParameterTypeString, "Digitize all files matching the glob pattern", false).
+		WithParameter("concurrency", plugin.ParameterTypeInteger, "Number of files to digitize in parallel (default 4)", false).
+		WithParameter("output-dir", plugin.ParameterTypeString, "The directory to save the digitization results to (default: next to the file)", false).
+		WithParameter("force", plugin.ParameterTypeBoolean, "Digitize files again which already have a digitization result", false)
 }
 
 func (c DigitizeCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
-	documentId, err := c.startDigitization(context, logger)
+	pattern, _ := c.getParameter("file-glob", context.Parameters)
+	_, err := c.getFileParameter(context.Parameters)
+	hasFile := err == nil || context.Input != nil
+	if pattern != "" && hasFile {
+		return errors.New("The --file and --file-glob parameters cannot be used together")
+	}
+	if pattern != "" {
+		return c.digitizeFiles(pattern, context, writer, logger)
+	}
+	if !hasFile {
+		return errors.New("Invalid arguments:\n  Argument --file is missing")
+	}
+	_, err = c.digitize(context, writer, logger)
+	return err
+}
+
+func (c DigitizeCommand) digitize(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) (string, error) {
+	var documentId string
+	phaseContext, span := context.StartPhase("start digitization")
+	err := utils.RetryWithAttempt(phaseContext.Context, phaseContext.RetryPolicy, func(attempt int) error {
+		var err error
+		documentId, err = c.startDigitization(phaseContext.WithRetryAttempt(attempt), logger)
+		return err
+	})
+	span.End(err)
+	if err != nil {
+		return "", err
+	}
+
+	phaseContext, span = context.StartPhase("wait for digitization")
+	err = c.waitForResult(documentId, phaseContext, writer, logger)
+	span.End(err)
+	return documentId, err
+}
+
+// digitizeFiles digitizes all files matching the glob pattern using a bounded
+// number of workers. Files which already have a digitization result are
+// skipped unless --force is provided.
+func (c DigitizeCommand) digitizeFiles(pattern string, context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	concurrency, err := c.getIntParameter("concurrency", context.Parameters)
+	if err != nil {
+		concurrency = digitizeDefaultConcurrency
+	}
+	if concurrency <= 0 {
+		return errors.New("Invalid value for --concurrency: needs to be greater than 0")
+	}
+	outputDir, _ := c.getParameter("output-dir", context.Parameters)
+	if outputDir != "" {
+		err = os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			return fmt.Errorf("Error creating output directory '%s': %w", outputDir, err)
+		}
+	}
+	force := c.getBoolParameter("force", context.Parameters)
+	files, err := c.findFiles(pattern)
+	if err != nil {
+		return err
+	}
+	baseDir := c.globBase(pattern)
+
+	pending := make(chan string, len(files))
+	for _, file := range files {
+		pending <- file
+	}
+	close(pending)
+	var mutex sync.Mutex
+	failed := 0
+	var writeErr error
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(files); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerLogger := log.NewQuietLogger(logger)
+			for file := range pending {
+				status := c.digitizeFile(file, c.resultPath(file, baseDir, outputDir), force, context, workerLogger)
+
+				mutex.Lock()
+				if status.Status == digitizeStatusFailed {
+					failed++
+				}
+				err := c.writeStatus(writer, status)
+				if err != nil && writeErr == nil {
+					writeErr = err
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if writeErr != nil {
+		return writeErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("Failed to digitize %d of %d files", failed, len(files))
+	}
+	return nil
+}
+
+func (c DigitizeCommand) digitizeFile(file string, resultPath string, force bool, context plugin.ExecutionContext, logger log.Logger) digitizeStatus {
+	status := digitizeStatus{File: file, Output: resultPath, Status: digitizeStatusDigitized}
+	if !force {
+		if _, err := os.Stat(resultPath); err == nil {
+			status.Status = digitizeStatusSkipped
+			return status
+		}
+	}
+	contentType, _ := c.getParameter("content-type", context.Parameters)
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(file))
+	}
+	projectId, _ := c.getParameter("project-id", context.Parameters)
+	context.Input = utils.NewFileStream(file)
+	context.Parameters = []plugin.ExecutionParameter{
+		*plugin.NewExecutionParameter("project-id", projectId),
+		*plugin.NewExecutionParameter("content-type", contentType),
+	}
+
+	result := output.NewMemoryOutputWriter()
+	documentId, err := c.digitize(context, result, logger)
+	status.DocumentId = documentId
+	if err == nil {
+		err = c.saveResult(documentId, resultPath, result.Response())
+	}
+	if err != nil {
+		status.Status = digitizeStatusFailed
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// saveResult writes the digitization result to a temporary file first and
+// renames it once completed so that interrupted runs never leave a partial
+// result behind which would be skipped on the next run.
+func (c DigitizeCommand) saveResult(documentId string, path string, response output.ResponseInfo) error {
+	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		return err
 	}
+	var result digitizeResultResponse
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return fmt.Errorf("Error parsing json response: %w", err)
+	}
+	if result.Status == "Failed" {
+		return fmt.Errorf("Digitization with documentId '%s' failed", documentId)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("Error creating directory '%s': %w", filepath.Dir(path), err)
+	}
+	partialPath := path + ".partial"
+	err = os.WriteFile(partialPath, body, digitizeFilePermissions)
+	if err != nil {
+		return fmt.Errorf("Error writing file '%s': %w", partialPath, err)
+	}
+	err = os.Rename(partialPath, path)
+	if err != nil {
+		return fmt.Errorf("Error writing file '%s': %w", path, err)
+	}
+	return nil
+}
+
+func (c DigitizeCommand) findFiles(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value for --file-glob: %w", err)
+	}
+	files := []string{}
+	for _, match := range matches {
+		if strings.HasSuffix(match, digitizeResultSuffix) || strings.HasSuffix(match, digitizeResultSuffix+".partial") {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, match)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("No files found matching '%s'", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
 
+// globBase returns the directory of the glob pattern which does not contain
+// any wildcards.
+func (c DigitizeCommand) globBase(pattern string) string {
+	base := filepath.Dir(pattern)
+	for strings.ContainsAny(base, "*?[") {
+		base = filepath.Dir(base)
+	}
+	return base
+}
+
+// resultPath returns the path of the digitization result. The directory
+// structure below the glob base directory is preserved in the output
+// directory so that files with the same name do not overwrite each other.
+func (c DigitizeCommand) resultPath(file string, baseDir string, outputDir string) string {
+	if outputDir == "" {
+		return file + digitizeResultSuffix
+	}
+	relativePath, err := filepath.Rel(baseDir, file)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		relativePath = filepath.Base(file)
+	}
+	return filepath.Join(outputDir, relativePath+digitizeResultSuffix)
+}
+
+func (c DigitizeCommand) writeStatus(writer output.OutputWriter, status digitizeStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("Error serializing digitization status: %w", err)
+	}
+	data = append(data, '\n')
+	header := map[string][]string{"Content-Type": {"application/x-ndjson"}}
+	return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", "HTTP/1.1", header, bytes.NewReader(data)))
+}
+
+func (c DigitizeCommand) waitForResult(documentId string, context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
 	for i := 1; i <= 60; i++ {
-		finished, err := c.waitForDigitization(documentId, context, writer, logger)
+		finished := false
+		err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+			var err error
+			finished, err = c.waitForDigitization(documentId, context.WithRetryAttempt(attempt), writer, logger)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 		if finished {
 			return nil
 		}
-		time.Sleep(1 * time.Second)
+		err = utils.Sleep(context.Context, 1*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 	return fmt.Errorf("Digitization with documentId '%s' did not finish in time", documentId)
 }
@@ -63,7 +307,7 @@ func (c DigitizeCommand) startDigitization(context plugin.ExecutionContext, logg
 	if context.Debug {
 		c.logRequest(logger, request)
 	}
-	response, err := c.send(request, context.Insecure, requestError)
+	response, err := c.send(request, context.Network, requestError)
 	if err != nil {
 		return "", fmt.Errorf("Error sending request: %w", err)
 	}
@@ -74,7 +318,7 @@ func (c DigitizeCommand) startDigitization(context plugin.ExecutionContext, logg
 	}
 	c.logResponse(logger, response, body)
 	if response.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("Digitizer returned status code '%v' and body '%v'", response.StatusCode, string(body))
+		return "", plugin.NewStatusError(context, "Digitizer", response, body)
 	}
 	var result digitizeResponse
 	err = json.Unmarshal(body, &result)
@@ -92,9 +336,13 @@ func (c DigitizeCommand) waitForDigitization(documentId string, context plugin.E
 	if context.Debug {
 		c.logRequest(logger, request)
 	}
-	response, err := c.sendRequest(request, context.Insecure)
+	client, err := c.httpClient(context.Network)
 	if err != nil {
-		return true, fmt.Errorf("Error sending request: %w", err)
+		return true, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return true, utils.Retryable(fmt.Errorf("Error sending request: %w", err))
 	}
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
@@ -103,7 +351,7 @@ func (c DigitizeCommand) waitForDigitization(documentId string, context plugin.E
 	}
 	c.logResponse(logger, response, body)
 	if response.StatusCode != http.StatusOK {
-		return true, fmt.Errorf("Digitizer returned status code '%v' and body '%v'", response.StatusCode, string(body))
+		return true, plugin.NewStatusError(context, "Digitizer", response, body)
 	}
 	var result digitizeResultResponse
 	err = json.Unmarshal(body, &result)
@@ -146,7 +394,7 @@ func (c DigitizeCommand) createDigitizeRequest(context plugin.ExecutionContext,
 	uploadReader := c.progressReader("uploading...", "completing  ", bodyReader, contentLength, uploadBar)
 
 	uri := c.formatUri(context.BaseUri, context.Organization, context.Tenant, projectId) + "/digitization/start?api-version=1"
-	request, err := http.NewRequest("POST", uri, uploadReader)
+	request, err := http.NewRequestWithContext(context.Context, "POST", uri, uploadReader)
 	if err != nil {
 		return nil, err
 	}
@@ -196,7 +444,7 @@ func (c DigitizeCommand) createDigitizeStatusRequest(documentId string, context
 	}
 
 	uri := c.formatUri(context.BaseUri, context.Organization, context.Tenant, projectId) + fmt.Sprintf("/digitization/result/%s?api-version=1", documentId)
-	request, err := http.NewRequest("GET", uri, &bytes.Buffer{})
+	request, err := http.NewRequestWithContext(context.Context, "GET", uri, &bytes.Buffer{})
 	if err != nil {
 		return nil, err
 	}
@@ -246,12 +494,16 @@ func (c DigitizeCommand) writeMultipartBody(bodyWriter *io.PipeWriter, stream ut
 	return formWriter.FormDataContentType(), contentLength
 }
 
-func (c DigitizeCommand) send(request *http.Request, insecure bool, errorChan chan error) (*http.Response, error) {
+func (c DigitizeCommand) send(request *http.Request, settings network.HttpClientSettings, errorChan chan error) (*http.Response, error) {
+	client, err := c.httpClient(settings)
+	if err != nil {
+		return nil, err
+	}
 	responseChan := make(chan *http.Response)
 	go func(request *http.Request) {
-		response, err := c.sendRequest(request, insecure)
+		response, err := client.Do(request)
 		if err != nil {
-			errorChan <- err
+			errorChan <- utils.Retryable(err)
 			return
 		}
 		responseChan <- response
@@ -265,12 +517,8 @@ func (c DigitizeCommand) send(request *http.Request, insecure bool, errorChan ch
 	}
 }
 
-func (c DigitizeCommand) sendRequest(request *http.Request, insecure bool) (*http.Response, error) {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint // This is user configurable and disabled by default
-	}
-	client := &http.Client{Transport: transport}
-	return client.Do(request)
+func (c DigitizeCommand) httpClient(settings network.HttpClientSettings) (*http.Client, error) {
+	return network.NewHttpClient(settings)
 }
 
 func (c DigitizeCommand) getParameter(name string, parameters []plugin.ExecutionParameter) (string, error) {
@@ -284,6 +532,28 @@ func (c DigitizeCommand) getParameter(name string, parameters []plugin.Execution
 	return "", fmt.Errorf("Could not find '%s' parameter", name)
 }
 
+func (c DigitizeCommand) getIntParameter(name string, parameters []plugin.ExecutionParameter) (int, error) {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.(int); ok {
+				return data, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("Could not find '%s' parameter", name)
+}
+
+func (c DigitizeCommand) getBoolParameter(name string, parameters []plugin.ExecutionParameter) bool {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.(bool); ok {
+				return data
+			}
+		}
+	}
+	return false
+}
+
 func (c DigitizeCommand) getFileParameter(parameters []plugin.ExecutionParameter) (utils.Stream, error) {
 	for _, p := range parameters {
 		if p.Name == "file" {
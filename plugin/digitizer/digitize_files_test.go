@@ -0,0 +1,192 @@
+package digitzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/test"
+)
+
+func TestDigitizeFileGlobSavesResultsNextToFiles(t *testing.T) {
+	directory := createDirectory(t, "invoice1.pdf", "invoice2.pdf", "notes.txt")
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*.pdf"))
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	for _, name := range []string{"invoice1.pdf", "invoice2.pdf"} {
+		data, err := os.ReadFile(filepath.Join(directory, name+".digitization.json"))
+		if err != nil || string(data) != `{"status":"Done"}` {
+			t.Errorf("Expected digitization result for %s, but got: %v %v", name, string(data), err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(directory, "notes.txt.digitization.json")); err == nil {
+		t.Errorf("Expected file not matching the glob to be ignored")
+	}
+	statuses := parseStatusLines(t, result.StdOut)
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 status lines, but got: %v", result.StdOut)
+	}
+	for _, status := range statuses {
+		if status.Status != digitizeStatusDigitized || status.DocumentId != "eb80e441-05de-4a13-9aaa-f65b1babba05" {
+			t.Errorf("Expected file to be digitized, but got: %v", status)
+		}
+	}
+}
+
+func TestDigitizeFileGlobSavesResultsToOutputDirectory(t *testing.T) {
+	directory := createDirectory(t, "invoice.pdf")
+	outputDir := filepath.Join(t.TempDir(), "results")
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*.pdf"), "--output-dir", outputDir, "--concurrency", "1")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "invoice.pdf.digitization.json"))
+	if err != nil || string(data) != `{"status":"Done"}` {
+		t.Errorf("Expected digitization result in output directory, but got: %v %v", string(data), err)
+	}
+}
+
+func TestDigitizeFileGlobKeepsDirectoryStructureInOutputDirectory(t *testing.T) {
+	directory := t.TempDir()
+	for _, name := range []string{"january", "february"} {
+		_ = os.MkdirAll(filepath.Join(directory, name), 0755)
+		writeFile(filepath.Join(directory, name, "invoice.pdf"), []byte("hello-world"))
+	}
+	outputDir := filepath.Join(t.TempDir(), "results")
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*", "*.pdf"), "--output-dir", outputDir)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	for _, name := range []string{"january", "february"} {
+		data, err := os.ReadFile(filepath.Join(outputDir, name, "invoice.pdf.digitization.json"))
+		if err != nil || string(data) != `{"status":"Done"}` {
+			t.Errorf("Expected digitization result for %s in output directory, but got: %v %v", name, string(data), err)
+		}
+	}
+}
+
+func TestDigitizeFileGlobSkipsProcessedFiles(t *testing.T) {
+	directory := createDirectory(t, "invoice1.pdf", "invoice2.pdf")
+	writeFile(filepath.Join(directory, "invoice1.pdf.digitization.json"), []byte(`{"status":"Previous"}`))
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*"))
+
+	statuses := parseStatusLines(t, result.StdOut)
+	if len(statuses) != 2 || statuses[filepath.Join(directory, "invoice1.pdf")].Status != digitizeStatusSkipped {
+		t.Errorf("Expected processed file to be skipped, but got: %v", result.StdOut)
+	}
+	data, _ := os.ReadFile(filepath.Join(directory, "invoice1.pdf.digitization.json"))
+	if string(data) != `{"status":"Previous"}` {
+		t.Errorf("Expected existing result to be kept, but got: %v", string(data))
+	}
+}
+
+func TestDigitizeFileGlobWithForceDigitizesProcessedFiles(t *testing.T) {
+	directory := createDirectory(t, "invoice.pdf")
+	writeFile(filepath.Join(directory, "invoice.pdf.digitization.json"), []byte(`{"status":"Previous"}`))
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*.pdf"), "--force")
+
+	statuses := parseStatusLines(t, result.StdOut)
+	if statuses[filepath.Join(directory, "invoice.pdf")].Status != digitizeStatusDigitized {
+		t.Errorf("Expected processed file to be digitized again, but got: %v", result.StdOut)
+	}
+	data, _ := os.ReadFile(filepath.Join(directory, "invoice.pdf.digitization.json"))
+	if string(data) != `{"status":"Done"}` {
+		t.Errorf("Expected result to be overwritten, but got: %v", string(data))
+	}
+}
+
+func TestDigitizeFileGlobFailedDigitizationReturnsError(t *testing.T) {
+	directory := createDirectory(t, "invoice1.pdf", "invoice2.pdf")
+
+	result := runDigitizeFiles(200, `{"status":"Failed"}`, "--file-glob", filepath.Join(directory, "*.pdf"))
+
+	if !strings.Contains(result.StdErr, "Failed to digitize 2 of 2 files") {
+		t.Errorf("Expected stderr to show failed files, but got: %v", result.StdErr)
+	}
+	for _, status := range parseStatusLines(t, result.StdOut) {
+		if status.Status != digitizeStatusFailed || !strings.Contains(status.Error, "failed") {
+			t.Errorf("Expected file to fail, but got: %v", status)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(directory, "invoice1.pdf.digitization.json")); err == nil {
+		t.Errorf("Expected no result to be saved for failed digitization")
+	}
+}
+
+func TestDigitizeFileGlobWithoutMatchesShowsError(t *testing.T) {
+	directory := t.TempDir()
+
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file-glob", filepath.Join(directory, "*.pdf"))
+
+	if !strings.Contains(result.StdErr, "No files found matching") {
+		t.Errorf("Expected stderr to show that no files were found, but got: %v", result.StdErr)
+	}
+}
+
+func TestDigitizeFileAndFileGlobShowsValidationError(t *testing.T) {
+	result := runDigitizeFiles(200, `{"status":"Done"}`, "--file", "invoice.pdf", "--file-glob", "*.pdf")
+
+	if !strings.Contains(result.StdErr, "The --file and --file-glob parameters cannot be used together") {
+		t.Errorf("Expected stderr to show validation error, but got: %v", result.StdErr)
+	}
+}
+
+func runDigitizeFiles(statusCode int, body string, args ...string) test.Result {
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+
+	definition := `
+servers:
+- url: https://cloud.uipath.com/{organization}/{tenant}/du_/api/framework
+paths:
+  /digitize:
+    get:
+      operationId: digitize
+`
+
+	context := test.NewContextBuilder().
+		WithDefinition("du", definition).
+		WithConfig(config).
+		WithCommandPlugin(DigitizeCommand{}).
+		WithResponse(202, `{"documentId":"eb80e441-05de-4a13-9aaa-f65b1babba05"}`).
+		WithUrlResponse("/my-org/my-tenant/du_/api/framework/projects/1234/digitization/result/eb80e441-05de-4a13-9aaa-f65b1babba05?api-version=1", statusCode, body).
+		Build()
+
+	args = append([]string{"du", "digitization", "digitize", "--project-id", "1234"}, args...)
+	return test.RunCli(args, context)
+}
+
+func parseStatusLines(t *testing.T, stdout string) map[string]digitizeStatus {
+	result := map[string]digitizeStatus{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var status digitizeStatus
+		err := json.Unmarshal([]byte(line), &status)
+		if err != nil {
+			t.Fatalf("Failed to parse status line '%s': %v", line, err)
+		}
+		result[status.File] = status
+	}
+	return result
+}
+
+func createDirectory(t *testing.T, names ...string) string {
+	directory := t.TempDir()
+	for _, name := range names {
+		writeFile(filepath.Join(directory, name), []byte("hello-world"))
+	}
+	return directory
+}
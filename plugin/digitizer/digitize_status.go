@@ -0,0 +1,17 @@
+package digitzer
+
+const (
+	digitizeStatusDigitized = "digitized"
+	digitizeStatusSkipped   = "skipped"
+	digitizeStatusFailed    = "failed"
+)
+
+// digitizeStatus is the NDJSON status line written for every document
+// of a batch digitization.
+type digitizeStatus struct {
+	File       string `json:"file"`
+	Output     string `json:"output"`
+	Status     string `json:"status"`
+	DocumentId string `json:"documentId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -1,12 +1,23 @@
 package plugin
 
 import (
+	"context"
 	"net/url"
 
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/tracing"
 	"github.com/UiPath/uipathcli/utils"
 )
 
 // The ExecutionContext provides all the data needed by the plugin to perform the operation.
+//
+// Plugins should pass the Context to all outgoing requests so that the operation
+// is aborted when the user presses Ctrl+C or the configured timeout expires.
+// The Network settings contain the proxy, certificate and rate limit
+// configuration which should be used when creating HTTP clients.
+// DryRun is only set for commands which support the --dry-run flag.
+// Fail is set when the user passed --fail and plugins which write the body of
+// non-2xx responses should return an error after writing it.
 type ExecutionContext struct {
 	Organization string
 	Tenant       string
@@ -16,16 +27,25 @@ type ExecutionContext struct {
 	Parameters   []ExecutionParameter
 	Insecure     bool
 	Debug        bool
+	DryRun       bool
+	Fail         bool
+	RetryPolicy  utils.RetryPolicy
+	Context      context.Context
+	Network      network.HttpClientSettings
 }
 
-func NewExecutionContext(
-	organization string,
-	tenant string,
-	baseUri url.URL,
-	auth AuthResult,
-	input utils.Stream,
-	parameters []ExecutionParameter,
-	insecure bool,
-	debug bool) *ExecutionContext {
-	return &ExecutionContext{organization, tenant, baseUri, auth, input, parameters, insecure, debug}
+// WithRetryAttempt returns a copy of the execution context whose Context
+// carries the attempt number, so that it is reported in the request timing.
+func (c ExecutionContext) WithRetryAttempt(attempt int) ExecutionContext {
+	c.Context = utils.WithRetryAttempt(c.Context, attempt)
+	return c
+}
+
+// StartPhase returns a copy of the execution context whose Context carries a
+// new span for the given phase of the plugin. The span needs to be ended once
+// the phase is completed.
+func (c ExecutionContext) StartPhase(name string) (ExecutionContext, *tracing.Span) {
+	ctx, span := tracing.Start(c.Context, name, tracing.SpanKindInternal)
+	c.Context = ctx
+	return c, span
 }
@@ -0,0 +1,22 @@
+package plugin
+
+// OperationError is returned by plugins when the operation they started on
+// the server, e.g. an orchestrator job, did not complete successfully.
+// Stopped is set when the operation was stopped instead of failing so that
+// the CLI can return a separate exit code.
+type OperationError struct {
+	message string
+	Stopped bool
+}
+
+func (e OperationError) Error() string {
+	return e.message
+}
+
+func NewOperationFailedError(message string) *OperationError {
+	return &OperationError{message, false}
+}
+
+func NewOperationStoppedError(message string) *OperationError {
+	return &OperationError{message, true}
+}
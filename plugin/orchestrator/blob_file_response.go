@@ -0,0 +1,11 @@
+package orchestrator
+
+type blobFileResponse struct {
+	FullPath    string `json:"FullPath"`
+	Size        int64  `json:"Size"`
+	IsDirectory bool   `json:"IsDirectory"`
+}
+
+type blobFilesResponse struct {
+	Value []blobFileResponse `json:"value"`
+}
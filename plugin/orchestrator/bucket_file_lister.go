@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const bucketFilesPageSize = 1000
+
+// bucketFileLister pages through the GetFiles operation of a bucket and
+// returns all files of a directory including the files of its subdirectories.
+//
+// The get function sends the request for a page and is provided by the
+// commands so that they can apply their own headers and retries.
+type bucketFileLister struct {
+	get func(uri string) ([]byte, error)
+}
+
+// List returns the files in the given directory. The full path of the returned
+// files does not start with a slash.
+func (l bucketFileLister) List(baseUri string, bucketId int, directory string) ([]blobFileResponse, error) {
+	directory = strings.Trim(directory, "/")
+	prefix := ""
+	if directory != "" {
+		prefix = directory + "/"
+	}
+	escapedDirectory := strings.ReplaceAll(url.QueryEscape("/"+directory), "+", "%20")
+	result := []blobFileResponse{}
+	for skip := 0; ; skip += bucketFilesPageSize {
+		uri := baseUri + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetFiles?directory=%s&recursive=true&$top=%d&$skip=%d", bucketId, escapedDirectory, bucketFilesPageSize, skip)
+		body, err := l.get(uri)
+		if err != nil {
+			return nil, err
+		}
+		var files blobFilesResponse
+		err = json.Unmarshal(body, &files)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing json response: %w", err)
+		}
+		for _, file := range files.Value {
+			file.FullPath = strings.TrimPrefix(file.FullPath, "/")
+			if !file.IsDirectory && strings.HasPrefix(file.FullPath, prefix) {
+				result = append(result, file)
+			}
+		}
+		if len(files.Value) < bucketFilesPageSize {
+			return result, nil
+		}
+	}
+}
+
+func newBucketFileLister(get func(uri string) ([]byte, error)) *bucketFileLister {
+	return &bucketFileLister{get}
+}
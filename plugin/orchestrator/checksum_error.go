@@ -0,0 +1,18 @@
+package orchestrator
+
+import "fmt"
+
+// checksumError is returned when the downloaded file does not match the MD5
+// checksum of the blob.
+type checksumError struct {
+	expected string
+	actual   string
+}
+
+func (e checksumError) Error() string {
+	return fmt.Sprintf("The downloaded file is corrupted: expected MD5 checksum '%s' but got '%s'", e.expected, e.actual)
+}
+
+func newChecksumError(expected string, actual string) *checksumError {
+	return &checksumError{expected, actual}
+}
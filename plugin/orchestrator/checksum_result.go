@@ -0,0 +1,8 @@
+package orchestrator
+
+// checksumResult is the result of a checksum calculation running in the
+// background.
+type checksumResult struct {
+	Value string
+	Err   error
+}
@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// chunkProgress sums up the progress of the chunks which are transferred in
+// parallel and renders it on a single progress bar.
+type chunkProgress struct {
+	progressBar   *utils.ProgressBar
+	text          string
+	completedText string
+	total         int64
+	initial       int64
+	current       int64
+	startTime     time.Time
+	mutex         sync.Mutex
+}
+
+// Add reports the number of bytes transferred for a chunk. Negative values
+// revert the progress of a failed chunk which is going to be retried.
+func (p *chunkProgress) Add(bytes int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.current += bytes
+	if p.total < 10*1024*1024 {
+		return
+	}
+	bytesPerSecond := int64(0)
+	seconds := time.Since(p.startTime).Seconds()
+	if seconds > 0 {
+		bytesPerSecond = int64(float64(p.current-p.initial) / seconds)
+	}
+	text := p.text
+	if p.current >= p.total {
+		text = p.completedText
+	}
+	p.progressBar.Update(text, p.current, p.total, bytesPerSecond)
+}
+
+func newChunkProgress(progressBar *utils.ProgressBar, text string, completedText string, total int64, initial int64) *chunkProgress {
+	return &chunkProgress{
+		progressBar:   progressBar,
+		text:          text,
+		completedText: completedText,
+		total:         total,
+		initial:       initial,
+		current:       initial,
+		startTime:     time.Now(),
+	}
+}
@@ -0,0 +1,133 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/network"
+	"github.com/UiPath/uipathcli/plugin"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// The helpers in this file are shared between the orchestrator commands to
+// build the orchestrator url, read the command parameters and send and log
+// the HTTP requests.
+
+func formatUri(baseUri url.URL, org string, tenant string) string {
+	path := baseUri.Path
+	if baseUri.Path == "" {
+		path = "/{organization}/{tenant}/orchestrator_"
+	}
+	path = strings.ReplaceAll(path, "{organization}", org)
+	path = strings.ReplaceAll(path, "{tenant}", tenant)
+	path = strings.TrimSuffix(path, "/")
+	return fmt.Sprintf("%s://%s%s", baseUri.Scheme, baseUri.Host, path)
+}
+
+func queryEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func httpClient(settings network.HttpClientSettings) (*http.Client, error) {
+	return network.NewHttpClient(settings)
+}
+
+// sendRequest sends the request and returns as soon as the response headers
+// are received or an error is reported on the error channel, e.g. when
+// reading the request body fails.
+func sendRequest(request *http.Request, settings network.HttpClientSettings, errorChan chan error) (*http.Response, error) {
+	client, err := httpClient(settings)
+	if err != nil {
+		return nil, err
+	}
+	responseChan := make(chan *http.Response)
+	go func(request *http.Request) {
+		response, err := client.Do(request)
+		if err != nil {
+			errorChan <- utils.Retryable(err)
+			return
+		}
+		responseChan <- response
+	}(request)
+
+	select {
+	case err := <-errorChan:
+		return nil, err
+	case response := <-responseChan:
+		return response, nil
+	}
+}
+
+func getStringParameter(name string, parameters []plugin.ExecutionParameter) (string, error) {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.(string); ok {
+				return data, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Could not find '%s' parameter", name)
+}
+
+func getIntParameter(name string, parameters []plugin.ExecutionParameter) (int, error) {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.(int); ok {
+				return data, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("Could not find '%s' parameter", name)
+}
+
+func getBoolParameter(name string, parameters []plugin.ExecutionParameter) bool {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.(bool); ok {
+				return data
+			}
+		}
+	}
+	return false
+}
+
+func getStringArrayParameter(name string, parameters []plugin.ExecutionParameter) []string {
+	for _, p := range parameters {
+		if p.Name == name {
+			if data, ok := p.Value.([]string); ok {
+				return data
+			}
+		}
+	}
+	return []string{}
+}
+
+func getFileParameter(name string, parameters []plugin.ExecutionParameter) (utils.Stream, error) {
+	for _, p := range parameters {
+		if p.Name == name {
+			if stream, ok := p.Value.(utils.Stream); ok {
+				return stream, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Could not find '%s' parameter", name)
+}
+
+func logRequest(logger log.Logger, request *http.Request) {
+	buffer := &bytes.Buffer{}
+	_, _ = buffer.ReadFrom(request.Body)
+	body := buffer.Bytes()
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	requestInfo := log.NewRequestInfo(request.Method, request.URL.String(), request.Proto, request.Header, bytes.NewReader(body))
+	logger.LogRequest(*requestInfo)
+}
+
+func logResponse(logger log.Logger, response *http.Response, body []byte) {
+	responseInfo := log.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, bytes.NewReader(body))
+	logger.LogResponse(*responseInfo)
+}
@@ -2,14 +2,17 @@ package orchestrator
 
 import (
 	"bytes"
-	"crypto/tls"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/UiPath/uipathcli/log"
 	"github.com/UiPath/uipathcli/output"
@@ -17,9 +20,26 @@ import (
 	"github.com/UiPath/uipathcli/utils"
 )
 
+const (
+	downloadChunkSize            int64 = 8 * 1024 * 1024
+	downloadConcurrency                = 4
+	downloadDefaultTransfers           = 4
+	downloadFilePermissions            = 0644
+	downloadDirectoryPermissions       = 0755
+)
+
+var errDownloadChanged = errors.New("The file changed on the server during the download. Run the command again to start over.")
+
 // The DownloadCommand is a custom command for the orchestrator service which makes downloading
 // files more convenient. It provides a wrapper over retrieving the read url and actually
 // performing the download.
+//
+// When a destination is provided, the file is streamed to a partial file which is continued
+// using range requests in case the download is interrupted. Large files can be downloaded
+// in parallel chunks. The downloaded file is verified using the MD5 checksum of the blob.
+//
+// With --recursive, the path is treated as a directory and all files inside of it are
+// downloaded concurrently into a mirrored local directory tree.
 type DownloadCommand struct{}
 
 func (c DownloadCommand) Command() plugin.Command {
@@ -28,27 +48,581 @@ func (c DownloadCommand) Command() plugin.Command {
 		WithOperation("download", "Downloads the file with the given path from the bucket").
 		WithParameter("folder-id", plugin.ParameterTypeInteger, "Folder/OrganizationUnit Id", true).
 		WithParameter("key", plugin.ParameterTypeInteger, "The Bucket Id", true).
-		WithParameter("path", plugin.ParameterTypeString, "The BlobFile full path", true)
+		WithParameter("path", plugin.ParameterTypeString, "The BlobFile full path", true).
+		WithParameter("destination", plugin.ParameterTypeString, "The local file path to download the file to", false).
+		WithParameter("parallel", plugin.ParameterTypeBoolean, "Download the file in parallel chunks (requires --destination)", false).
+		WithParameter("recursive", plugin.ParameterTypeBoolean, "Download all files in the directory with the given path", false).
+		WithParameter("transfers", plugin.ParameterTypeInteger, "Number of files to download in parallel with --recursive (default 4)", false)
 }
 
 func (c DownloadCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
-	writeUrl, err := c.getReadUrl(context, logger)
+	if getBoolParameter("recursive", context.Parameters) {
+		return c.downloadDirectory(context, writer, logger)
+	}
+	_, err := getStringParameter("destination", context.Parameters)
+	if err != nil && getBoolParameter("parallel", context.Parameters) {
+		return errors.New("The --parallel parameter requires --destination")
+	}
+	var readUrl string
+	phaseContext, span := context.StartPhase("get read url")
+	err = utils.RetryWithAttempt(phaseContext.Context, phaseContext.RetryPolicy, func(attempt int) error {
+		var err error
+		readUrl, err = c.getReadUrl(phaseContext.WithRetryAttempt(attempt), logger)
+		return err
+	})
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	phaseContext, span = context.StartPhase("download")
+	destination, err := getStringParameter("destination", context.Parameters)
+	if err == nil {
+		err = c.downloadFile(phaseContext, logger, readUrl, destination)
+	} else {
+		err = utils.RetryWithAttempt(phaseContext.Context, phaseContext.RetryPolicy, func(attempt int) error {
+			return c.download(phaseContext.WithRetryAttempt(attempt), writer, logger, readUrl)
+		})
+	}
+	span.End(err)
+	return err
+}
+
+// downloadDirectory lists all files in the bucket directory and downloads them
+// concurrently. The local directory tree mirrors the bucket directory. A summary
+// row is written for every file once all downloads completed.
+func (c DownloadCommand) downloadDirectory(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	prefix, err := getStringParameter("path", context.Parameters)
+	if err != nil {
+		return err
+	}
+	prefix = strings.Trim(prefix, "/")
+	destination, err := getStringParameter("destination", context.Parameters)
+	if err != nil {
+		destination = "."
+	}
+	transfers, err := getIntParameter("transfers", context.Parameters)
+	if err != nil {
+		transfers = downloadDefaultTransfers
+	}
+	if transfers <= 0 {
+		return errors.New("Invalid value for --transfers: needs to be greater than 0")
+	}
+
+	phaseContext, span := context.StartPhase("list files")
+	files, err := c.listFiles(phaseContext, logger, prefix)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+
+	phaseContext, span = context.StartPhase("download files")
+	summary := make([]downloadSummary, len(files))
+	pending := make(chan int, len(files))
+	for index := range files {
+		pending <- index
+	}
+	close(pending)
+	var mutex sync.Mutex
+	completed := 0
+	failed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < transfers && i < len(files); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range pending {
+				file := files[index]
+				relativePath := file.FullPath
+				if prefix != "" {
+					relativePath = strings.TrimPrefix(relativePath, prefix+"/")
+				}
+				localFile, err := localPath(destination, relativePath)
+				if err == nil {
+					err = c.downloadDirectoryFile(phaseContext, logger, file.FullPath, localFile)
+				}
+
+				mutex.Lock()
+				completed++
+				summary[index] = downloadSummary{Path: file.FullPath, File: localFile, Size: file.Size, Status: downloadStatusDownloaded}
+				if err != nil {
+					failed++
+					summary[index].Status = downloadStatusFailed
+					summary[index].Error = err.Error()
+					logger.LogError(fmt.Sprintf("[%d/%d] Failed to download %s: %v\n", completed, len(files), file.FullPath, err))
+				} else {
+					logger.LogError(fmt.Sprintf("[%d/%d] Downloaded %s (%d bytes)\n", completed, len(files), file.FullPath, file.Size))
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		err = fmt.Errorf("Failed to download %d of %d files", failed, len(files))
+	}
+	span.End(err)
+	data, jsonErr := json.Marshal(summary)
+	if jsonErr != nil {
+		return fmt.Errorf("Error serializing download summary: %w", jsonErr)
+	}
+	writeErr := writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader(data)))
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+func (c DownloadCommand) downloadDirectoryFile(context plugin.ExecutionContext, logger log.Logger, path string, localPath string) error {
+	err := os.MkdirAll(filepath.Dir(localPath), downloadDirectoryPermissions)
+	if err != nil {
+		return fmt.Errorf("Error creating directory '%s': %w", filepath.Dir(localPath), err)
+	}
+	parameters := []plugin.ExecutionParameter{
+		*plugin.NewExecutionParameter("path", path),
+		*plugin.NewExecutionParameter("destination", localPath),
+	}
+	for _, parameter := range context.Parameters {
+		if parameter.Name == "folder-id" || parameter.Name == "key" || parameter.Name == "parallel" {
+			parameters = append(parameters, parameter)
+		}
+	}
+	context.Parameters = parameters
+	return c.Execute(context, nil, log.NewQuietLogger(logger))
+}
+
+// listFiles returns all files in the bucket directory including the files of
+// all subdirectories.
+func (c DownloadCommand) listFiles(context plugin.ExecutionContext, logger log.Logger, prefix string) ([]blobFileResponse, error) {
+	if context.Organization == "" {
+		return nil, errors.New("Organization is not set")
+	}
+	if context.Tenant == "" {
+		return nil, errors.New("Tenant is not set")
+	}
+	folderId, err := getIntParameter("folder-id", context.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	bucketId, err := getIntParameter("key", context.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	lister := newBucketFileLister(func(uri string) ([]byte, error) {
+		var body []byte
+		err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+			var err error
+			body, err = c.getFiles(context.WithRetryAttempt(attempt), logger, uri, folderId)
+			return err
+		})
+		return body, err
+	})
+	baseUri := formatUri(context.BaseUri, context.Organization, context.Tenant)
+	return lister.List(baseUri, bucketId, prefix)
+}
+
+func (c DownloadCommand) getFiles(context plugin.ExecutionContext, logger log.Logger, uri string, folderId int) ([]byte, error) {
+	request, err := http.NewRequestWithContext(context.Context, "GET", uri, &bytes.Buffer{})
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("X-UiPath-OrganizationUnitId", fmt.Sprintf("%d", folderId))
+	for key, value := range context.Auth.Header {
+		request.Header.Add(key, value)
+	}
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	response, err := sendRequest(request, context.Network, make(chan error))
+	if err != nil {
+		return nil, fmt.Errorf("Error sending request: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, utils.Retryable(fmt.Errorf("Error reading response: %w", err))
+	}
+	logResponse(logger, response, body)
+	if response.StatusCode != http.StatusOK {
+		return nil, plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
+	return body, nil
+}
+
+// downloadFile writes the blob to a partial file next to the destination which
+// is renamed once the download completed and the checksum has been verified.
+// The partial file of a previous download is continued.
+func (c DownloadCommand) downloadFile(context plugin.ExecutionContext, logger log.Logger, readUrl string, destination string) error {
+	path, err := filepath.Abs(destination)
+	if err != nil {
+		return fmt.Errorf("Invalid destination '%s': %w", destination, err)
+	}
+	store, err := newJournalStore("downloads", path)
 	if err != nil {
 		return err
 	}
-	return c.download(context, writer, logger, writeUrl)
+	chunkSize := int64(0)
+	if getBoolParameter("parallel", context.Parameters) {
+		chunkSize = downloadChunkSize
+	}
+	partialPath := path + ".partial"
+	journal, err := c.loadJournal(*store, partialPath, chunkSize)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(partialPath, os.O_CREATE|os.O_RDWR, downloadFilePermissions)
+	if err != nil {
+		return fmt.Errorf("Error creating file '%s': %w", partialPath, err)
+	}
+
+	downloadBar := utils.NewProgressBar(logger)
+	if chunkSize > 0 {
+		err = c.downloadChunks(context, logger, readUrl, file, journal, *store, downloadBar)
+	} else {
+		err = utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+			return c.downloadStream(context.WithRetryAttempt(attempt), logger, readUrl, file, journal, *store, downloadBar)
+		})
+	}
+	downloadBar.Remove()
+	if err == nil {
+		err = c.verifyChecksum(file, journal.MD5)
+	}
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("Error writing file '%s': %w", partialPath, closeErr)
+	}
+	if err != nil {
+		var checksumErr *checksumError
+		fileInfo, statErr := os.Stat(partialPath)
+		if errors.Is(err, errDownloadChanged) || errors.As(err, &checksumErr) || journal.ETag == "" || statErr != nil || fileInfo.Size() == 0 {
+			_ = os.Remove(partialPath)
+			store.Remove()
+		} else {
+			logger.LogError("Download interrupted. Run the command again to continue.\n")
+		}
+		return err
+	}
+	err = os.Rename(partialPath, path)
+	if err != nil {
+		return fmt.Errorf("Error writing file '%s': %w", path, err)
+	}
+	store.Remove()
+	return nil
+}
+
+// loadJournal returns the journal of a previous download to the same destination
+// or a new journal in case there is nothing to continue.
+func (c DownloadCommand) loadJournal(store journalStore, partialPath string, chunkSize int64) (*downloadJournal, error) {
+	var journal downloadJournal
+	found, err := store.Read(&journal)
+	if err != nil {
+		return nil, err
+	}
+	_, statErr := os.Stat(partialPath)
+	if found && statErr == nil && journal.ETag != "" && journal.ChunkSize == chunkSize {
+		return &journal, nil
+	}
+	_ = os.Remove(partialPath)
+	return newDownloadJournal(chunkSize), nil
+}
+
+// downloadStream downloads the remaining part of the blob sequentially. A range
+// request is used to continue the partial file. The If-Range header makes sure
+// that the whole blob is downloaded again in case it changed in between.
+func (c DownloadCommand) downloadStream(context plugin.ExecutionContext, logger log.Logger, readUrl string, file *os.File, journal *downloadJournal, store journalStore, progressBar *utils.ProgressBar) error {
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Name(), err)
+	}
+	resume := offset > 0 && journal.ETag != ""
+	if resume && offset == journal.Size {
+		return nil
+	}
+	request, err := http.NewRequestWithContext(context.Context, "GET", readUrl, &bytes.Buffer{})
+	if err != nil {
+		return err
+	}
+	if resume {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		request.Header.Set("If-Range", journal.ETag)
+	}
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	response, err := sendRequest(request, context.Network, make(chan error))
+	if err != nil {
+		return fmt.Errorf("Error sending request: %w", err)
+	}
+	defer response.Body.Close()
+	switch {
+	case response.StatusCode == http.StatusPartialContent && resume:
+		start, _, err := c.parseContentRange(response.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			return fmt.Errorf("Invalid Content-Range '%s' for the requested offset %d", response.Header.Get("Content-Range"), offset)
+		}
+	case response.StatusCode == http.StatusOK:
+		offset = 0
+		err = c.truncate(file)
+		if err != nil {
+			return err
+		}
+		journal.ETag = response.Header.Get("ETag")
+		journal.Size = response.ContentLength
+		journal.MD5 = c.contentMd5(response)
+		err = store.Write(*journal)
+		if err != nil {
+			return err
+		}
+	default:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
+		}
+		logResponse(logger, response, body)
+		return plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
+	logResponse(logger, response, []byte{})
+	progress := newChunkProgress(progressBar, "downloading...", "completing    ", journal.Size, offset)
+	_, err = io.Copy(file, c.chunkReader(response.Body, progress))
+	if err != nil {
+		return utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
+	}
+	return nil
+}
+
+// downloadChunks downloads the blob in parallel ranged chunks. The first chunk
+// determines the size of the blob in case it is not known yet. The If-Match
+// header makes sure that all chunks belong to the same version of the blob.
+func (c DownloadCommand) downloadChunks(context plugin.ExecutionContext, logger log.Logger, readUrl string, file *os.File, journal *downloadJournal, store journalStore, progressBar *utils.ProgressBar) error {
+	var mutex sync.Mutex
+	complete := func(index int) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		journal.Chunks = append(journal.Chunks, index)
+		return store.Write(*journal)
+	}
+
+	completed := journal.Completed()
+	if journal.Size < 0 {
+		full := false
+		err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+			var err error
+			full, err = c.downloadChunk(context.WithRetryAttempt(attempt), logger, readUrl, file, journal, 0, nil)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if full {
+			return nil
+		}
+		err = complete(0)
+		if err != nil {
+			return err
+		}
+		completed[0] = true
+	}
+
+	chunkCount := int((journal.Size + journal.ChunkSize - 1) / journal.ChunkSize)
+	downloaded := int64(0)
+	pending := make(chan int, chunkCount)
+	for index := 0; index < chunkCount; index++ {
+		if completed[index] {
+			downloaded += c.chunkLength(index, journal.ChunkSize, journal.Size)
+		} else {
+			pending <- index
+		}
+	}
+	close(pending)
+	progress := newChunkProgress(progressBar, "downloading...", "completing    ", journal.Size, downloaded)
+
+	var downloadErr error
+	var wg sync.WaitGroup
+	for i := 0; i < downloadConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range pending {
+				mutex.Lock()
+				failed := downloadErr != nil
+				mutex.Unlock()
+				if failed {
+					return
+				}
+				err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+					_, err := c.downloadChunk(context.WithRetryAttempt(attempt), logger, readUrl, file, journal, index, progress)
+					return err
+				})
+				if err == nil {
+					err = complete(index)
+				}
+				mutex.Lock()
+				if err != nil && downloadErr == nil {
+					downloadErr = err
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return downloadErr
+}
+
+// downloadChunk downloads a single chunk and writes it at its offset into the
+// file. It returns true in case the server ignored the range and sent the
+// whole blob instead.
+func (c DownloadCommand) downloadChunk(context plugin.ExecutionContext, logger log.Logger, readUrl string, file *os.File, journal *downloadJournal, index int, progress *chunkProgress) (bool, error) {
+	start := int64(index) * journal.ChunkSize
+	end := start + journal.ChunkSize - 1
+	request, err := http.NewRequestWithContext(context.Context, "GET", readUrl, &bytes.Buffer{})
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if journal.ETag != "" {
+		request.Header.Set("If-Match", journal.ETag)
+	}
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	response, err := sendRequest(request, context.Network, make(chan error))
+	if err != nil {
+		return false, fmt.Errorf("Error sending request: %w", err)
+	}
+	defer response.Body.Close()
+
+	full := false
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		rangeStart, total, err := c.parseContentRange(response.Header.Get("Content-Range"))
+		if err != nil || rangeStart != start {
+			return false, fmt.Errorf("Invalid Content-Range '%s' for the requested offset %d", response.Header.Get("Content-Range"), start)
+		}
+		if journal.Size < 0 {
+			journal.ETag = response.Header.Get("ETag")
+			journal.Size = total
+			journal.MD5 = c.contentMd5(response)
+		}
+	case http.StatusOK:
+		if index != 0 {
+			return false, errors.New("The server does not support range requests, download the file without --parallel")
+		}
+		journal.ETag = response.Header.Get("ETag")
+		journal.Size = response.ContentLength
+		journal.MD5 = c.contentMd5(response)
+		full = true
+	case http.StatusRequestedRangeNotSatisfiable:
+		if journal.Size < 0 {
+			logResponse(logger, response, []byte{})
+			journal.Size = 0
+			return true, nil
+		}
+		return false, fmt.Errorf("Invalid range requested for chunk %d", index)
+	case http.StatusPreconditionFailed:
+		logResponse(logger, response, []byte{})
+		return false, errDownloadChanged
+	default:
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return false, utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
+		}
+		logResponse(logger, response, body)
+		return false, plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
+	logResponse(logger, response, []byte{})
+
+	var reader io.Reader = response.Body
+	if progress != nil {
+		reader = c.chunkReader(reader, progress)
+	}
+	written, err := io.Copy(io.NewOffsetWriter(file, start), reader)
+	if err != nil {
+		if progress != nil {
+			progress.Add(-written)
+		}
+		return false, utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
+	}
+	return full, nil
+}
+
+func (c DownloadCommand) chunkReader(reader io.Reader, progress *chunkProgress) io.Reader {
+	var bytesRead int64
+	return utils.NewProgressReader(reader, func(p utils.Progress) {
+		progress.Add(p.BytesRead - bytesRead)
+		bytesRead = p.BytesRead
+	})
+}
+
+func (c DownloadCommand) chunkLength(index int, chunkSize int64, size int64) int64 {
+	offset := int64(index) * chunkSize
+	if size-offset < chunkSize {
+		return size - offset
+	}
+	return chunkSize
+}
+
+// parseContentRange returns the start and the total size from a Content-Range
+// header, e.g. bytes 0-1023/4096
+func (c DownloadCommand) parseContentRange(value string) (int64, int64, error) {
+	var start, end, total int64
+	_, err := fmt.Sscanf(value, "bytes %d-%d/%d", &start, &end, &total)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, total, nil
+}
+
+// contentMd5 returns the MD5 checksum of the whole blob. Ranged responses
+// only contain it in the blob storage specific header.
+func (c DownloadCommand) contentMd5(response *http.Response) string {
+	md5 := response.Header.Get("x-ms-blob-content-md5")
+	if md5 == "" && response.StatusCode == http.StatusOK {
+		md5 = response.Header.Get("Content-MD5")
+	}
+	return md5
+}
+
+func (c DownloadCommand) verifyChecksum(file *os.File, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Name(), err)
+	}
+	hash := md5.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Name(), err)
+	}
+	actual := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return newChecksumError(expected, actual)
+	}
+	return nil
+}
+
+func (c DownloadCommand) truncate(file *os.File) error {
+	err := file.Truncate(0)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return fmt.Errorf("Error writing file '%s': %w", file.Name(), err)
+	}
+	return nil
 }
 
 func (c DownloadCommand) download(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger, url string) error {
 	requestError := make(chan error)
-	request, err := http.NewRequest("GET", url, &bytes.Buffer{})
+	request, err := http.NewRequestWithContext(context.Context, "GET", url, &bytes.Buffer{})
 	if err != nil {
 		return err
 	}
 	if context.Debug {
-		c.logRequest(logger, request)
+		logRequest(logger, request)
 	}
-	response, err := c.send(request, context.Insecure, requestError)
+	response, err := sendRequest(request, context.Network, requestError)
 	if err != nil {
 		return fmt.Errorf("Error sending request: %w", err)
 	}
@@ -56,15 +630,25 @@ func (c DownloadCommand) download(context plugin.ExecutionContext, writer output
 	downloadBar := utils.NewProgressBar(logger)
 	downloadReader := c.progressReader("downloading...", "completing    ", response.Body, response.ContentLength, downloadBar)
 	defer downloadBar.Remove()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		logResponse(logger, response, []byte{})
+		return writer.WriteResponse(*output.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, downloadReader))
+	}
 	body, err := io.ReadAll(downloadReader)
 	if err != nil {
-		return fmt.Errorf("Error reading response body: %w", err)
+		return utils.Retryable(fmt.Errorf("Error reading response body: %w", err))
+	}
+	logResponse(logger, response, body)
+	if context.RetryPolicy.IsRetryableStatusCode(response.StatusCode) {
+		return plugin.NewStatusError(context, "Orchestrator", response, body)
 	}
-	c.logResponse(logger, response, body)
 	err = writer.WriteResponse(*output.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, bytes.NewReader(body)))
 	if err != nil {
 		return err
 	}
+	if context.Fail {
+		return plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
 	return nil
 }
 
@@ -88,10 +672,10 @@ func (c DownloadCommand) getReadUrl(context plugin.ExecutionContext, logger log.
 		return "", err
 	}
 	if context.Debug {
-		c.logRequest(logger, request)
+		logRequest(logger, request)
 	}
 	requestError := make(chan error)
-	response, err := c.send(request, context.Insecure, requestError)
+	response, err := sendRequest(request, context.Network, requestError)
 	if err != nil {
 		return "", fmt.Errorf("Error sending request: %w", err)
 	}
@@ -100,9 +684,9 @@ func (c DownloadCommand) getReadUrl(context plugin.ExecutionContext, logger log.
 	if err != nil {
 		return "", fmt.Errorf("Error reading response: %w", err)
 	}
-	c.logResponse(logger, response, body)
+	logResponse(logger, response, body)
 	if response.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Orchestrator returned status code '%v' and body '%v'", response.StatusCode, string(body))
+		return "", plugin.NewStatusError(context, "Orchestrator", response, body)
 	}
 	var result urlResponse
 	err = json.Unmarshal(body, &result)
@@ -119,21 +703,21 @@ func (c DownloadCommand) createReadUrlRequest(context plugin.ExecutionContext) (
 	if context.Tenant == "" {
 		return nil, errors.New("Tenant is not set")
 	}
-	folderId, err := c.getIntParameter("folder-id", context.Parameters)
+	folderId, err := getIntParameter("folder-id", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
-	bucketId, err := c.getIntParameter("key", context.Parameters)
+	bucketId, err := getIntParameter("key", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
-	path, err := c.getStringParameter("path", context.Parameters)
+	path, err := getStringParameter("path", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
 
-	uri := c.formatUri(context.BaseUri, context.Organization, context.Tenant) + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetReadUri?path=%s", bucketId, path)
-	request, err := http.NewRequest("GET", uri, &bytes.Buffer{})
+	uri := formatUri(context.BaseUri, context.Organization, context.Tenant) + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetReadUri?path=%s", bucketId, path)
+	request, err := http.NewRequestWithContext(context.Context, "GET", uri, &bytes.Buffer{})
 	if err != nil {
 		return nil, err
 	}
@@ -143,77 +727,3 @@ func (c DownloadCommand) createReadUrlRequest(context plugin.ExecutionContext) (
 	}
 	return request, nil
 }
-
-func (c DownloadCommand) formatUri(baseUri url.URL, org string, tenant string) string {
-	path := baseUri.Path
-	if baseUri.Path == "" {
-		path = "/{organization}/{tenant}/orchestrator_"
-	}
-	path = strings.ReplaceAll(path, "{organization}", org)
-	path = strings.ReplaceAll(path, "{tenant}", tenant)
-	path = strings.TrimSuffix(path, "/")
-	return fmt.Sprintf("%s://%s%s", baseUri.Scheme, baseUri.Host, path)
-}
-
-func (c DownloadCommand) send(request *http.Request, insecure bool, errorChan chan error) (*http.Response, error) {
-	responseChan := make(chan *http.Response)
-	go func(request *http.Request) {
-		response, err := c.sendRequest(request, insecure)
-		if err != nil {
-			errorChan <- err
-			return
-		}
-		responseChan <- response
-	}(request)
-
-	select {
-	case err := <-errorChan:
-		return nil, err
-	case response := <-responseChan:
-		return response, nil
-	}
-}
-
-func (c DownloadCommand) sendRequest(request *http.Request, insecure bool) (*http.Response, error) {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint // This is user configurable and disabled by default
-	}
-	client := &http.Client{Transport: transport}
-	return client.Do(request)
-}
-
-func (c DownloadCommand) getStringParameter(name string, parameters []plugin.ExecutionParameter) (string, error) {
-	for _, p := range parameters {
-		if p.Name == name {
-			if data, ok := p.Value.(string); ok {
-				return data, nil
-			}
-		}
-	}
-	return "", fmt.Errorf("Could not find '%s' parameter", name)
-}
-
-func (c DownloadCommand) getIntParameter(name string, parameters []plugin.ExecutionParameter) (int, error) {
-	for _, p := range parameters {
-		if p.Name == name {
-			if data, ok := p.Value.(int); ok {
-				return data, nil
-			}
-		}
-	}
-	return 0, fmt.Errorf("Could not find '%s' parameter", name)
-}
-
-func (c DownloadCommand) logRequest(logger log.Logger, request *http.Request) {
-	buffer := &bytes.Buffer{}
-	_, _ = buffer.ReadFrom(request.Body)
-	body := buffer.Bytes()
-	request.Body = io.NopCloser(bytes.NewReader(body))
-	requestInfo := log.NewRequestInfo(request.Method, request.URL.String(), request.Proto, request.Header, bytes.NewReader(body))
-	logger.LogRequest(*requestInfo)
-}
-
-func (c DownloadCommand) logResponse(logger log.Logger, response *http.Response, body []byte) {
-	responseInfo := log.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, bytes.NewReader(body))
-	logger.LogResponse(*responseInfo)
-}
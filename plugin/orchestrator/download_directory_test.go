@@ -0,0 +1,186 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/test"
+)
+
+func TestDownloadRecursiveMirrorsDirectoryTree(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/a.txt":          "a",
+		"artifacts/nested/b.txt":   "bb",
+		"artifacts/nested/x/c.txt": "ccc",
+		"artifacts-old/d.txt":      "d",
+		"other/e.txt":              "e",
+	})
+	defer srv.Close()
+	destination := t.TempDir()
+
+	result := runDirectoryDownload(srv, "--path", "artifacts/", "--destination", destination)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	expected := map[string]string{
+		"a.txt":          "a",
+		"nested/b.txt":   "bb",
+		"nested/x/c.txt": "ccc",
+	}
+	assertFiles(t, readLocalTree(t, destination), expected)
+	if !strings.Contains(result.StdErr, "Downloaded artifacts/nested/b.txt (2 bytes)") || !strings.Contains(result.StdErr, "/3]") {
+		t.Errorf("Expected stderr to show per-file progress, but got: %v", result.StdErr)
+	}
+}
+
+func TestDownloadRecursiveWritesSummary(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/a.txt":        "a",
+		"artifacts/nested/b.txt": "bb",
+	})
+	defer srv.Close()
+	destination := t.TempDir()
+
+	result := runDirectoryDownload(srv, "--path", "artifacts", "--destination", destination)
+
+	summary := parseDownloadSummary(t, result.StdOut)
+	if len(summary) != 2 {
+		t.Fatalf("Expected summary for 2 files, but got: %v", result.StdOut)
+	}
+	for _, row := range summary {
+		if row.Status != downloadStatusDownloaded {
+			t.Errorf("Expected file %s to be downloaded, but got: %v", row.Path, row.Status)
+		}
+	}
+	if summary[0].Path != "artifacts/a.txt" && summary[1].Path != "artifacts/a.txt" {
+		t.Errorf("Expected summary to contain artifacts/a.txt, but got: %v", result.StdOut)
+	}
+	if summary[0].File != filepath.Join(destination, "a.txt") && summary[1].File != filepath.Join(destination, "a.txt") {
+		t.Errorf("Expected summary to contain local file path, but got: %v", result.StdOut)
+	}
+}
+
+func TestDownloadRecursiveWithParallelChunks(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"data/large.bin": string(createLargeFileData(int(downloadChunkSize) + 100)),
+		"data/small.txt": "small",
+	})
+	defer srv.Close()
+	destination := t.TempDir()
+
+	result := runDirectoryDownload(srv, "--path", "data", "--destination", destination, "--parallel")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, readLocalTree(t, destination), map[string]string{
+		"large.bin": string(createLargeFileData(int(downloadChunkSize) + 100)),
+		"small.txt": "small",
+	})
+}
+
+func TestDownloadRecursiveFailedFileReturnsError(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/ok.txt":     "ok",
+		"artifacts/broken.txt": "broken",
+	})
+	srv.failPath = "artifacts/broken.txt"
+	defer srv.Close()
+	destination := t.TempDir()
+
+	result := runDirectoryDownload(srv, "--path", "artifacts", "--destination", destination)
+
+	if !strings.Contains(result.StdErr, "Failed to download artifacts/broken.txt") || !strings.Contains(result.StdErr, "Failed to download 1 of 2 files") {
+		t.Errorf("Expected stderr to show failed download, but got: %v", result.StdErr)
+	}
+	assertFiles(t, readLocalTree(t, destination), map[string]string{"ok.txt": "ok"})
+	summary := parseDownloadSummary(t, result.StdOut)
+	for _, row := range summary {
+		if row.Path == "artifacts/broken.txt" && (row.Status != downloadStatusFailed || row.Error == "") {
+			t.Errorf("Expected summary to show failed file, but got: %v", result.StdOut)
+		}
+	}
+}
+
+func TestDownloadRecursiveEmptyDirectory(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"other/file.txt": "other"})
+	defer srv.Close()
+	destination := t.TempDir()
+
+	result := runDirectoryDownload(srv, "--path", "artifacts", "--destination", destination)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if result.StdOut != "[]\n" {
+		t.Errorf("Expected empty summary, but got: %v", result.StdOut)
+	}
+	assertFiles(t, readLocalTree(t, destination), map[string]string{})
+}
+
+func TestDownloadRecursiveRejectsPathsOutsideOfDestination(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/ok.txt":           "ok",
+		"artifacts/../../escape.txt": "escape",
+		"artifacts/nested/../in.txt": "in",
+	})
+	defer srv.Close()
+	parent := t.TempDir()
+	destination := filepath.Join(parent, "a", "b")
+
+	result := runDirectoryDownload(srv, "--path", "artifacts", "--destination", destination)
+
+	if !strings.Contains(result.StdErr, "Failed to download artifacts/../../escape.txt") || !strings.Contains(result.StdErr, "outside of the local directory") {
+		t.Errorf("Expected stderr to show rejected path, but got: %v", result.StdErr)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escape.txt")); err == nil {
+		t.Errorf("Expected no file to be written outside of the destination")
+	}
+	assertFiles(t, readLocalTree(t, destination), map[string]string{"ok.txt": "ok", "in.txt": "in"})
+}
+
+func TestDownloadRecursiveInvalidTransfersShowsValidationError(t *testing.T) {
+	srv := newBucketServer(map[string]string{})
+	defer srv.Close()
+
+	result := runDirectoryDownload(srv, "--path", "artifacts", "--transfers", "0")
+
+	if !strings.Contains(result.StdErr, "Invalid value for --transfers: needs to be greater than 0") {
+		t.Errorf("Expected stderr to show invalid transfers, but got: %v", result.StdErr)
+	}
+}
+
+func runDirectoryDownload(srv *bucketServer, args ...string) test.Result {
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(DownloadCommand{}).
+		Build()
+	args = append([]string{"orchestrator", "buckets", "download", "--folder-id", "1", "--key", "2", "--recursive"}, args...)
+	args = append(args, "--uri", srv.URL+"/my-org/my-tenant/orchestrator_")
+	return test.RunCli(args, context)
+}
+
+func parseDownloadSummary(t *testing.T, stdout string) []downloadSummary {
+	var result []downloadSummary
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatalf("Failed to parse download summary: %v", err)
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package orchestrator
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UiPath/uipathcli/test"
+)
+
+func TestDownloadToDestination(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(1024 * 1024)
+	srv := newBlobDownloadServer(data)
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+
+	result := runFileDownload(srv, "--destination", destination)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if result.StdOut != "" {
+		t.Errorf("Expected stdout to be empty, but got: %v", result.StdOut)
+	}
+	content, _ := os.ReadFile(destination)
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected destination to contain downloaded content, but got %d bytes", len(content))
+	}
+	if _, err := os.Stat(destination + ".partial"); err == nil {
+		t.Errorf("Expected partial file to be removed")
+	}
+}
+
+func TestDownloadInterruptedShowsHint(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBlobDownloadServer(createLargeFileData(1024 * 1024))
+	srv.cutAfter = 100 * 1024
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+
+	result := runFileDownload(srv, "--destination", destination, "--max-retries", "0")
+
+	if !strings.Contains(result.StdErr, "Download interrupted. Run the command again to continue.") {
+		t.Errorf("Expected stderr to show hint to continue download, but got: %v", result.StdErr)
+	}
+	if _, err := os.Stat(destination); err == nil {
+		t.Errorf("Expected destination not to be created for incomplete download")
+	}
+}
+
+func TestDownloadContinuesPartialDownloadWithRange(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(1024 * 1024)
+	srv := newBlobDownloadServer(data)
+	srv.cutAfter = 100 * 1024
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+	runFileDownload(srv, "--destination", destination, "--max-retries", "0")
+	partial, _ := os.Stat(destination + ".partial")
+
+	srv.cutAfter = 0
+	srv.ranges = []string{}
+	result := runFileDownload(srv, "--destination", destination)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	expectedRange := "bytes=" + strconv.FormatInt(partial.Size(), 10) + "-"
+	if len(srv.ranges) != 1 || srv.ranges[0] != expectedRange {
+		t.Errorf("Expected download to continue with range %v, but got: %v", expectedRange, srv.ranges)
+	}
+	content, _ := os.ReadFile(destination)
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected destination to contain downloaded content, but got %d bytes", len(content))
+	}
+}
+
+func TestDownloadStartsOverWhenFileChanged(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBlobDownloadServer(createLargeFileData(1024 * 1024))
+	srv.cutAfter = 100 * 1024
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+	runFileDownload(srv, "--destination", destination, "--max-retries", "0")
+
+	data := []byte("changed content")
+	srv.update(data)
+	srv.cutAfter = 0
+	result := runFileDownload(srv, "--destination", destination)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	content, _ := os.ReadFile(destination)
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected destination to contain changed content, but got: %v", string(content))
+	}
+}
+
+func TestDownloadChecksumMismatchReturnsError(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBlobDownloadServer([]byte("hello-world"))
+	srv.md5 = base64.StdEncoding.EncodeToString([]byte("invalid-checksum"))
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+
+	result := runFileDownload(srv, "--destination", destination)
+
+	if !strings.Contains(result.StdErr, "The downloaded file is corrupted") {
+		t.Errorf("Expected stderr to show checksum error, but got: %v", result.StdErr)
+	}
+	if _, err := os.Stat(destination); err == nil {
+		t.Errorf("Expected destination not to be created for corrupted download")
+	}
+	if _, err := os.Stat(destination + ".partial"); err == nil {
+		t.Errorf("Expected partial file to be removed for corrupted download")
+	}
+}
+
+func TestDownloadParallelChunks(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	srv := newBlobDownloadServer(data)
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+
+	result := runFileDownload(srv, "--destination", destination, "--parallel")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.ranges) != 3 {
+		t.Errorf("Expected file to be downloaded in 3 chunks, but got: %v", srv.ranges)
+	}
+	content, _ := os.ReadFile(destination)
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected destination to contain downloaded content, but got %d bytes", len(content))
+	}
+}
+
+func TestDownloadParallelContinuesMissingChunks(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	srv := newBlobDownloadServer(data)
+	srv.failRange = "bytes=8388608-16777215"
+	defer srv.Close()
+	destination := filepath.Join(t.TempDir(), "file.bin")
+	runFileDownload(srv, "--destination", destination, "--parallel")
+
+	srv.failRange = ""
+	srv.ranges = []string{}
+	result := runFileDownload(srv, "--destination", destination, "--parallel")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.ranges) != 1 || srv.ranges[0] != "bytes=8388608-16777215" {
+		t.Errorf("Expected only missing chunk to be downloaded, but got: %v", srv.ranges)
+	}
+	content, _ := os.ReadFile(destination)
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected destination to contain downloaded content, but got %d bytes", len(content))
+	}
+}
+
+func TestDownloadParallelWithoutDestinationShowsValidationError(t *testing.T) {
+	srv := newBlobDownloadServer([]byte("hello-world"))
+	defer srv.Close()
+
+	result := runFileDownload(srv, "--parallel")
+
+	if !strings.Contains(result.StdErr, "The --parallel parameter requires --destination") {
+		t.Errorf("Expected stderr to show that destination is missing, but got: %v", result.StdErr)
+	}
+}
+
+func runFileDownload(srv *blobDownloadServer, args ...string) test.Result {
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(DownloadCommand{}).
+		WithResponse(200, `{"Uri":"`+srv.URL+`/blob?sv=1&sig=abc"}`).
+		Build()
+	args = append([]string{"orchestrator", "buckets", "download", "--folder-id", "1", "--key", "2", "--path", "file.bin"}, args...)
+	return test.RunCli(args, context)
+}
+
+// blobDownloadServer serves a blob with support for range and conditional
+// requests. The connection can be cut after a number of bytes to simulate
+// an interrupted download.
+type blobDownloadServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	data      []byte
+	etag      string
+	md5       string
+	cutAfter  int
+	failRange string
+	ranges    []string
+}
+
+func (s *blobDownloadServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	data, etag, md5, cutAfter := s.data, s.etag, s.md5, s.cutAfter
+	if r.Header.Get("Range") != "" {
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+	}
+	failed := s.failRange != "" && r.Header.Get("Range") == s.failRange
+	s.mutex.Unlock()
+
+	if failed {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("x-ms-blob-content-md5", md5)
+	if cutAfter > 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data[:cutAfter])
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+		return
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+func (s *blobDownloadServer) update(data []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	hash := md5.Sum(data)
+	s.data = data
+	s.etag = `"` + strconv.FormatInt(time.Now().UnixNano(), 10) + `"`
+	s.md5 = base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func newBlobDownloadServer(data []byte) *blobDownloadServer {
+	server := &blobDownloadServer{ranges: []string{}}
+	server.update(data)
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
@@ -0,0 +1,25 @@
+package orchestrator
+
+// downloadJournal records the state of a download to a partial file so that an
+// interrupted download can be continued. The ETag of the blob is stored to
+// detect whether the blob changed in between. The size is -1 as long as it is
+// unknown.
+type downloadJournal struct {
+	ETag      string `json:"etag"`
+	Size      int64  `json:"size"`
+	MD5       string `json:"md5"`
+	ChunkSize int64  `json:"chunkSize"`
+	Chunks    []int  `json:"chunks"`
+}
+
+func (j downloadJournal) Completed() map[int]bool {
+	result := map[int]bool{}
+	for _, chunk := range j.Chunks {
+		result[chunk] = true
+	}
+	return result
+}
+
+func newDownloadJournal(chunkSize int64) *downloadJournal {
+	return &downloadJournal{"", -1, "", chunkSize, []int{}}
+}
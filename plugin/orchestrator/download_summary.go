@@ -0,0 +1,15 @@
+package orchestrator
+
+const (
+	downloadStatusDownloaded = "downloaded"
+	downloadStatusFailed     = "failed"
+)
+
+// downloadSummary is the result row for a single file of a recursive download.
+type downloadSummary struct {
+	Path   string `json:"path"`
+	File   string `json:"file"`
+	Size   int64  `json:"size"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
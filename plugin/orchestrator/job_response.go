@@ -0,0 +1,13 @@
+package orchestrator
+
+type jobResponse struct {
+	Id              int64   `json:"Id"`
+	Key             string  `json:"Key"`
+	State           string  `json:"State"`
+	Info            string  `json:"Info"`
+	OutputArguments *string `json:"OutputArguments"`
+}
+
+type jobsResponse struct {
+	Value []jobResponse `json:"value"`
+}
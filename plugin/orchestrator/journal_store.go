@@ -0,0 +1,67 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const journalDirectoryPermissions = 0700
+const journalFilePermissions = 0600
+
+// journalStore persists the journal of a transfer in the user cache
+// directory. The file name is derived from a key which identifies the transfer
+// so that every transfer has its own journal.
+type journalStore struct {
+	path string
+}
+
+// Read loads the journal into the provided value and returns false in case
+// no valid journal exists.
+func (s journalStore) Read(journal interface{}) (bool, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("Error reading journal '%s': %w", s.path, err)
+	}
+	err = json.Unmarshal(data, journal)
+	if err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s journalStore) Write(journal interface{}) error {
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return fmt.Errorf("Error writing journal: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(s.path), journalDirectoryPermissions)
+	if err != nil {
+		return fmt.Errorf("Error writing journal '%s': %w", s.path, err)
+	}
+	err = os.WriteFile(s.path, data, journalFilePermissions)
+	if err != nil {
+		return fmt.Errorf("Error writing journal '%s': %w", s.path, err)
+	}
+	return nil
+}
+
+func (s journalStore) Remove() {
+	_ = os.Remove(s.path)
+}
+
+func newJournalStore(directory string, key string) (*journalStore, error) {
+	userCacheDirectory, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("Error locating journal directory: %w", err)
+	}
+	hash := sha256.Sum256([]byte(key))
+	path := filepath.Join(userCacheDirectory, "uipath", directory, fmt.Sprintf("%x.json", hash))
+	return &journalStore{path}, nil
+}
@@ -0,0 +1,19 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// localPath resolves the slash-separated relative path of a bucket file in the
+// local root directory. The relative path is provided by the server, so paths
+// which would escape the root directory, e.g. using '..', are rejected.
+func localPath(root string, relativePath string) (string, error) {
+	path := filepath.Join(root, filepath.FromSlash(relativePath))
+	rel, err := filepath.Rel(filepath.Clean(root), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("Invalid file path '%s': the file is outside of the local directory '%s'", relativePath, root)
+	}
+	return path, nil
+}
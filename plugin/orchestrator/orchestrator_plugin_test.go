@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/UiPath/uipathcli/commandline"
 	"github.com/UiPath/uipathcli/test"
 )
 
@@ -308,6 +309,111 @@ servers:
 	}
 }
 
+func TestDownloadNotFoundWithFailReturnsClientErrorExitCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		_, _ = w.Write([]byte("blob not found"))
+	}))
+	defer srv.Close()
+
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(DownloadCommand{}).
+		WithResponse(200, `{"Uri":"`+srv.URL+`"}`).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "buckets", "download", "--folder-id", "1", "--key", "2", "--path", "file.txt", "--fail"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+	if result.StdOut != "blob not found" {
+		t.Errorf("Expected stdout to show response body, but got: %v", result.StdOut)
+	}
+	if !strings.Contains(result.StdErr, "Orchestrator returned status code '404' and body 'blob not found'") {
+		t.Errorf("Expected stderr to show that download failed, but got: %v", result.StdErr)
+	}
+}
+
+func TestDownloadNotFoundWithoutFailReturnsSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		_, _ = w.Write([]byte("blob not found"))
+	}))
+	defer srv.Close()
+
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(DownloadCommand{}).
+		WithResponse(200, `{"Uri":"`+srv.URL+`"}`).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "buckets", "download", "--folder-id", "1", "--key", "2", "--path", "file.txt"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if result.StdOut != "blob not found" {
+		t.Errorf("Expected stdout to show response body, but got: %v", result.StdOut)
+	}
+}
+
+func TestDownloadToOutFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("hello-world"))
+	}))
+	defer srv.Close()
+
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+
+	definition := `
+servers:
+- url: https://cloud.uipath.com/{organization}/{tenant}/orchestrator_
+  description: The production url
+`
+
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", definition).
+		WithConfig(config).
+		WithCommandPlugin(DownloadCommand{}).
+		WithResponse(200, `{"Uri":"`+srv.URL+`"}`).
+		Build()
+
+	path := createFile(t)
+	result := test.RunCli([]string{"orchestrator", "buckets", "download", "--folder-id", "1", "--key", "2", "--path", "file.txt", "--out-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if result.StdOut != "" {
+		t.Errorf("Expected stdout to be empty, but got: %v", result.StdOut)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "hello-world" {
+		t.Errorf("Expected file to contain downloaded content, but got: %v", string(data))
+	}
+}
+
 func createFile(t *testing.T) string {
 	tempFile, err := os.CreateTemp("", "uipath-test")
 	if err != nil {
@@ -0,0 +1,10 @@
+package orchestrator
+
+type releaseResponse struct {
+	Key  string `json:"Key"`
+	Name string `json:"Name"`
+}
+
+type releasesResponse struct {
+	Value []releaseResponse `json:"value"`
+}
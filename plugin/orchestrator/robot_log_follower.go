@@ -0,0 +1,24 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// robotLogFollower keeps track of the robot logs of a job which have already
+// been printed.
+type robotLogFollower struct {
+	baseUri  string
+	jobKey   string
+	LastId   int64
+	Disabled bool
+}
+
+func (f robotLogFollower) Uri() string {
+	filter := fmt.Sprintf("JobKey eq %s and Id gt %d", f.jobKey, f.LastId)
+	return f.baseUri + "/odata/RobotLogs?$filter=" + strings.ReplaceAll(filter, " ", "%20") + "&$orderby=Id%20asc"
+}
+
+func newRobotLogFollower(baseUri string, jobKey string) *robotLogFollower {
+	return &robotLogFollower{baseUri, jobKey, 0, false}
+}
@@ -0,0 +1,11 @@
+package orchestrator
+
+type robotLogResponse struct {
+	Id      int64  `json:"Id"`
+	Level   string `json:"Level"`
+	Message string `json:"Message"`
+}
+
+type robotLogsResponse struct {
+	Value []robotLogResponse `json:"value"`
+}
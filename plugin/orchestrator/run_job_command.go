@@ -0,0 +1,334 @@
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/plugin"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+const jobPollInterval = 1 * time.Second
+
+const (
+	jobStateSuccessful = "Successful"
+	jobStateFaulted    = "Faulted"
+	jobStateStopped    = "Stopped"
+	jobStateSuspended  = "Suspended"
+)
+
+// The RunJobCommand is a custom command for the orchestrator service which starts
+// a process and follows the job until it completes. It looks up the release of the
+// process, starts the job and streams the state changes and robot logs to stderr.
+// Once the job completed successfully, its output arguments are written to stdout.
+type RunJobCommand struct{}
+
+func (c RunJobCommand) Command() plugin.Command {
+	return *plugin.NewCommand("orchestrator").
+		WithCategory("jobs", "Orchestrator Jobs").
+		WithOperation("run", "Starts the process and waits for the job to complete").
+		WithParameter("process-name", plugin.ParameterTypeString, "The name of the process", true).
+		WithParameter("folder-id", plugin.ParameterTypeInteger, "Folder/OrganizationUnit Id", false).
+		WithParameter("folder-path", plugin.ParameterTypeString, "The full path of the folder, e.g. Shared/Finance", false).
+		WithParameter("input-file", plugin.ParameterTypeBinary, "JSON file with the input arguments of the process", false)
+}
+
+func (c RunJobCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	if context.Organization == "" {
+		return errors.New("Organization is not set")
+	}
+	if context.Tenant == "" {
+		return errors.New("Tenant is not set")
+	}
+	processName, err := getStringParameter("process-name", context.Parameters)
+	if err != nil {
+		return err
+	}
+	folder, err := c.folderHeader(context.Parameters)
+	if err != nil {
+		return err
+	}
+	inputArguments, err := c.inputArguments(context.Parameters)
+	if err != nil {
+		return err
+	}
+	baseUri := formatUri(context.BaseUri, context.Organization, context.Tenant)
+
+	phaseContext, span := context.StartPhase("find process")
+	releaseKey, err := c.findRelease(phaseContext, logger, baseUri, folder, processName)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	phaseContext, span = context.StartPhase("start job")
+	job, err := c.startJob(phaseContext, logger, baseUri, folder, releaseKey, inputArguments)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	phaseContext, span = context.StartPhase("follow job")
+	job, err = c.followJob(phaseContext, logger, baseUri, folder, *job)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	return c.writeResult(*job, writer)
+}
+
+func (c RunJobCommand) findRelease(context plugin.ExecutionContext, logger log.Logger, baseUri string, header http.Header, processName string) (string, error) {
+	filter := fmt.Sprintf("Name eq '%s'", strings.ReplaceAll(processName, "'", "''"))
+	uri := baseUri + "/odata/Releases?$filter=" + queryEscape(filter) + "&$select=Key,Name"
+	body, err := c.call(context, logger, http.MethodGet, uri, header, nil)
+	if err != nil {
+		return "", err
+	}
+	var result releasesResponse
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return "", fmt.Errorf("Error parsing json response: %w", err)
+	}
+	if len(result.Value) == 0 {
+		return "", fmt.Errorf("Could not find process '%s'", processName)
+	}
+	return result.Value[0].Key, nil
+}
+
+func (c RunJobCommand) startJob(context plugin.ExecutionContext, logger log.Logger, baseUri string, header http.Header, releaseKey string, inputArguments string) (*jobResponse, error) {
+	startInfo := map[string]interface{}{
+		"ReleaseKey": releaseKey,
+		"Strategy":   "ModernJobsCount",
+		"JobsCount":  1,
+	}
+	if inputArguments != "" {
+		startInfo["InputArguments"] = inputArguments
+	}
+	request, err := json.Marshal(map[string]interface{}{"startInfo": startInfo})
+	if err != nil {
+		return nil, fmt.Errorf("Error creating body: %w", err)
+	}
+	uri := baseUri + "/odata/Jobs/UiPath.Server.Configuration.OData.StartJobs"
+	body, err := c.call(context, logger, http.MethodPost, uri, header, request)
+	if err != nil {
+		return nil, err
+	}
+	var result jobsResponse
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing json response: %w", err)
+	}
+	if len(result.Value) == 0 {
+		return nil, errors.New("Orchestrator did not start any job")
+	}
+	return &result.Value[0], nil
+}
+
+// followJob polls the job until it reached a final state. State changes and
+// new robot logs are written to stderr while the job is running. Suspended
+// jobs can wait for a long time to be resumed, so polling is only bounded by
+// the deadline of the --timeout argument.
+func (c RunJobCommand) followJob(context plugin.ExecutionContext, logger log.Logger, baseUri string, header http.Header, job jobResponse) (*jobResponse, error) {
+	state := ""
+	logs := newRobotLogFollower(baseUri, job.Key)
+	for {
+		if job.State != state {
+			logger.LogError(c.stateText(context, job))
+			state = job.State
+		}
+		c.writeRobotLogs(context, logger, header, logs)
+		if c.completed(job.State) {
+			return &job, nil
+		}
+		err := utils.Sleep(context.Context, jobPollInterval)
+		if err != nil {
+			return nil, err
+		}
+		uri := baseUri + fmt.Sprintf("/odata/Jobs(%d)", job.Id)
+		body, err := c.call(context, logger, http.MethodGet, uri, header, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(body, &job)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing json response: %w", err)
+		}
+	}
+}
+
+// writeRobotLogs prints the robot logs which were added since the last poll.
+// Streaming the logs is stopped in case they cannot be retrieved, e.g. when
+// the user is not allowed to read them, without failing the command.
+func (c RunJobCommand) writeRobotLogs(context plugin.ExecutionContext, logger log.Logger, header http.Header, logs *robotLogFollower) {
+	if logs.Disabled {
+		return
+	}
+	body, err := c.call(context, logger, http.MethodGet, logs.Uri(), header, nil)
+	if err == nil {
+		var result robotLogsResponse
+		err = json.Unmarshal(body, &result)
+		for _, entry := range result.Value {
+			logger.LogError(fmt.Sprintf("[%s] %s\n", entry.Level, entry.Message))
+			logs.LastId = entry.Id
+		}
+	}
+	if err != nil {
+		logger.LogError(fmt.Sprintf("Could not retrieve robot logs: %v\n", err))
+		logs.Disabled = true
+	}
+}
+
+func (c RunJobCommand) stateText(context plugin.ExecutionContext, job jobResponse) string {
+	if job.State != jobStateSuspended {
+		return fmt.Sprintf("Job %d is %s\n", job.Id, job.State)
+	}
+	deadline, ok := context.Context.Deadline()
+	if !ok {
+		return fmt.Sprintf("Job %d is %s, waiting for it to be resumed without time limit (use --timeout to limit the wait)\n", job.Id, job.State)
+	}
+	return fmt.Sprintf("Job %d is %s, waiting for it to be resumed until %s\n", job.Id, job.State, deadline.Format(time.RFC3339))
+}
+
+func (c RunJobCommand) completed(state string) bool {
+	return state == jobStateSuccessful || state == jobStateFaulted || state == jobStateStopped
+}
+
+func (c RunJobCommand) writeResult(job jobResponse, writer output.OutputWriter) error {
+	switch job.State {
+	case jobStateFaulted:
+		return plugin.NewOperationFailedError(fmt.Sprintf("Job %d faulted: %s", job.Id, job.Info))
+	case jobStateStopped:
+		return plugin.NewOperationStoppedError(fmt.Sprintf("Job %d was stopped", job.Id))
+	}
+	outputArguments := "{}"
+	if job.OutputArguments != nil && *job.OutputArguments != "" {
+		outputArguments = *job.OutputArguments
+	}
+	var data interface{}
+	err := json.Unmarshal([]byte(outputArguments), &data)
+	if err != nil {
+		return fmt.Errorf("Error parsing output arguments of job %d: %w", job.Id, err)
+	}
+	return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", "HTTP/1.1", map[string][]string{}, strings.NewReader(outputArguments)))
+}
+
+func (c RunJobCommand) call(context plugin.ExecutionContext, logger log.Logger, method string, uri string, header http.Header, body []byte) ([]byte, error) {
+	var result []byte
+	err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		var err error
+		result, err = c.send(context.WithRetryAttempt(attempt), logger, method, uri, header, body)
+		return err
+	})
+	return result, err
+}
+
+func (c RunJobCommand) send(context plugin.ExecutionContext, logger log.Logger, method string, uri string, header http.Header, body []byte) ([]byte, error) {
+	var sent int32
+	trace := &httptrace.ClientTrace{
+		WroteHeaders: func() { atomic.StoreInt32(&sent, 1) },
+	}
+	ctx := httptrace.WithClientTrace(context.Context, trace)
+	request, err := http.NewRequestWithContext(ctx, method, uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("Error preparing request: %w", err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range context.Auth.Header {
+		request.Header.Add(key, value)
+	}
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	client, err := httpClient(context.Network)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		err = fmt.Errorf("Error sending request: %w", err)
+		// Starting the job is not idempotent, it is only retried when
+		// the request did not reach orchestrator at all.
+		if method != http.MethodGet && atomic.LoadInt32(&sent) != 0 {
+			return nil, err
+		}
+		return nil, utils.Retryable(err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, utils.Retryable(fmt.Errorf("Error reading response: %w", err))
+	}
+	logResponse(logger, response, responseBody)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, c.statusError(context, method, response, responseBody)
+	}
+	return responseBody, nil
+}
+
+func (c RunJobCommand) folderHeader(parameters []plugin.ExecutionParameter) (http.Header, error) {
+	header := http.Header{}
+	folderId, err := getIntParameter("folder-id", parameters)
+	if err == nil {
+		header.Set("X-UiPath-OrganizationUnitId", fmt.Sprintf("%d", folderId))
+		return header, nil
+	}
+	folderPath, err := getStringParameter("folder-path", parameters)
+	if err == nil {
+		header.Set("X-UiPath-FolderPath", folderPath)
+		return header, nil
+	}
+	return nil, errors.New("Either --folder-id or --folder-path needs to be provided")
+}
+
+// inputArguments reads the input arguments file and returns its content
+// which orchestrator expects as serialized JSON object. The content is only
+// compacted and not re-encoded to keep large numbers and the key order.
+func (c RunJobCommand) inputArguments(parameters []plugin.ExecutionParameter) (string, error) {
+	file, err := getFileParameter("input-file", parameters)
+	if err != nil {
+		return "", nil
+	}
+	reader, err := file.Data()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("Error reading input arguments from '%s': %w", file.Name(), err)
+	}
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) || len(data) == 0 || data[0] != '{' {
+		return "", fmt.Errorf("Input arguments from '%s' must be a JSON object", file.Name())
+	}
+	result := bytes.Buffer{}
+	err = json.Compact(&result, data)
+	if err != nil {
+		return "", fmt.Errorf("Error serializing input arguments: %w", err)
+	}
+	return result.String(), nil
+}
+
+// statusError marks the error as retryable based on the retry policy. Requests
+// which are not idempotent, like starting a job, are only retried when
+// orchestrator rejected them with '429 Too Many Requests' because the job
+// might have been started already in case of other errors like '502 Bad Gateway'.
+func (c RunJobCommand) statusError(context plugin.ExecutionContext, method string, response *http.Response, body []byte) error {
+	if method != http.MethodGet && response.StatusCode != http.StatusTooManyRequests {
+		context.RetryPolicy.StatusCodes = []int{http.StatusTooManyRequests}
+	}
+	return plugin.NewStatusError(context, "Orchestrator", response, body)
+}
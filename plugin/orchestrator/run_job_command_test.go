@@ -0,0 +1,358 @@
+package orchestrator
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+	"github.com/UiPath/uipathcli/test"
+)
+
+const runJobConfig = `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+
+func TestRunJobWithoutProcessNameShowsValidationError(t *testing.T) {
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithCommandPlugin(RunJobCommand{}).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "jobs", "run", "--folder-id", "1"}, context)
+
+	if !strings.Contains(result.StdErr, "Argument --process-name is missing") {
+		t.Errorf("Expected stderr to show that process-name parameter is missing, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobWithoutFolderShowsValidationError(t *testing.T) {
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(runJobConfig).
+		WithCommandPlugin(RunJobCommand{}).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "jobs", "run", "--process-name", "MyProcess"}, context)
+
+	if !strings.Contains(result.StdErr, "Either --folder-id or --folder-path needs to be provided") {
+		t.Errorf("Expected stderr to show that the folder is missing, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobWithoutOrganizationShowsValidationError(t *testing.T) {
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithCommandPlugin(RunJobCommand{}).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "jobs", "run", "--folder-id", "1", "--process-name", "MyProcess"}, context)
+
+	if !strings.Contains(result.StdErr, "Organization is not set") {
+		t.Errorf("Expected stderr to show that organization is not set, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobInputFileWithoutObjectShowsValidationError(t *testing.T) {
+	path := createFile(t)
+	writeFile(path, []byte(`[1,2,3]`))
+
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(runJobConfig).
+		WithCommandPlugin(RunJobCommand{}).
+		Build()
+
+	result := test.RunCli([]string{"orchestrator", "jobs", "run", "--folder-id", "1", "--process-name", "MyProcess", "--input-file", path}, context)
+
+	if !strings.Contains(result.StdErr, "must be a JSON object") {
+		t.Errorf("Expected stderr to show that input arguments are invalid, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobProcessNotFoundReturnsError(t *testing.T) {
+	srv := newJobServer(t, nil, nil)
+	srv.releases = `{"value":[]}`
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "Unknown")
+
+	if !strings.Contains(result.StdErr, "Could not find process 'Unknown'") {
+		t.Errorf("Expected stderr to show that process was not found, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobSuccessfullyOutputsArguments(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Successful","OutputArguments":"{\"Total\":42}"}`,
+	}, nil)
+	defer srv.Close()
+
+	path := createFile(t)
+	writeFile(path, []byte(`{"Amount": 21}`))
+
+	result := runJob(srv, "--folder-path", "Shared/Finance", "--process-name", "My Process", "--input-file", path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	expectedStdOut := `{
+  "Total": 42
+}
+`
+	if result.StdOut != expectedStdOut {
+		t.Errorf("Expected output arguments on stdout, but got: %v", result.StdOut)
+	}
+	if srv.releaseQuery != "$filter=Name%20eq%20%27My%20Process%27&$select=Key,Name" {
+		t.Errorf("Expected release to be looked up by name, but got: %v", srv.releaseQuery)
+	}
+	if srv.folderPath != "Shared/Finance" {
+		t.Errorf("Expected folder path header, but got: %v", srv.folderPath)
+	}
+	expectedBody := `{"startInfo":{"InputArguments":"{\"Amount\":21}","JobsCount":1,"ReleaseKey":"release-key","Strategy":"ModernJobsCount"}}`
+	if srv.startBody != expectedBody {
+		t.Errorf("Expected start job request body %v, but got: %v", expectedBody, srv.startBody)
+	}
+}
+
+func TestRunJobWithoutOutputArgumentsReturnsEmptyObject(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Successful","OutputArguments":null}`,
+	}, nil)
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if result.StdOut != "{}\n" {
+		t.Errorf("Expected empty object on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestRunJobStreamsStateAndRobotLogs(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Pending"}`,
+		`{"Id":7,"Key":"k-7","State":"Successful","OutputArguments":"{}"}`,
+	}, []string{
+		`{"value":[{"Id":1,"Level":"Info","Message":"Process started"}]}`,
+		`{"value":[{"Id":2,"Level":"Info","Message":"Process finished"}]}`,
+	})
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	expectedStdErr := `Job 7 is Pending
+[Info] Process started
+Job 7 is Successful
+[Info] Process finished
+`
+	if result.StdErr != expectedStdErr {
+		t.Errorf("Expected job state and logs on stderr, but got: %v", result.StdErr)
+	}
+	if srv.folderId != "1" {
+		t.Errorf("Expected folder id header, but got: %v", srv.folderId)
+	}
+	if srv.logQuery != "$filter=JobKey%20eq%20k-7%20and%20Id%20gt%201&$orderby=Id%20asc" {
+		t.Errorf("Expected robot logs to be requested after last id, but got: %v", srv.logQuery)
+	}
+}
+
+func TestRunJobFaultedReturnsOperationFailedExitCode(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Faulted","Info":"Invalid input"}`,
+	}, nil)
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if !strings.Contains(result.StdErr, "Job 7 faulted: Invalid input") {
+		t.Errorf("Expected stderr to show that job faulted, but got: %v", result.StdErr)
+	}
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeOperationFailed {
+		t.Errorf("Expected operation failed exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestRunJobStoppedReturnsOperationStoppedExitCode(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Stopped"}`,
+	}, nil)
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if !strings.Contains(result.StdErr, "Job 7 was stopped") {
+		t.Errorf("Expected stderr to show that job was stopped, but got: %v", result.StdErr)
+	}
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeOperationStopped {
+		t.Errorf("Expected operation stopped exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestRunJobRobotLogsFailureContinuesWithoutLogs(t *testing.T) {
+	srv := newJobServer(t, []string{
+		`{"Id":7,"Key":"k-7","State":"Successful","OutputArguments":"{}"}`,
+	}, nil)
+	srv.logStatus = http.StatusForbidden
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if !strings.Contains(result.StdErr, "Could not retrieve robot logs") {
+		t.Errorf("Expected stderr to show that logs could not be retrieved, but got: %v", result.StdErr)
+	}
+}
+
+func TestRunJobStartRejectedWithBadGatewayIsNotRetried(t *testing.T) {
+	srv := newJobServer(t, []string{`{"Id":7,"Key":"k-7","State":"Successful"}`}, nil)
+	srv.startStatus = []int{http.StatusBadGateway}
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if !strings.Contains(result.StdErr, "Orchestrator returned status code '502'") {
+		t.Errorf("Expected stderr to show bad gateway error, but got: %v", result.StdErr)
+	}
+	if srv.startCount != 1 {
+		t.Errorf("Expected start job request not to be retried, but got %d requests", srv.startCount)
+	}
+}
+
+func TestRunJobStartRejectedWithTooManyRequestsIsRetried(t *testing.T) {
+	srv := newJobServer(t, []string{`{"Id":7,"Key":"k-7","State":"Successful"}`}, nil)
+	srv.startStatus = []int{http.StatusTooManyRequests}
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if srv.startCount != 2 {
+		t.Errorf("Expected start job request to be retried once, but got %d requests", srv.startCount)
+	}
+}
+
+func TestRunJobKeepsInputArgumentsAsIs(t *testing.T) {
+	srv := newJobServer(t, []string{`{"Id":7,"Key":"k-7","State":"Successful"}`}, nil)
+	defer srv.Close()
+	path := createFile(t)
+	writeFile(path, []byte(`{ "Zeta": 12345678901234567890, "Alpha": 0.10 }`))
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess", "--input-file", path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	expectedBody := `{"startInfo":{"InputArguments":"{\"Zeta\":12345678901234567890,\"Alpha\":0.10}","JobsCount":1,"ReleaseKey":"release-key","Strategy":"ModernJobsCount"}}`
+	if srv.startBody != expectedBody {
+		t.Errorf("Expected start job request body %v, but got: %v", expectedBody, srv.startBody)
+	}
+}
+
+func TestRunJobSuspendedJobStopsWaitingAtTimeout(t *testing.T) {
+	srv := newJobServer(t, []string{`{"Id":7,"Key":"k-7","State":"Suspended"}`}, nil)
+	defer srv.Close()
+
+	result := runJob(srv, "--folder-id", "1", "--process-name", "MyProcess", "--timeout", "2s")
+
+	if !strings.Contains(result.StdErr, "Job 7 is Suspended, waiting for it to be resumed until") {
+		t.Errorf("Expected stderr to show how long the suspended job is awaited, but got: %v", result.StdErr)
+	}
+	if !strings.Contains(result.StdErr, "timed out") {
+		t.Errorf("Expected stderr to show timeout, but got: %v", result.StdErr)
+	}
+}
+
+func runJob(srv *jobServer, args ...string) test.Result {
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(runJobConfig).
+		WithCommandPlugin(RunJobCommand{}).
+		Build()
+	args = append([]string{"orchestrator", "jobs", "run"}, args...)
+	args = append(args, "--uri", srv.URL+"/my-org/my-tenant/orchestrator_")
+	return test.RunCli(args, context)
+}
+
+// jobServer simulates the orchestrator endpoints used to run a job. The
+// start job request returns the first job state, every poll the next one.
+type jobServer struct {
+	*httptest.Server
+	mutex        sync.Mutex
+	releases     string
+	jobs         []string
+	logs         []string
+	logStatus    int
+	releaseQuery string
+	logQuery     string
+	startBody    string
+	startStatus  []int
+	startCount   int
+	folderId     string
+	folderPath   string
+}
+
+func (s *jobServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.folderId = r.Header.Get("X-UiPath-OrganizationUnitId")
+	s.folderPath = r.Header.Get("X-UiPath-FolderPath")
+	path := strings.TrimPrefix(r.URL.Path, "/my-org/my-tenant/orchestrator_")
+	switch {
+	case path == "/odata/Releases":
+		s.releaseQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(s.releases))
+	case path == "/odata/Jobs/UiPath.Server.Configuration.OData.StartJobs":
+		s.startCount++
+		if len(s.startStatus) > 0 {
+			w.WriteHeader(s.startStatus[0])
+			s.startStatus = s.startStatus[1:]
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.startBody = string(body)
+		_, _ = w.Write([]byte(`{"value":[` + s.nextJob() + `]}`))
+	case strings.HasPrefix(path, "/odata/Jobs("):
+		_, _ = w.Write([]byte(s.nextJob()))
+	case path == "/odata/RobotLogs":
+		if s.logStatus != 0 {
+			w.WriteHeader(s.logStatus)
+			return
+		}
+		s.logQuery = r.URL.RawQuery
+		if len(s.logs) == 0 {
+			_, _ = w.Write([]byte(`{"value":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(s.logs[0]))
+		s.logs = s.logs[1:]
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *jobServer) nextJob() string {
+	job := s.jobs[0]
+	if len(s.jobs) > 1 {
+		s.jobs = s.jobs[1:]
+	}
+	return job
+}
+
+func newJobServer(t *testing.T, jobs []string, logs []string) *jobServer {
+	server := &jobServer{
+		releases: `{"value":[{"Key":"release-key","Name":"MyProcess"}]}`,
+		jobs:     jobs,
+		logs:     logs,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
@@ -0,0 +1,16 @@
+package orchestrator
+
+const (
+	syncActionUpload   = "upload"
+	syncActionDownload = "download"
+	syncActionDelete   = "delete"
+)
+
+// syncAction describes a single file operation which is needed to bring the
+// target in sync with the source.
+type syncAction struct {
+	Action string `json:"action"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Error  string `json:"error,omitempty"`
+}
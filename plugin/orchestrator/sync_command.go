@@ -0,0 +1,521 @@
+package orchestrator
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/plugin"
+	"github.com/UiPath/uipathcli/utils"
+)
+
+const (
+	syncDirectionUp      = "up"
+	syncDirectionDown    = "down"
+	syncDefaultTransfers = 4
+)
+
+// The SyncCommand is a custom command for the orchestrator service which mirrors
+// a local directory into a storage bucket or the other way around. It lists the
+// files on both sides, compares them and uploads, downloads or deletes files so
+// that the target matches the source.
+//
+// The transfers reuse the upload and download commands and are executed in
+// parallel up to the configured number of transfers.
+type SyncCommand struct{}
+
+func (c SyncCommand) Command() plugin.Command {
+	return *plugin.NewCommand("orchestrator").
+		WithCategory("buckets", "Orchestrator Buckets").
+		WithOperation("sync", "Synchronizes a local directory with the bucket").
+		WithParameter("folder-id", plugin.ParameterTypeInteger, "Folder/OrganizationUnit Id", true).
+		WithParameter("key", plugin.ParameterTypeInteger, "The Bucket Id", true).
+		WithParameter("local", plugin.ParameterTypeString, "The local directory", true).
+		WithParameter("remote", plugin.ParameterTypeString, "The directory in the bucket", false).
+		WithParameter("direction", plugin.ParameterTypeString, "The sync direction: up (local to bucket, default) or down (bucket to local)", false).
+		WithParameter("delete", plugin.ParameterTypeBoolean, "Delete files which do not exist in the source", false).
+		WithParameter("include", plugin.ParameterTypeStringArray, "Only sync files matching the glob patterns", false).
+		WithParameter("exclude", plugin.ParameterTypeStringArray, "Skip files matching the glob patterns", false).
+		WithParameter("checksum", plugin.ParameterTypeBoolean, "Compare files of equal size using their MD5 checksum instead of the modification time", false).
+		WithParameter("transfers", plugin.ParameterTypeInteger, "Number of files to transfer in parallel (default 4)", false).
+		SupportsDryRun()
+}
+
+func (c SyncCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	if context.Organization == "" {
+		return errors.New("Organization is not set")
+	}
+	if context.Tenant == "" {
+		return errors.New("Tenant is not set")
+	}
+	folderId, err := getIntParameter("folder-id", context.Parameters)
+	if err != nil {
+		return err
+	}
+	bucketId, err := getIntParameter("key", context.Parameters)
+	if err != nil {
+		return err
+	}
+	local, err := getStringParameter("local", context.Parameters)
+	if err != nil {
+		return err
+	}
+	remote, _ := getStringParameter("remote", context.Parameters)
+	remote = strings.Trim(remote, "/")
+	direction, err := getStringParameter("direction", context.Parameters)
+	if err != nil {
+		direction = syncDirectionUp
+	}
+	if direction != syncDirectionUp && direction != syncDirectionDown {
+		return fmt.Errorf("Invalid value for --direction: needs to be '%s' or '%s'", syncDirectionUp, syncDirectionDown)
+	}
+	transfers, err := getIntParameter("transfers", context.Parameters)
+	if err != nil {
+		transfers = syncDefaultTransfers
+	}
+	if transfers <= 0 {
+		return errors.New("Invalid value for --transfers: needs to be greater than 0")
+	}
+	filter := newSyncFilter(getStringArrayParameter("include", context.Parameters), getStringArrayParameter("exclude", context.Parameters))
+	target := newSyncTarget(formatUri(context.BaseUri, context.Organization, context.Tenant), folderId, bucketId, local, remote)
+
+	phaseContext, span := context.StartPhase("list files")
+	localFiles, remoteFiles, err := c.listFiles(phaseContext, logger, *target, direction, *filter)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	phaseContext, span = context.StartPhase("compare files")
+	result, err := c.plan(phaseContext, logger, *target, direction, localFiles, remoteFiles, transfers)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	return c.sync(context, writer, logger, *target, *result, transfers)
+}
+
+func (c SyncCommand) listFiles(context plugin.ExecutionContext, logger log.Logger, target syncTarget, direction string, filter syncFilter) (map[string]syncFile, map[string]syncFile, error) {
+	localFiles, err := c.localFiles(target.Local, direction, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	remoteFiles, err := c.remoteFiles(context, logger, target, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return localFiles, remoteFiles, nil
+}
+
+// plan compares the local and remote files and returns the actions which are
+// needed to bring the target in sync with the source.
+func (c SyncCommand) plan(context plugin.ExecutionContext, logger log.Logger, target syncTarget, direction string, localFiles map[string]syncFile, remoteFiles map[string]syncFile, transfers int) (*syncResult, error) {
+	source, destination := localFiles, remoteFiles
+	transferAction := syncActionUpload
+	if direction == syncDirectionDown {
+		source, destination = remoteFiles, localFiles
+		transferAction = syncActionDownload
+	}
+	checksum := getBoolParameter("checksum", context.Parameters)
+
+	result := syncResult{Direction: direction, DryRun: context.DryRun, Actions: []syncAction{}}
+	compare := []string{}
+	for _, relativePath := range c.sortedPaths(source) {
+		sourceFile := source[relativePath]
+		destinationFile, found := destination[relativePath]
+		if !found || sourceFile.Size != destinationFile.Size {
+			result.Actions = append(result.Actions, syncAction{Action: transferAction, Path: relativePath, Size: sourceFile.Size})
+		} else {
+			compare = append(compare, relativePath)
+		}
+	}
+
+	changed := make([]bool, len(compare))
+	errs := c.parallel(len(compare), transfers, func(index int) error {
+		var err error
+		changed[index], err = c.changed(context, logger, target, direction, compare[index], localFiles[compare[index]], checksum)
+		return err
+	})
+	for index, relativePath := range compare {
+		if errs[index] != nil {
+			return nil, errs[index]
+		}
+		if changed[index] {
+			result.Actions = append(result.Actions, syncAction{Action: transferAction, Path: relativePath, Size: source[relativePath].Size})
+		} else {
+			result.Unchanged++
+		}
+	}
+
+	if getBoolParameter("delete", context.Parameters) {
+		for _, relativePath := range c.sortedPaths(destination) {
+			if _, found := source[relativePath]; !found {
+				result.Actions = append(result.Actions, syncAction{Action: syncActionDelete, Path: relativePath, Size: destination[relativePath].Size})
+			}
+		}
+	}
+	return &result, nil
+}
+
+// sync performs the planned actions in parallel. Failed actions do not stop
+// the other transfers but are reported in the result.
+func (c SyncCommand) sync(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger, target syncTarget, result syncResult, transfers int) error {
+	if !context.DryRun {
+		phaseContext, span := context.StartPhase("sync files")
+		direction := result.Direction
+		errs := c.parallel(len(result.Actions), transfers, func(index int) error {
+			return c.execute(phaseContext, logger, target, direction, result.Actions[index])
+		})
+		failed := 0
+		for index, err := range errs {
+			action := result.Actions[index]
+			if err != nil {
+				failed++
+				result.Actions[index].Error = err.Error()
+				logger.LogError(fmt.Sprintf("Failed to %s %s: %v\n", action.Action, action.Path, err))
+			}
+		}
+		var err error
+		if failed > 0 {
+			err = fmt.Errorf("Failed to sync %d of %d files", failed, len(result.Actions))
+		}
+		span.End(err)
+		writeErr := c.writeResult(result, writer)
+		if err != nil {
+			return err
+		}
+		return writeErr
+	}
+	return c.writeResult(result, writer)
+}
+
+func (c SyncCommand) execute(context plugin.ExecutionContext, logger log.Logger, target syncTarget, direction string, action syncAction) error {
+	var err error
+	switch {
+	case action.Action == syncActionUpload:
+		err = c.upload(context, logger, target, action.Path)
+	case action.Action == syncActionDownload:
+		err = c.download(context, logger, target, action.Path)
+	case direction == syncDirectionUp:
+		err = c.deleteRemote(context, logger, target, action.Path)
+	default:
+		err = c.deleteLocal(target, action.Path)
+	}
+	if err == nil {
+		logger.LogError(fmt.Sprintf("%s %s\n", c.actionText(action.Action), action.Path))
+	}
+	return err
+}
+
+func (c SyncCommand) actionText(action string) string {
+	switch action {
+	case syncActionUpload:
+		return "Uploaded"
+	case syncActionDownload:
+		return "Downloaded"
+	default:
+		return "Deleted"
+	}
+}
+
+func (c SyncCommand) upload(context plugin.ExecutionContext, logger log.Logger, target syncTarget, relativePath string) error {
+	source, err := target.LocalPath(relativePath)
+	if err != nil {
+		return err
+	}
+	context.Input = nil
+	context.Parameters = []plugin.ExecutionParameter{
+		*plugin.NewExecutionParameter("folder-id", target.FolderId),
+		*plugin.NewExecutionParameter("key", target.BucketId),
+		*plugin.NewExecutionParameter("path", target.RemotePath(relativePath)),
+		*plugin.NewExecutionParameter("file", utils.NewFileStream(source)),
+	}
+	return UploadCommand{}.Execute(context, nil, log.NewQuietLogger(logger))
+}
+
+func (c SyncCommand) download(context plugin.ExecutionContext, logger log.Logger, target syncTarget, relativePath string) error {
+	destination, err := target.LocalPath(relativePath)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(destination), 0755)
+	if err != nil {
+		return fmt.Errorf("Error creating directory '%s': %w", filepath.Dir(destination), err)
+	}
+	context.Input = nil
+	context.Parameters = []plugin.ExecutionParameter{
+		*plugin.NewExecutionParameter("folder-id", target.FolderId),
+		*plugin.NewExecutionParameter("key", target.BucketId),
+		*plugin.NewExecutionParameter("path", target.RemotePath(relativePath)),
+		*plugin.NewExecutionParameter("destination", destination),
+	}
+	return DownloadCommand{}.Execute(context, nil, log.NewQuietLogger(logger))
+}
+
+func (c SyncCommand) deleteRemote(context plugin.ExecutionContext, logger log.Logger, target syncTarget, relativePath string) error {
+	uri := target.BaseUri + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.DeleteFile?path=%s", target.BucketId, queryEscape(target.RemotePath(relativePath)))
+	_, err := c.call(context, logger, http.MethodDelete, uri, target)
+	return err
+}
+
+func (c SyncCommand) deleteLocal(target syncTarget, relativePath string) error {
+	path, err := target.LocalPath(relativePath)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("Error deleting file: %w", err)
+	}
+	return nil
+}
+
+// changed compares a local and a remote file of the same size. The file is
+// considered changed when the source is newer than the target. With --checksum,
+// the MD5 checksum of the blob is compared instead when the storage provides it.
+// Files which are empty on both sides are always unchanged.
+func (c SyncCommand) changed(context plugin.ExecutionContext, logger log.Logger, target syncTarget, direction string, relativePath string, localFile syncFile, checksum bool) (bool, error) {
+	if localFile.Size == 0 {
+		return false, nil
+	}
+	md5, lastModified, err := c.remoteProperties(context, logger, target, relativePath)
+	if err != nil {
+		return false, err
+	}
+	if checksum && md5 != "" {
+		path, err := target.LocalPath(relativePath)
+		if err != nil {
+			return false, err
+		}
+		localMd5, err := c.localMd5(path)
+		if err != nil {
+			return false, err
+		}
+		return localMd5 != md5, nil
+	}
+	if lastModified.IsZero() {
+		logger.LogError(fmt.Sprintf("Skipping %s: the storage provides neither an MD5 checksum nor a modification time\n", relativePath))
+		return false, nil
+	}
+	if direction == syncDirectionUp {
+		// Last-Modified only has a precision of seconds
+		return localFile.Modified.Truncate(time.Second).After(lastModified), nil
+	}
+	return lastModified.After(localFile.Modified), nil
+}
+
+// remoteProperties retrieves the MD5 checksum and the modification time of the
+// blob by requesting its first byte using the read url. The bucket file listing
+// does not contain the modification time, so every file needs to be probed.
+// A HEAD request cannot be used because the read url of some storage
+// providers is only signed for GET requests.
+func (c SyncCommand) remoteProperties(context plugin.ExecutionContext, logger log.Logger, target syncTarget, relativePath string) (string, time.Time, error) {
+	uri := target.BaseUri + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetReadUri?path=%s", target.BucketId, queryEscape(target.RemotePath(relativePath)))
+	body, err := c.call(context, logger, http.MethodGet, uri, target)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var readUrl urlResponse
+	err = json.Unmarshal(body, &readUrl)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Error parsing json response: %w", err)
+	}
+
+	var header http.Header
+	err = utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		request, err := http.NewRequestWithContext(utils.WithRetryAttempt(context.Context, attempt), http.MethodGet, readUrl.Uri, &bytes.Buffer{})
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Range", "bytes=0-0")
+		response, err := c.send(context, logger, request)
+		if err != nil {
+			return err
+		}
+		header = response.Header
+		return nil
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	md5 := header.Get("x-ms-blob-content-md5")
+	if md5 == "" {
+		md5 = header.Get("Content-MD5")
+	}
+	lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+	return md5, lastModified, nil
+}
+
+func (c SyncCommand) localMd5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading file '%s': %w", path, err)
+	}
+	defer file.Close()
+	hash := md5.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("Error reading file '%s': %w", path, err)
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (c SyncCommand) localFiles(directory string, direction string, filter syncFilter) (map[string]syncFile, error) {
+	result := map[string]syncFile{}
+	info, err := os.Stat(directory)
+	if err != nil && errors.Is(err, os.ErrNotExist) && direction == syncDirectionDown {
+		return result, nil
+	}
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("Local directory '%s' not found", directory)
+	}
+	err = filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(filePath, ".partial") {
+			return nil
+		}
+		relativePath, err := filepath.Rel(directory, filePath)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if !filter.Matches(relativePath) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		result[relativePath] = syncFile{info.Size(), info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error reading local directory '%s': %w", directory, err)
+	}
+	return result, nil
+}
+
+func (c SyncCommand) remoteFiles(context plugin.ExecutionContext, logger log.Logger, target syncTarget, filter syncFilter) (map[string]syncFile, error) {
+	lister := newBucketFileLister(func(uri string) ([]byte, error) {
+		return c.call(context, logger, http.MethodGet, uri, target)
+	})
+	files, err := lister.List(target.BaseUri, target.BucketId, target.Remote)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if target.Remote != "" {
+		prefix = target.Remote + "/"
+	}
+	result := map[string]syncFile{}
+	for _, file := range files {
+		relativePath := strings.TrimPrefix(file.FullPath, prefix)
+		if filter.Matches(relativePath) {
+			result[relativePath] = syncFile{file.Size, time.Time{}}
+		}
+	}
+	return result, nil
+}
+
+// parallel invokes the function for all indexes using the given number of
+// goroutines and returns the error for every index.
+func (c SyncCommand) parallel(count int, concurrency int, f func(index int) error) []error {
+	errs := make([]error, count)
+	indexes := make(chan int, count)
+	for index := 0; index < count; index++ {
+		indexes <- index
+	}
+	close(indexes)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				errs[index] = f(index)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func (c SyncCommand) sortedPaths(files map[string]syncFile) []string {
+	result := []string{}
+	for relativePath := range files {
+		result = append(result, relativePath)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c SyncCommand) writeResult(result syncResult, writer output.OutputWriter) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("Error serializing sync result: %w", err)
+	}
+	return writer.WriteResponse(*output.NewResponseInfo(http.StatusOK, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader(data)))
+}
+
+func (c SyncCommand) call(context plugin.ExecutionContext, logger log.Logger, method string, uri string, target syncTarget) ([]byte, error) {
+	var result []byte
+	err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		request, err := http.NewRequestWithContext(utils.WithRetryAttempt(context.Context, attempt), method, uri, &bytes.Buffer{})
+		if err != nil {
+			return fmt.Errorf("Error preparing request: %w", err)
+		}
+		request.Header.Set("X-UiPath-OrganizationUnitId", fmt.Sprintf("%d", target.FolderId))
+		for key, value := range context.Auth.Header {
+			request.Header.Add(key, value)
+		}
+		response, err := c.send(context, logger, request)
+		if err != nil {
+			return err
+		}
+		result, err = io.ReadAll(response.Body)
+		return err
+	})
+	return result, err
+}
+
+// send executes the request and returns the response with the body already
+// read into memory.
+func (c SyncCommand) send(context plugin.ExecutionContext, logger log.Logger, request *http.Request) (*http.Response, error) {
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	client, err := httpClient(context.Network)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, utils.Retryable(fmt.Errorf("Error sending request: %w", err))
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, utils.Retryable(fmt.Errorf("Error reading response: %w", err))
+	}
+	logResponse(logger, response, body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	return response, nil
+}
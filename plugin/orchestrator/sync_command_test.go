@@ -0,0 +1,497 @@
+package orchestrator
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UiPath/uipathcli/test"
+)
+
+func TestSyncInvalidDirectionShowsValidationError(t *testing.T) {
+	srv := newBucketServer(map[string]string{})
+	defer srv.Close()
+
+	result := runSync(srv, "--local", t.TempDir(), "--direction", "sideways")
+
+	if !strings.Contains(result.StdErr, "Invalid value for --direction: needs to be 'up' or 'down'") {
+		t.Errorf("Expected stderr to show invalid direction, but got: %v", result.StdErr)
+	}
+}
+
+func TestSyncUpLocalDirectoryNotFoundShowsError(t *testing.T) {
+	srv := newBucketServer(map[string]string{})
+	defer srv.Close()
+
+	result := runSync(srv, "--local", "does-not-exist")
+
+	if !strings.Contains(result.StdErr, "Local directory 'does-not-exist' not found") {
+		t.Errorf("Expected stderr to show that directory was not found, but got: %v", result.StdErr)
+	}
+}
+
+func TestSyncUpUploadsNewAndChangedFiles(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/same.txt":    "same",
+		"artifacts/changed.txt": "old",
+		"other/file.txt":        "other",
+	})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{
+		"same.txt":        "same",
+		"changed.txt":     "new content",
+		"nested/new.txt":  "new",
+		"nested/new2.txt": "new2",
+	})
+
+	result := runSync(srv, "--local", local, "--remote", "artifacts/")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	expected := map[string]string{
+		"artifacts/same.txt":        "same",
+		"artifacts/changed.txt":     "new content",
+		"artifacts/nested/new.txt":  "new",
+		"artifacts/nested/new2.txt": "new2",
+		"other/file.txt":            "other",
+	}
+	assertFiles(t, srv.snapshot(), expected)
+	syncResult := parseSyncResult(t, result.StdOut)
+	if len(syncResult.Actions) != 3 || syncResult.Unchanged != 1 {
+		t.Errorf("Expected 3 uploads and 1 unchanged file, but got: %v", result.StdOut)
+	}
+	if !strings.Contains(result.StdErr, "Uploaded nested/new.txt") {
+		t.Errorf("Expected stderr to show uploaded files, but got: %v", result.StdErr)
+	}
+}
+
+func TestSyncUpWithDeleteRemovesRemoteFiles(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/keep.txt":   "keep",
+		"artifacts/remove.txt": "remove",
+		"other/file.txt":       "other",
+	})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"keep.txt": "keep"})
+
+	result := runSync(srv, "--local", local, "--remote", "artifacts", "--delete")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{
+		"artifacts/keep.txt": "keep",
+		"other/file.txt":     "other",
+	})
+	if srv.deletedFolder != "1" {
+		t.Errorf("Expected folder header for delete request, but got: %v", srv.deletedFolder)
+	}
+}
+
+func TestSyncUpWithoutDeleteKeepsRemoteFiles(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"remove.txt": "remove"})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"keep.txt": "keep"})
+
+	result := runSync(srv, "--local", local)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"keep.txt": "keep", "remove.txt": "remove"})
+}
+
+func TestSyncDownDownloadsFiles(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{
+		"artifacts/a.txt":        "a",
+		"artifacts/nested/b.txt": "b",
+	})
+	defer srv.Close()
+	local := filepath.Join(t.TempDir(), "download")
+
+	result := runSync(srv, "--local", local, "--remote", "artifacts", "--direction", "down")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, readLocalTree(t, local), map[string]string{"a.txt": "a", "nested/b.txt": "b"})
+}
+
+func TestSyncDownWithDeleteRemovesLocalFiles(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"a.txt": "a"})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"a.txt": "a", "stale.txt": "stale"})
+
+	result := runSync(srv, "--local", local, "--direction", "down", "--delete")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, readLocalTree(t, local), map[string]string{"a.txt": "a"})
+}
+
+func TestSyncDryRunOnlyReportsActions(t *testing.T) {
+	srv := newBucketServer(map[string]string{"remove.txt": "remove"})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"new.txt": "new"})
+
+	result := runSync(srv, "--local", local, "--delete", "--dry-run")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"remove.txt": "remove"})
+	expected := `{
+  "actions": [
+    {
+      "action": "upload",
+      "path": "new.txt",
+      "size": 3
+    },
+    {
+      "action": "delete",
+      "path": "remove.txt",
+      "size": 6
+    }
+  ],
+  "direction": "up",
+  "dryRun": true,
+  "unchanged": 0
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected planned actions on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestSyncIncludeAndExcludeFilters(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{
+		"app.dll":          "app",
+		"app.pdb":          "pdb",
+		"logs/build.log":   "log",
+		"docs/readme.dll":  "doc",
+		"docs/manual.html": "html",
+	})
+
+	result := runSync(srv, "--local", local, "--include", "*.dll", "--include", "docs/**", "--exclude", "*.html")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"app.dll": "app", "docs/readme.dll": "doc"})
+}
+
+func TestSyncChecksumDetectsChangedContent(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"same.txt": "abc", "changed.txt": "abc"})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"same.txt": "abc", "changed.txt": "xyz"})
+
+	result := runSync(srv, "--local", local, "--checksum")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"same.txt": "abc", "changed.txt": "xyz"})
+	syncResult := parseSyncResult(t, result.StdOut)
+	if len(syncResult.Actions) != 1 || syncResult.Actions[0].Path != "changed.txt" {
+		t.Errorf("Expected only changed file to be uploaded, but got: %v", result.StdOut)
+	}
+}
+
+func TestSyncWithoutChecksumComparesModificationTime(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"changed.txt": "abc", "newer.txt": "abc"})
+	srv.modified["changed.txt"] = time.Now().Add(-1 * time.Hour)
+	srv.modified["newer.txt"] = time.Now().Add(1 * time.Hour)
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"changed.txt": "xyz", "newer.txt": "xyz"})
+
+	result := runSync(srv, "--local", local)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"changed.txt": "xyz", "newer.txt": "abc"})
+	syncResult := parseSyncResult(t, result.StdOut)
+	if len(syncResult.Actions) != 1 || syncResult.Unchanged != 1 {
+		t.Errorf("Expected only the locally modified file to be uploaded, but got: %v", result.StdOut)
+	}
+}
+
+func TestSyncWithoutChecksumSkipsFilesWithoutModificationTime(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"changed.txt": "abc"})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"changed.txt": "xyz"})
+
+	result := runSync(srv, "--local", local)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"changed.txt": "abc"})
+	if !strings.Contains(result.StdErr, "Skipping changed.txt: the storage provides neither an MD5 checksum nor a modification time") {
+		t.Errorf("Expected stderr to show skipped file, but got: %v", result.StdErr)
+	}
+}
+
+func TestSyncEmptyFilesAreUnchanged(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"empty.txt": ""})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"empty.txt": ""})
+
+	result := runSync(srv, "--local", local, "--checksum")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	syncResult := parseSyncResult(t, result.StdOut)
+	if len(syncResult.Actions) != 0 || syncResult.Unchanged != 1 {
+		t.Errorf("Expected empty file to be unchanged, but got: %v", result.StdOut)
+	}
+}
+
+func TestSyncUploadsEmptyFile(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{})
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"empty.txt": ""})
+
+	result := runSync(srv, "--local", local)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"empty.txt": ""})
+}
+
+func TestSyncDownDownloadsEmptyFile(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"empty.txt": ""})
+	defer srv.Close()
+	local := filepath.Join(t.TempDir(), "local")
+
+	result := runSync(srv, "--local", local, "--direction", "down")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	assertFiles(t, readLocalTree(t, local), map[string]string{"empty.txt": ""})
+}
+
+func TestSyncDownRejectsPathsOutsideOfLocalDirectory(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{"../escape.txt": "escape", "ok.txt": "ok"})
+	defer srv.Close()
+	parent := t.TempDir()
+	local := filepath.Join(parent, "local")
+
+	result := runSync(srv, "--local", local, "--direction", "down")
+
+	if !strings.Contains(result.StdErr, "Failed to download ../escape.txt") || !strings.Contains(result.StdErr, "outside of the local directory") {
+		t.Errorf("Expected stderr to show rejected path, but got: %v", result.StdErr)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escape.txt")); err == nil {
+		t.Errorf("Expected no file to be written outside of the local directory")
+	}
+	assertFiles(t, readLocalTree(t, local), map[string]string{"ok.txt": "ok"})
+}
+
+func TestSyncFailedTransferReturnsError(t *testing.T) {
+	setJournalDirectory(t)
+	srv := newBucketServer(map[string]string{})
+	srv.failPath = "broken.txt"
+	defer srv.Close()
+	local := createLocalTree(t, map[string]string{"broken.txt": "broken", "ok.txt": "ok"})
+
+	result := runSync(srv, "--local", local)
+
+	if !strings.Contains(result.StdErr, "Failed to upload broken.txt") || !strings.Contains(result.StdErr, "Failed to sync 1 of 2 files") {
+		t.Errorf("Expected stderr to show failed upload, but got: %v", result.StdErr)
+	}
+	assertFiles(t, srv.snapshot(), map[string]string{"ok.txt": "ok"})
+}
+
+func runSync(srv *bucketServer, args ...string) test.Result {
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(SyncCommand{}).
+		Build()
+	args = append([]string{"orchestrator", "buckets", "sync", "--folder-id", "1", "--key", "2"}, args...)
+	args = append(args, "--uri", srv.URL+"/my-org/my-tenant/orchestrator_")
+	return test.RunCli(args, context)
+}
+
+func parseSyncResult(t *testing.T, stdout string) syncResult {
+	var result syncResult
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatalf("Failed to parse sync result: %v", err)
+	}
+	return result
+}
+
+func createLocalTree(t *testing.T, files map[string]string) string {
+	directory := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(directory, filepath.FromSlash(name))
+		_ = os.MkdirAll(filepath.Dir(path), 0700)
+		writeFile(path, []byte(content))
+	}
+	return directory
+}
+
+func readLocalTree(t *testing.T, directory string) map[string]string {
+	result := map[string]string{}
+	_ = filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relativePath, _ := filepath.Rel(directory, path)
+		data, _ := os.ReadFile(path)
+		result[filepath.ToSlash(relativePath)] = string(data)
+		return nil
+	})
+	return result
+}
+
+func assertFiles(t *testing.T, actual map[string]string, expected map[string]string) {
+	if fmt.Sprint(sortedKeys(actual)) != fmt.Sprint(sortedKeys(expected)) {
+		t.Errorf("Expected files %v, but got: %v", sortedKeys(expected), sortedKeys(actual))
+		return
+	}
+	for name, content := range expected {
+		if actual[name] != content {
+			t.Errorf("Expected file %s to contain %v, but got: %v", name, content, actual[name])
+		}
+	}
+}
+
+func sortedKeys(files map[string]string) []string {
+	result := []string{}
+	for name := range files {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// bucketServer simulates the orchestrator bucket api together with the blob
+// storage the read and write urls point to.
+type bucketServer struct {
+	*httptest.Server
+	mutex         sync.Mutex
+	files         map[string]string
+	modified      map[string]time.Time
+	failPath      string
+	deletedFolder string
+}
+
+func (s *bucketServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	query := r.URL.Query()
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/odata/Buckets(2)/UiPath.Server.Configuration.OData.GetFiles"):
+		s.listFiles(w, query.Get("directory"), query.Get("$skip"))
+	case strings.HasSuffix(r.URL.Path, "/odata/Buckets(2)/UiPath.Server.Configuration.OData.GetWriteUri"),
+		strings.HasSuffix(r.URL.Path, "/odata/Buckets(2)/UiPath.Server.Configuration.OData.GetReadUri"):
+		_, _ = w.Write([]byte(`{"Uri":"` + s.URL + `/blob/` + query.Get("path") + `?sig=1"}`))
+	case strings.HasSuffix(r.URL.Path, "/odata/Buckets(2)/UiPath.Server.Configuration.OData.DeleteFile"):
+		s.deletedFolder = r.Header.Get("X-UiPath-OrganizationUnitId")
+		delete(s.files, query.Get("path"))
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(r.URL.Path, "/blob/") && r.Method == http.MethodPut:
+		path := strings.TrimPrefix(r.URL.Path, "/blob/")
+		if path == s.failPath {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.files[path] = string(body)
+		s.modified[path] = time.Now()
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(r.URL.Path, "/blob/"):
+		path := strings.TrimPrefix(r.URL.Path, "/blob/")
+		if path == s.failPath {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		content, found := s.files[path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if content == "" && r.Header.Get("Range") != "" {
+			// Azure rejects range requests on empty blobs
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		hash := md5.Sum([]byte(content))
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, hash))
+		w.Header().Set("x-ms-blob-content-md5", base64.StdEncoding.EncodeToString(hash[:]))
+		http.ServeContent(w, r, "", s.modified[path], strings.NewReader(content))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *bucketServer) listFiles(w http.ResponseWriter, directory string, skip string) {
+	if skip != "0" {
+		_, _ = w.Write([]byte(`{"value":[]}`))
+		return
+	}
+	prefix := strings.TrimPrefix(directory, "/")
+	files := []blobFileResponse{}
+	for path, content := range s.files {
+		if strings.HasPrefix(path, prefix) {
+			files = append(files, blobFileResponse{FullPath: path, Size: int64(len(content))})
+		}
+	}
+	data, _ := json.Marshal(blobFilesResponse{files})
+	_, _ = w.Write(data)
+}
+
+func (s *bucketServer) snapshot() map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result := map[string]string{}
+	for path, content := range s.files {
+		result[path] = content
+	}
+	return result
+}
+
+func newBucketServer(files map[string]string) *bucketServer {
+	server := &bucketServer{files: files, modified: map[string]time.Time{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
@@ -0,0 +1,10 @@
+package orchestrator
+
+import "time"
+
+// syncFile contains the information about a local or remote file which is
+// used to decide whether it needs to be transferred.
+type syncFile struct {
+	Size     int64
+	Modified time.Time
+}
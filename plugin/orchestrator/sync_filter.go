@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"path"
+	"strings"
+)
+
+// syncFilter decides which files are synchronized based on include and
+// exclude glob patterns. Patterns without a slash are matched against the
+// file name, other patterns against the relative path. A trailing /** matches
+// everything inside of a directory.
+type syncFilter struct {
+	include []string
+	exclude []string
+}
+
+func (f syncFilter) Matches(relativePath string) bool {
+	if len(f.include) > 0 && !f.matchesAny(f.include, relativePath) {
+		return false
+	}
+	return !f.matchesAny(f.exclude, relativePath)
+}
+
+func (f syncFilter) matchesAny(patterns []string, relativePath string) bool {
+	for _, pattern := range patterns {
+		if f.match(pattern, relativePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f syncFilter) match(pattern string, relativePath string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		return strings.HasPrefix(relativePath, strings.TrimSuffix(pattern, "**"))
+	}
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relativePath))
+		return matched
+	}
+	matched, _ := path.Match(pattern, relativePath)
+	return matched
+}
+
+func newSyncFilter(include []string, exclude []string) *syncFilter {
+	return &syncFilter{include, exclude}
+}
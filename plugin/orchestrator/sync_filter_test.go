@@ -0,0 +1,35 @@
+package orchestrator
+
+import "testing"
+
+func TestSyncFilterWithoutPatternsMatchesEverything(t *testing.T) {
+	filter := newSyncFilter([]string{}, []string{})
+
+	if !filter.Matches("dir/file.txt") {
+		t.Errorf("Expected filter without patterns to match every file")
+	}
+}
+
+func TestSyncFilterMatchesFileNamePattern(t *testing.T) {
+	filter := newSyncFilter([]string{"*.dll"}, []string{})
+
+	if !filter.Matches("bin/app.dll") || filter.Matches("bin/app.pdb") {
+		t.Errorf("Expected pattern without slash to match the file name")
+	}
+}
+
+func TestSyncFilterMatchesRelativePathPattern(t *testing.T) {
+	filter := newSyncFilter([]string{"bin/*.dll"}, []string{})
+
+	if !filter.Matches("bin/app.dll") || filter.Matches("lib/bin/app.dll") {
+		t.Errorf("Expected pattern with slash to match the relative path")
+	}
+}
+
+func TestSyncFilterMatchesDirectoryPattern(t *testing.T) {
+	filter := newSyncFilter([]string{}, []string{"logs/**"})
+
+	if filter.Matches("logs/2024/build.log") || !filter.Matches("src/logs.txt") {
+		t.Errorf("Expected directory pattern to match everything inside of the directory")
+	}
+}
@@ -0,0 +1,10 @@
+package orchestrator
+
+// syncResult is written to the output once the sync completed. In dry run mode
+// it contains the actions which would have been performed.
+type syncResult struct {
+	Direction string       `json:"direction"`
+	DryRun    bool         `json:"dryRun"`
+	Actions   []syncAction `json:"actions"`
+	Unchanged int          `json:"unchanged"`
+}
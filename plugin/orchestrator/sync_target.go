@@ -0,0 +1,29 @@
+package orchestrator
+
+import (
+	"path"
+)
+
+// syncTarget identifies the bucket directory and the local directory which
+// are synchronized.
+type syncTarget struct {
+	BaseUri  string
+	FolderId int
+	BucketId int
+	Local    string
+	Remote   string
+}
+
+// LocalPath returns the path of the file in the local directory and an error
+// in case the relative path points outside of the local directory.
+func (t syncTarget) LocalPath(relativePath string) (string, error) {
+	return localPath(t.Local, relativePath)
+}
+
+func (t syncTarget) RemotePath(relativePath string) string {
+	return path.Join(t.Remote, relativePath)
+}
+
+func newSyncTarget(baseUri string, folderId int, bucketId int, local string, remote string) *syncTarget {
+	return &syncTarget{baseUri, folderId, bucketId, local, remote}
+}
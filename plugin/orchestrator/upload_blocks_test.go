@@ -0,0 +1,308 @@
+package orchestrator
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UiPath/uipathcli/test"
+)
+
+func TestUploadLargeFileInBlocks(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	path := createFile(t)
+	writeFile(path, data)
+	srv := newBlobServer()
+	defer srv.Close()
+
+	result := runBlockUpload(srv, path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 3 {
+		t.Errorf("Expected file to be uploaded in 3 blocks, but got: %v", srv.uploaded)
+	}
+	if !bytes.Equal(srv.committed, data) {
+		t.Errorf("Expected committed blob to contain file content, but got %d bytes", len(srv.committed))
+	}
+	if srv.sasQuery != "sv=1&sig=abc" {
+		t.Errorf("Expected blocks to be uploaded with sas token, but got: %v", srv.sasQuery)
+	}
+}
+
+func TestUploadLargeFileSetsContentMd5OnCommit(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	path := createFile(t)
+	writeFile(path, data)
+	srv := newBlobServer()
+	defer srv.Close()
+
+	result := runBlockUpload(srv, path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	hash := md5.Sum(data)
+	expected := base64.StdEncoding.EncodeToString(hash[:])
+	if srv.committedMd5 != expected {
+		t.Errorf("Expected block list commit to set content md5 %s, but got: %v", expected, srv.committedMd5)
+	}
+}
+
+func TestUploadLargeFileToNonAzureStorageUsesSingleRequest(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	path := createFile(t)
+	writeFile(path, data)
+	srv := newBlobServer()
+	defer srv.Close()
+
+	result := runUpload(srv.URL+"/bucket/file.txt?X-Amz-Signature=abc", path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 0 {
+		t.Errorf("Expected no blocks to be uploaded, but got: %v", srv.uploaded)
+	}
+	if !bytes.Equal(srv.committed, data) {
+		t.Errorf("Expected file to be uploaded in a single request, but got %d bytes", len(srv.committed))
+	}
+}
+
+func TestUploadInterruptedShowsResumeHint(t *testing.T) {
+	setJournalDirectory(t)
+	path := createFile(t)
+	writeFile(path, createLargeFileData(20*1024*1024))
+	srv := newBlobServer()
+	srv.failBlock = blockIdForTest(1)
+	defer srv.Close()
+
+	result := runBlockUpload(srv, path)
+
+	if !strings.Contains(result.StdErr, "Upload interrupted after 2 of 3 blocks. Run the command again with --resume to continue.") {
+		t.Errorf("Expected stderr to show resume hint, but got: %v", result.StdErr)
+	}
+	if srv.committed != nil {
+		t.Errorf("Expected block list not to be committed")
+	}
+}
+
+func TestUploadResumeOnlyUploadsMissingBlocks(t *testing.T) {
+	setJournalDirectory(t)
+	data := createLargeFileData(20 * 1024 * 1024)
+	path := createFile(t)
+	writeFile(path, data)
+	srv := newBlobServer()
+	srv.failBlock = blockIdForTest(1)
+	defer srv.Close()
+	runBlockUpload(srv, path)
+
+	srv.failBlock = ""
+	srv.uploaded = []string{}
+	result := runBlockUpload(srv, path, "--resume")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 1 || srv.uploaded[0] != blockIdForTest(1) {
+		t.Errorf("Expected only missing block to be uploaded, but got: %v", srv.uploaded)
+	}
+	if !bytes.Equal(srv.committed, data) {
+		t.Errorf("Expected committed blob to contain file content, but got %d bytes", len(srv.committed))
+	}
+}
+
+func TestUploadWithoutResumeStartsOver(t *testing.T) {
+	setJournalDirectory(t)
+	path := createFile(t)
+	writeFile(path, createLargeFileData(20*1024*1024))
+	srv := newBlobServer()
+	srv.failBlock = blockIdForTest(1)
+	defer srv.Close()
+	runBlockUpload(srv, path)
+
+	srv.failBlock = ""
+	srv.uploaded = []string{}
+	result := runBlockUpload(srv, path)
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 3 {
+		t.Errorf("Expected all blocks to be uploaded again, but got: %v", srv.uploaded)
+	}
+}
+
+func TestUploadResumeIgnoresChangedFile(t *testing.T) {
+	setJournalDirectory(t)
+	path := createFile(t)
+	writeFile(path, createLargeFileData(20*1024*1024))
+	srv := newBlobServer()
+	srv.failBlock = blockIdForTest(1)
+	defer srv.Close()
+	runBlockUpload(srv, path)
+
+	data := createLargeFileData(18 * 1024 * 1024)
+	writeFile(path, data)
+	srv.failBlock = ""
+	srv.uploaded = []string{}
+	result := runBlockUpload(srv, path, "--resume")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 3 {
+		t.Errorf("Expected all blocks of the changed file to be uploaded, but got: %v", srv.uploaded)
+	}
+	if !bytes.Equal(srv.committed, data) {
+		t.Errorf("Expected committed blob to contain changed file content, but got %d bytes", len(srv.committed))
+	}
+}
+
+func TestUploadResumeIgnoresExpiredJournal(t *testing.T) {
+	setJournalDirectory(t)
+	path := createFile(t)
+	writeFile(path, createLargeFileData(20*1024*1024))
+	srv := newBlobServer()
+	srv.failBlock = blockIdForTest(1)
+	defer srv.Close()
+	runBlockUpload(srv, path)
+	expireUploadJournals(t, 7*24*time.Hour)
+
+	srv.failBlock = ""
+	srv.uploaded = []string{}
+	result := runBlockUpload(srv, path, "--resume")
+
+	if result.Error != nil {
+		t.Errorf("Expected no error, but got: %v", result.Error)
+	}
+	if len(srv.uploaded) != 3 {
+		t.Errorf("Expected all blocks to be uploaded again, but got: %v", srv.uploaded)
+	}
+}
+
+func runBlockUpload(srv *blobServer, path string, args ...string) test.Result {
+	return runUpload(srv.URL+"/blob?sv=1&sig=abc", path, args...)
+}
+
+func runUpload(writeUrl string, path string, args ...string) test.Result {
+	config := `profiles:
+- name: default
+  organization: my-org
+  tenant: my-tenant
+`
+	context := test.NewContextBuilder().
+		WithDefinition("orchestrator", "").
+		WithConfig(config).
+		WithCommandPlugin(UploadCommand{}).
+		WithResponse(200, `{"Uri":"`+writeUrl+`"}`).
+		Build()
+	args = append([]string{"orchestrator", "buckets", "upload", "--folder-id", "1", "--key", "2", "--path", "file.txt", "--file", path}, args...)
+	return test.RunCli(args, context)
+}
+
+// blobServer simulates the block upload api of the blob storage and the
+// single request upload of other storage providers.
+type blobServer struct {
+	*httptest.Server
+	mutex        sync.Mutex
+	blocks       map[string][]byte
+	uploaded     []string
+	committed    []byte
+	committedMd5 string
+	failBlock    string
+	sasQuery     string
+}
+
+func (s *blobServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+	switch query.Get("comp") {
+	case "block":
+		blockId := query.Get("blockid")
+		if blockId == s.failBlock {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.sasQuery = "sv=" + query.Get("sv") + "&sig=" + query.Get("sig")
+		s.blocks[blockId] = body
+		s.uploaded = append(s.uploaded, blockId)
+		w.WriteHeader(http.StatusCreated)
+	case "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		_ = xml.Unmarshal(body, &blockList)
+		committed := []byte{}
+		for _, blockId := range blockList.Latest {
+			committed = append(committed, s.blocks[blockId]...)
+		}
+		s.committed = committed
+		s.committedMd5 = r.Header.Get("x-ms-blob-content-md5")
+		w.WriteHeader(http.StatusCreated)
+	case "":
+		s.committed = body
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newBlobServer() *blobServer {
+	server := &blobServer{blocks: map[string][]byte{}, uploaded: []string{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+func blockIdForTest(index int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%08d", index)))
+}
+
+func createLargeFileData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func expireUploadJournals(t *testing.T, age time.Duration) {
+	directory, _ := os.UserCacheDir()
+	paths, _ := filepath.Glob(filepath.Join(directory, "uipath", "uploads", "*.json"))
+	if len(paths) == 0 {
+		t.Fatalf("Expected upload journal to exist")
+	}
+	for _, path := range paths {
+		data, _ := os.ReadFile(path)
+		var journal uploadJournal
+		_ = json.Unmarshal(data, &journal)
+		journal.Started = time.Now().Add(-age).UnixNano()
+		data, _ = json.Marshal(journal)
+		_ = os.WriteFile(path, data, 0600)
+	}
+}
+
+func setJournalDirectory(t *testing.T) {
+	directory := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", directory)
+	t.Setenv("LocalAppData", directory)
+	t.Setenv("HOME", directory)
+}
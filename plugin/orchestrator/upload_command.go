@@ -2,14 +2,20 @@ package orchestrator
 
 import (
 	"bytes"
-	"crypto/tls"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/UiPath/uipathcli/log"
 	"github.com/UiPath/uipathcli/output"
@@ -17,9 +23,19 @@ import (
 	"github.com/UiPath/uipathcli/utils"
 )
 
+const (
+	uploadBlockSize   int64 = 8 * 1024 * 1024
+	uploadMaxBlocks   int64 = 50000
+	uploadConcurrency       = 4
+)
+
 // The UploadCommand is a custom command for the orchestrator service which makes uploading
 // files more convenient. It provides a wrapper over retrieving the write url and actually
 // performing the upload.
+//
+// Files larger than the block size are split into blocks which are uploaded in parallel
+// and committed at the end. The uploaded blocks are recorded in a local journal so that
+// an interrupted upload can be continued using the --resume flag.
 type UploadCommand struct{}
 
 func (c UploadCommand) Command() plugin.Command {
@@ -29,15 +45,278 @@ func (c UploadCommand) Command() plugin.Command {
 		WithParameter("folder-id", plugin.ParameterTypeInteger, "Folder/OrganizationUnit Id", true).
 		WithParameter("key", plugin.ParameterTypeInteger, "The Bucket Id", true).
 		WithParameter("path", plugin.ParameterTypeString, "The BlobFile full path", true).
-		WithParameter("file", plugin.ParameterTypeBinary, "The file to upload", true)
+		WithParameter("file", plugin.ParameterTypeBinary, "The file to upload", true).
+		WithParameter("resume", plugin.ParameterTypeBoolean, "Continue a previously interrupted upload", false)
 }
 
 func (c UploadCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
-	writeUrl, err := c.getWriteUrl(context, logger)
+	var writeUrl string
+	phaseContext, span := context.StartPhase("get write url")
+	err := utils.RetryWithAttempt(phaseContext.Context, phaseContext.RetryPolicy, func(attempt int) error {
+		var err error
+		writeUrl, err = c.getWriteUrl(phaseContext.WithRetryAttempt(attempt), logger)
+		return err
+	})
+	span.End(err)
+	if err != nil {
+		return err
+	}
+	phaseContext, span = context.StartPhase("upload")
+	err = c.uploadFile(phaseContext, logger, writeUrl)
+	span.End(err)
+	return err
+}
+
+func (c UploadCommand) uploadFile(context plugin.ExecutionContext, logger log.Logger, writeUrl string) error {
+	file, err := c.getInput(context)
+	if err != nil {
+		return err
+	}
+	fileStream, ok := file.(*utils.FileStream)
+	size, err := file.Size()
+	if ok && err == nil && size > uploadBlockSize && c.supportsBlocks(writeUrl) {
+		return c.uploadBlocks(context, logger, writeUrl, *fileStream, size)
+	}
+	return utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		return c.upload(context.WithRetryAttempt(attempt), logger, writeUrl)
+	})
+}
+
+// supportsBlocks returns true when the write url points to an Azure blob
+// storage which supports uploading blocks. Buckets can also be backed by other
+// storage providers like S3, MinIO or the orchestrator file system which only
+// support uploading the file in a single request.
+func (c UploadCommand) supportsBlocks(writeUrl string) bool {
+	uri, err := url.Parse(writeUrl)
+	if err != nil {
+		return false
+	}
+	query := uri.Query()
+	return strings.HasSuffix(strings.ToLower(uri.Hostname()), ".blob.core.windows.net") ||
+		(query.Get("sv") != "" && query.Get("sig") != "")
+}
+
+// uploadBlocks splits the file into blocks and uploads them in parallel. Every
+// block is retried on its own. Once all blocks are uploaded, the block list is
+// committed which makes the blob available. The MD5 checksum of the file is
+// calculated while the blocks are uploaded and stored with the blob so that
+// downloads can be verified.
+func (c UploadCommand) uploadBlocks(context plugin.ExecutionContext, logger log.Logger, writeUrl string, file utils.FileStream, size int64) error {
+	fileInfo, err := os.Stat(file.Path())
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Path(), err)
+	}
+	store, err := newJournalStore("uploads", c.journalKey(context, file))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	journal := newUploadJournal(size, fileInfo.ModTime().UnixNano(), c.blockSize(size), now)
+	if getBoolParameter("resume", context.Parameters) {
+		var existing uploadJournal
+		found, err := store.Read(&existing)
+		if err != nil {
+			return err
+		}
+		if found && existing.Matches(*journal) && !existing.Expired(now) {
+			journal = &existing
+		}
+	}
+	err = store.Write(*journal)
+	if err != nil {
+		return err
+	}
+
+	reader, err := os.Open(file.Path())
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Path(), err)
+	}
+	defer reader.Close()
+	checksum := make(chan checksumResult, 1)
+	go func() {
+		value, err := c.fileMd5(io.NewSectionReader(reader, 0, size))
+		checksum <- checksumResult{value, err}
+	}()
+
+	blockCount := int((size + journal.BlockSize - 1) / journal.BlockSize)
+	completed := journal.Completed()
+	uploaded := int64(0)
+	pending := make(chan int, blockCount)
+	for index := 0; index < blockCount; index++ {
+		if completed[index] {
+			uploaded += c.blockLength(index, journal.BlockSize, size)
+		} else {
+			pending <- index
+		}
+	}
+	close(pending)
+
+	uploadBar := utils.NewProgressBar(logger)
+	defer uploadBar.Remove()
+	progress := newChunkProgress(uploadBar, "uploading...", "completing  ", size, uploaded)
+
+	var mutex sync.Mutex
+	var uploadErr error
+	var wg sync.WaitGroup
+	for i := 0; i < uploadConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range pending {
+				mutex.Lock()
+				failed := uploadErr != nil
+				mutex.Unlock()
+				if failed {
+					return
+				}
+				offset := int64(index) * journal.BlockSize
+				length := c.blockLength(index, journal.BlockSize, size)
+				err := utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+					body := io.NewSectionReader(reader, offset, length)
+					return c.uploadBlock(context.WithRetryAttempt(attempt), logger, writeUrl, index, body, length, progress)
+				})
+				mutex.Lock()
+				if err == nil {
+					journal.Blocks = append(journal.Blocks, index)
+					err = store.Write(*journal)
+				}
+				if err != nil && uploadErr == nil {
+					uploadErr = err
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if uploadErr != nil {
+		uploadBar.Remove()
+		if len(journal.Blocks) > 0 {
+			logger.LogError(fmt.Sprintf("Upload interrupted after %d of %d blocks. Run the command again with --resume to continue.\n", len(journal.Blocks), blockCount))
+		}
+		return uploadErr
+	}
+	fileChecksum := <-checksum
+	if fileChecksum.Err != nil {
+		return fmt.Errorf("Error reading file '%s': %w", file.Path(), fileChecksum.Err)
+	}
+	err = utils.RetryWithAttempt(context.Context, context.RetryPolicy, func(attempt int) error {
+		return c.commitBlocks(context.WithRetryAttempt(attempt), logger, writeUrl, blockCount, fileChecksum.Value)
+	})
+	if err != nil {
+		return err
+	}
+	store.Remove()
+	return nil
+}
+
+func (c UploadCommand) uploadBlock(context plugin.ExecutionContext, logger log.Logger, writeUrl string, index int, body io.Reader, length int64, progress *chunkProgress) error {
+	var sent int64
+	reader := utils.NewProgressReader(body, func(p utils.Progress) {
+		progress.Add(p.BytesRead - atomic.SwapInt64(&sent, p.BytesRead))
+	})
+	uri := c.blockUri(writeUrl, "comp=block&blockid="+url.QueryEscape(c.blockId(index)))
+	request, err := http.NewRequestWithContext(context.Context, "PUT", uri, reader)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = length
+	request.Header.Add("Content-Type", "application/octet-stream")
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	err = c.sendBlockRequest(context, logger, request)
+	if err != nil {
+		progress.Add(-atomic.SwapInt64(&sent, 0))
+	}
+	return err
+}
+
+func (c UploadCommand) commitBlocks(context plugin.ExecutionContext, logger log.Logger, writeUrl string, blockCount int, md5 string) error {
+	body := `<?xml version="1.0" encoding="utf-8"?><BlockList>`
+	for index := 0; index < blockCount; index++ {
+		body += "<Latest>" + c.blockId(index) + "</Latest>"
+	}
+	body += "</BlockList>"
+	request, err := http.NewRequestWithContext(context.Context, "PUT", c.blockUri(writeUrl, "comp=blocklist"), strings.NewReader(body))
 	if err != nil {
 		return err
 	}
-	return c.upload(context, logger, writeUrl)
+	request.Header.Add("Content-Type", "application/xml")
+	request.Header.Add("x-ms-blob-content-md5", md5)
+	if context.Debug {
+		logRequest(logger, request)
+	}
+	return c.sendBlockRequest(context, logger, request)
+}
+
+func (c UploadCommand) sendBlockRequest(context plugin.ExecutionContext, logger log.Logger, request *http.Request) error {
+	response, err := sendRequest(request, context.Network, make(chan error))
+	if err != nil {
+		return fmt.Errorf("Error sending request: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return utils.Retryable(fmt.Errorf("Error reading response: %w", err))
+	}
+	logResponse(logger, response, body)
+	if response.StatusCode != http.StatusCreated {
+		return plugin.NewStatusError(context, "Orchestrator", response, body)
+	}
+	return nil
+}
+
+// fileMd5 returns the base64-encoded MD5 checksum of the file content.
+func (c UploadCommand) fileMd5(reader io.Reader) (string, error) {
+	hash := md5.New()
+	_, err := io.Copy(hash, reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// blockSize returns the size of the blocks for the given file size. The block
+// size is increased for very large files to stay within the block limit of
+// the blob storage.
+func (c UploadCommand) blockSize(size int64) int64 {
+	blockSize := uploadBlockSize
+	for (size+blockSize-1)/blockSize > uploadMaxBlocks {
+		blockSize *= 2
+	}
+	return blockSize
+}
+
+func (c UploadCommand) blockLength(index int, blockSize int64, size int64) int64 {
+	offset := int64(index) * blockSize
+	if size-offset < blockSize {
+		return size - offset
+	}
+	return blockSize
+}
+
+// blockId returns the base64-encoded id of the block. All block ids of a
+// blob need to have the same length.
+func (c UploadCommand) blockId(index int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%08d", index)))
+}
+
+func (c UploadCommand) blockUri(writeUrl string, query string) string {
+	if strings.Contains(writeUrl, "?") {
+		return writeUrl + "&" + query
+	}
+	return writeUrl + "?" + query
+}
+
+func (c UploadCommand) journalKey(context plugin.ExecutionContext, file utils.FileStream) string {
+	path, err := filepath.Abs(file.Path())
+	if err != nil {
+		path = file.Path()
+	}
+	folderId, _ := getIntParameter("folder-id", context.Parameters)
+	bucketId, _ := getIntParameter("key", context.Parameters)
+	blobPath, _ := getStringParameter("path", context.Parameters)
+	return fmt.Sprintf("%s/%s/%d/%d/%s/%s", context.Organization, context.Tenant, folderId, bucketId, blobPath, path)
 }
 
 func (c UploadCommand) upload(context plugin.ExecutionContext, logger log.Logger, url string) error {
@@ -49,9 +328,9 @@ func (c UploadCommand) upload(context plugin.ExecutionContext, logger log.Logger
 		return err
 	}
 	if context.Debug {
-		c.logRequest(logger, request)
+		logRequest(logger, request)
 	}
-	response, err := c.send(request, context.Insecure, requestError)
+	response, err := sendRequest(request, context.Network, requestError)
 	if err != nil {
 		return fmt.Errorf("Error sending request: %w", err)
 	}
@@ -60,27 +339,30 @@ func (c UploadCommand) upload(context plugin.ExecutionContext, logger log.Logger
 	if err != nil {
 		return fmt.Errorf("Error reading response: %w", err)
 	}
-	c.logResponse(logger, response, body)
-	if response.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Orchestrator returned status code '%v' and body '%v'", response.StatusCode, string(body))
+	logResponse(logger, response, body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return plugin.NewStatusError(context, "Orchestrator", response, body)
 	}
 	return nil
 }
 
+func (c UploadCommand) getInput(context plugin.ExecutionContext) (utils.Stream, error) {
+	if context.Input != nil {
+		return context.Input, nil
+	}
+	return getFileParameter("file", context.Parameters)
+}
+
 func (c UploadCommand) createUploadRequest(context plugin.ExecutionContext, url string, uploadBar *utils.ProgressBar, requestError chan error) (*http.Request, error) {
-	file := context.Input
-	if file == nil {
-		var err error
-		file, err = c.getFileParameter(context.Parameters)
-		if err != nil {
-			return nil, err
-		}
+	file, err := c.getInput(context)
+	if err != nil {
+		return nil, err
 	}
 	bodyReader, bodyWriter := io.Pipe()
 	contentType, contentLength := c.writeBody(bodyWriter, file, requestError)
 	uploadReader := c.progressReader("uploading...", "completing  ", bodyReader, contentLength, uploadBar)
 
-	request, err := http.NewRequest("PUT", url, uploadReader)
+	request, err := http.NewRequestWithContext(context.Context, "PUT", url, uploadReader)
 	if err != nil {
 		return nil, err
 	}
@@ -129,10 +411,10 @@ func (c UploadCommand) getWriteUrl(context plugin.ExecutionContext, logger log.L
 		return "", err
 	}
 	if context.Debug {
-		c.logRequest(logger, request)
+		logRequest(logger, request)
 	}
 	requestError := make(chan error)
-	response, err := c.send(request, context.Insecure, requestError)
+	response, err := sendRequest(request, context.Network, requestError)
 	if err != nil {
 		return "", fmt.Errorf("Error sending request: %w", err)
 	}
@@ -141,9 +423,9 @@ func (c UploadCommand) getWriteUrl(context plugin.ExecutionContext, logger log.L
 	if err != nil {
 		return "", fmt.Errorf("Error reading response: %w", err)
 	}
-	c.logResponse(logger, response, body)
+	logResponse(logger, response, body)
 	if response.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Orchestrator returned status code '%v' and body '%v'", response.StatusCode, string(body))
+		return "", plugin.NewStatusError(context, "Orchestrator", response, body)
 	}
 	var result urlResponse
 	err = json.Unmarshal(body, &result)
@@ -160,21 +442,21 @@ func (c UploadCommand) createWriteUrlRequest(context plugin.ExecutionContext) (*
 	if context.Tenant == "" {
 		return nil, errors.New("Tenant is not set")
 	}
-	folderId, err := c.getIntParameter("folder-id", context.Parameters)
+	folderId, err := getIntParameter("folder-id", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
-	bucketId, err := c.getIntParameter("key", context.Parameters)
+	bucketId, err := getIntParameter("key", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
-	path, err := c.getStringParameter("path", context.Parameters)
+	path, err := getStringParameter("path", context.Parameters)
 	if err != nil {
 		return nil, err
 	}
 
-	uri := c.formatUri(context.BaseUri, context.Organization, context.Tenant) + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetWriteUri?path=%s", bucketId, path)
-	request, err := http.NewRequest("GET", uri, &bytes.Buffer{})
+	uri := formatUri(context.BaseUri, context.Organization, context.Tenant) + fmt.Sprintf("/odata/Buckets(%d)/UiPath.Server.Configuration.OData.GetWriteUri?path=%s", bucketId, path)
+	request, err := http.NewRequestWithContext(context.Context, "GET", uri, &bytes.Buffer{})
 	if err != nil {
 		return nil, err
 	}
@@ -184,88 +466,3 @@ func (c UploadCommand) createWriteUrlRequest(context plugin.ExecutionContext) (*
 	}
 	return request, nil
 }
-
-func (c UploadCommand) formatUri(baseUri url.URL, org string, tenant string) string {
-	path := baseUri.Path
-	if baseUri.Path == "" {
-		path = "/{organization}/{tenant}/orchestrator_"
-	}
-	path = strings.ReplaceAll(path, "{organization}", org)
-	path = strings.ReplaceAll(path, "{tenant}", tenant)
-	path = strings.TrimSuffix(path, "/")
-	return fmt.Sprintf("%s://%s%s", baseUri.Scheme, baseUri.Host, path)
-}
-
-func (c UploadCommand) send(request *http.Request, insecure bool, errorChan chan error) (*http.Response, error) {
-	responseChan := make(chan *http.Response)
-	go func(request *http.Request) {
-		response, err := c.sendRequest(request, insecure)
-		if err != nil {
-			errorChan <- err
-			return
-		}
-		responseChan <- response
-	}(request)
-
-	select {
-	case err := <-errorChan:
-		return nil, err
-	case response := <-responseChan:
-		return response, nil
-	}
-}
-
-func (c UploadCommand) sendRequest(request *http.Request, insecure bool) (*http.Response, error) {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, //nolint // This is user configurable and disabled by default
-	}
-	client := &http.Client{Transport: transport}
-	return client.Do(request)
-}
-
-func (c UploadCommand) getStringParameter(name string, parameters []plugin.ExecutionParameter) (string, error) {
-	for _, p := range parameters {
-		if p.Name == name {
-			if data, ok := p.Value.(string); ok {
-				return data, nil
-			}
-		}
-	}
-	return "", fmt.Errorf("Could not find '%s' parameter", name)
-}
-
-func (c UploadCommand) getIntParameter(name string, parameters []plugin.ExecutionParameter) (int, error) {
-	for _, p := range parameters {
-		if p.Name == name {
-			if data, ok := p.Value.(int); ok {
-				return data, nil
-			}
-		}
-	}
-	return 0, fmt.Errorf("Could not find '%s' parameter", name)
-}
-
-func (c UploadCommand) getFileParameter(parameters []plugin.ExecutionParameter) (utils.Stream, error) {
-	for _, p := range parameters {
-		if p.Name == "file" {
-			if stream, ok := p.Value.(utils.Stream); ok {
-				return stream, nil
-			}
-		}
-	}
-	return nil, fmt.Errorf("Could not find 'file' parameter")
-}
-
-func (c UploadCommand) logRequest(logger log.Logger, request *http.Request) {
-	buffer := &bytes.Buffer{}
-	_, _ = buffer.ReadFrom(request.Body)
-	body := buffer.Bytes()
-	request.Body = io.NopCloser(bytes.NewReader(body))
-	requestInfo := log.NewRequestInfo(request.Method, request.URL.String(), request.Proto, request.Header, bytes.NewReader(body))
-	logger.LogRequest(*requestInfo)
-}
-
-func (c UploadCommand) logResponse(logger log.Logger, response *http.Response, body []byte) {
-	responseInfo := log.NewResponseInfo(response.StatusCode, response.Status, response.Proto, response.Header, bytes.NewReader(body))
-	logger.LogResponse(*responseInfo)
-}
@@ -0,0 +1,42 @@
+package orchestrator
+
+import "time"
+
+// Azure discards uncommitted blocks after a week, journals older than that
+// cannot be resumed anymore.
+const uploadJournalMaxAge = 6 * 24 * time.Hour
+
+// uploadJournal records the blocks of a file which have already been uploaded
+// so that an interrupted upload can be continued. The size and modification
+// time of the file are stored to detect whether the file changed in between.
+// The time the upload started is stored to detect whether the uploaded blocks
+// are still available.
+type uploadJournal struct {
+	Size      int64 `json:"size"`
+	Modified  int64 `json:"modified"`
+	BlockSize int64 `json:"blockSize"`
+	Started   int64 `json:"started"`
+	Blocks    []int `json:"blocks"`
+}
+
+func (j uploadJournal) Matches(other uploadJournal) bool {
+	return j.Size == other.Size && j.Modified == other.Modified && j.BlockSize == other.BlockSize
+}
+
+// Expired returns true when the uploaded blocks may have been discarded by
+// the storage already.
+func (j uploadJournal) Expired(now time.Time) bool {
+	return now.Sub(time.Unix(0, j.Started)) > uploadJournalMaxAge
+}
+
+func (j uploadJournal) Completed() map[int]bool {
+	result := map[int]bool{}
+	for _, block := range j.Blocks {
+		result[block] = true
+	}
+	return result
+}
+
+func newUploadJournal(size int64, modified int64, blockSize int64, started time.Time) *uploadJournal {
+	return &uploadJournal{size, modified, blockSize, started.UnixNano(), []int{}}
+}
@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/UiPath/uipathcli/utils"
+)
+
+// NewStatusError creates the error for an unsuccessful response of the given
+// service. The error is retryable when the retry policy of the execution
+// context allows retrying the status code. The Retry-After header of the
+// response determines the delay before the next attempt.
+func NewStatusError(context ExecutionContext, service string, response *http.Response, body []byte) error {
+	err := utils.NewHttpStatusError(response.StatusCode, fmt.Errorf("%s returned status code '%v' and body '%v'", service, response.StatusCode, string(body)))
+	if context.RetryPolicy.IsRetryableStatusCode(response.StatusCode) {
+		retryAfter := utils.ParseRetryAfter(response.Header.Get("Retry-After"))
+		return utils.RetryableAfter(err, retryAfter)
+	}
+	return err
+}
@@ -0,0 +1,307 @@
+package test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+)
+
+const batchDefinition = `
+paths:
+  /users/{id}:
+    get:
+      operationId: get-user
+      tags:
+        - users
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+`
+
+func TestBatchExecutesAllOperations(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		WithUrlResponse("/users/1", 200, `{"id":1}`).
+		WithUrlResponse("/users/2", 200, `{"id":2}`).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "users get-user", "parameters": {"id": 1}}
+
+{"service": "myservice", "operation": "users get-user", "parameters": {"id": 2}}
+`))
+	result := RunCli([]string{"batch", "--file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	records := parseBatchOutput(t, result.StdOut)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 result records, but got: %v", result.StdOut)
+	}
+	for i, record := range records {
+		if record["line"] != float64(i*2+1) || record["status"] != "succeeded" || record["exitCode"] != 0.0 {
+			t.Errorf("Expected succeeded record for line %d, but got: %v", i*2+1, record)
+		}
+		body := record["body"].(map[string]interface{})
+		if body["id"] != float64(i+1) {
+			t.Errorf("Expected response body with id %d, but got: %v", i+1, body)
+		}
+	}
+	expected := "Batch completed: 2 operations, 2 succeeded, 0 failed\n"
+	if result.StdErr != expected {
+		t.Errorf("Expected summary %v, but got: %v", expected, result.StdErr)
+	}
+}
+
+func TestBatchPreservesLargeIntegerIds(t *testing.T) {
+	definition := `
+paths:
+  /users/{id}:
+    post:
+      operationId: update-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                managerId:
+                  type: integer
+                tags:
+                  type: object
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithUrlResponse("/users/9007199254740993", 200, `{}`).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "update-user", "parameters": {"id": 9007199254740993, "manager-id": 9007199254740995, "tags": {"ref": 9007199254740997}}}`))
+	result := RunCli([]string{"batch", "--file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `{"managerId":9007199254740995,"tags":{"ref":9007199254740997}}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected request body %v, but got: %v", expected, result.RequestBody)
+	}
+}
+
+func TestBatchFailedOperationReturnsExitCodeOfFirstFailure(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		WithUrlResponse("/users/1", 200, `{"id":1}`).
+		WithUrlResponse("/users/2", 404, `{"message":"not found"}`).
+		WithUrlResponse("/users/3", 500, `{"message":"error"}`).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "users get-user", "parameters": {"id": 1}}
+{"service": "myservice", "operation": "users get-user", "parameters": {"id": 2}}
+{"service": "myservice", "operation": "users get-user", "parameters": {"id": 3}}
+`))
+	result := RunCli([]string{"batch", "--file", path, "--concurrency", "1"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code, but got: %v", result.Error)
+	}
+	records := parseBatchOutput(t, result.StdOut)
+	if records[1]["status"] != "failed" || records[1]["exitCode"] != float64(commandline.ExitCodeClientError) {
+		t.Errorf("Expected failed record for line 2, but got: %v", records[1])
+	}
+	body := records[1]["body"].(map[string]interface{})
+	if body["message"] != "not found" {
+		t.Errorf("Expected error response body, but got: %v", body)
+	}
+	if !strings.Contains(result.StdErr, "Batch completed: 3 operations, 1 succeeded, 2 failed") {
+		t.Errorf("Expected summary on stderr, but got: %v", result.StdErr)
+	}
+}
+
+func TestBatchUnknownOperationFails(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "users unknown"}
+{"service": "unknown", "operation": "users get-user"}
+not-json
+`))
+	result := RunCli([]string{"batch", "--file", path}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", result.Error)
+	}
+	records := parseBatchOutput(t, result.StdOut)
+	if records[0]["error"] != "Unknown operation 'users unknown' for service 'myservice'" {
+		t.Errorf("Expected unknown operation error, but got: %v", records[0]["error"])
+	}
+	if records[1]["error"] != "Unknown service 'unknown'" {
+		t.Errorf("Expected unknown service error, but got: %v", records[1]["error"])
+	}
+	if !strings.HasPrefix(records[2]["error"].(string), "Invalid batch operation") {
+		t.Errorf("Expected invalid batch operation error, but got: %v", records[2]["error"])
+	}
+}
+
+func TestBatchReadsYamlFile(t *testing.T) {
+	definition := `
+paths:
+  /users:
+    post:
+      operationId: create-user
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+                roles:
+                  type: array
+                  items:
+                    type: string
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(201, `{}`).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "ops.yaml")
+	writeFile(t, path, []byte(`
+- service: myservice
+  operation: create-user
+  parameters:
+    name: John
+    roles:
+      - admin
+      - user
+`))
+	result := RunCli([]string{"batch", "--file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `{"name":"John","roles":["admin","user"]}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected request body %v, but got: %v", expected, result.RequestBody)
+	}
+}
+
+func TestBatchForwardsGlobalArguments(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		WithResponse(200, `{"id":1,"name":"John"}`).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "users get-user", "parameters": {"id": 1}}`))
+	result := RunCli([]string{"batch", "--file", path, "--query", "name"}, context)
+
+	records := parseBatchOutput(t, result.StdOut)
+	if records[0]["body"] != "John" {
+		t.Errorf("Expected query to be applied, but got: %v", records[0]["body"])
+	}
+}
+
+func TestBatchConcurrency(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		WithResponse(200, `{}`).
+		Build()
+
+	lines := []string{}
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, `{"service": "myservice", "operation": "users get-user", "parameters": {"id": 1}}`)
+	}
+	path := createFile(t)
+	writeFile(t, path, []byte(strings.Join(lines, "\n")))
+	result := RunCli([]string{"batch", "--file", path, "--concurrency", "5"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	records := parseBatchOutput(t, result.StdOut)
+	numbers := []int{}
+	for _, record := range records {
+		numbers = append(numbers, int(record["line"].(float64)))
+	}
+	sort.Ints(numbers)
+	if len(numbers) != 10 || numbers[0] != 1 || numbers[9] != 10 {
+		t.Errorf("Expected result for every line, but got: %v", numbers)
+	}
+}
+
+func TestBatchInvalidConcurrency(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		Build()
+
+	path := createFile(t)
+	result := RunCli([]string{"batch", "--file", path, "--concurrency", "0"}, context)
+
+	expected := "Invalid value for concurrency: '0', must be at least 1"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected invalid concurrency error, but got: %v", result.Error)
+	}
+}
+
+func TestBatchPassesFalseBooleanSwitch(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", "").
+		WithCommandPlugin(SwitchPluginCommand{}).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"service": "myservice", "operation": "my-switch-command", "parameters": {"delete": false, "name": "a"}}
+{"service": "myservice", "operation": "my-switch-command", "parameters": {"delete": true, "name": "b"}}
+`))
+	result := RunCli([]string{"batch", "--file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	records := parseBatchOutput(t, result.StdOut)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 result records, but got: %v", result.StdOut)
+	}
+	first := records[0]["body"].(map[string]interface{})
+	if first["delete"] != false || first["name"] != "a" {
+		t.Errorf("Expected switch to be disabled, but got: %v", records[0])
+	}
+	second := records[1]["body"].(map[string]interface{})
+	if second["delete"] != true || second["name"] != "b" {
+		t.Errorf("Expected switch to be enabled, but got: %v", records[1])
+	}
+}
+
+func parseBatchOutput(t *testing.T, output string) []map[string]interface{} {
+	records := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		record := map[string]interface{}{}
+		err := json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			t.Fatalf("Failed to parse batch output: %v, output: %v", err, output)
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i]["line"].(float64) < records[j]["line"].(float64)
+	})
+	return records
+}
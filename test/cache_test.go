@@ -0,0 +1,269 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const cacheDefinition = `
+paths:
+  /users:
+    get:
+      operationId: get-users
+`
+
+func TestCacheRevalidatesResponseWithETag(t *testing.T) {
+	setCacheDirectory(t)
+	var requests int32
+	ifNoneMatch := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		if ifNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"my-user"}`))
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	first := RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	second := RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+
+	if second.Error != nil {
+		t.Errorf("Unexpected error, got: %v", second.Error)
+	}
+	if ifNoneMatch != `"v1"` {
+		t.Errorf("Expected If-None-Match header with cached ETag, but got: %v", ifNoneMatch)
+	}
+	if requests != 2 {
+		t.Errorf("Expected response to be revalidated, but got %d requests", requests)
+	}
+	if first.StdOut == "" || second.StdOut != first.StdOut {
+		t.Errorf("Expected cached response %v, but got: %v", first.StdOut, second.StdOut)
+	}
+}
+
+func TestCacheRevalidatesResponseWithLastModified(t *testing.T) {
+	setCacheDirectory(t)
+	ifModifiedSince := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifModifiedSince = r.Header.Get("If-Modified-Since")
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+
+	if ifModifiedSince != "Wed, 21 Oct 2015 07:28:00 GMT" {
+		t.Errorf("Expected If-Modified-Since header, but got: %v", ifModifiedSince)
+	}
+}
+
+func TestCacheServesFreshResponseWithoutRequest(t *testing.T) {
+	setCacheDirectory(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"my-user"}`))
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	result := RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+
+	if requests != 1 {
+		t.Errorf("Expected fresh response to be served from cache, but got %d requests", requests)
+	}
+	if !strings.Contains(result.StdOut, "my-user") {
+		t.Errorf("Expected cached response, but got: %v", result.StdOut)
+	}
+}
+
+func TestCacheDoesNotStoreNoStoreResponse(t *testing.T) {
+	setCacheDirectory(t)
+	ifNoneMatch := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+
+	if ifNoneMatch != "" {
+		t.Errorf("Expected no-store response not to be cached, but got: %v", ifNoneMatch)
+	}
+}
+
+func TestCacheIsDisabledByDefault(t *testing.T) {
+	setCacheDirectory(t)
+	ifNoneMatch := ""
+	server := newETagServer(&ifNoneMatch)
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--uri", server.URL}, context)
+	RunCli([]string{"myservice", "get-users", "--uri", server.URL}, context)
+
+	if ifNoneMatch != "" {
+		t.Errorf("Expected cache to be disabled, but got: %v", ifNoneMatch)
+	}
+}
+
+func TestNoCacheOverridesProfileConfig(t *testing.T) {
+	setCacheDirectory(t)
+	config := `
+profiles:
+  - name: default
+    cache: true
+`
+	ifNoneMatch := ""
+	server := newETagServer(&ifNoneMatch)
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		WithConfig(config).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--uri", server.URL}, context)
+	RunCli([]string{"myservice", "get-users", "--no-cache", "--uri", server.URL}, context)
+	if ifNoneMatch != "" {
+		t.Errorf("Expected --no-cache to bypass cache, but got: %v", ifNoneMatch)
+	}
+
+	RunCli([]string{"myservice", "get-users", "--uri", server.URL}, context)
+	if ifNoneMatch != `"v1"` {
+		t.Errorf("Expected profile to enable cache, but got: %v", ifNoneMatch)
+	}
+}
+
+func TestCacheIsScopedToProfile(t *testing.T) {
+	setCacheDirectory(t)
+	config := `
+profiles:
+  - name: default
+  - name: other
+`
+	ifNoneMatch := ""
+	server := newETagServer(&ifNoneMatch)
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		WithConfig(config).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	RunCli([]string{"myservice", "get-users", "--cache", "--profile", "other", "--uri", server.URL}, context)
+
+	if ifNoneMatch != "" {
+		t.Errorf("Expected cached response not to be shared between profiles, but got: %v", ifNoneMatch)
+	}
+}
+
+func TestCacheIsScopedToHeaderParameters(t *testing.T) {
+	setCacheDirectory(t)
+	definition := `
+paths:
+  /users:
+    get:
+      operationId: get-users
+      parameters:
+      - name: X-UIPATH-OrganizationUnitId
+        in: header
+        required: true
+        schema:
+          type: integer
+`
+	var requests int32
+	folders := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		folders = append(folders, r.Header.Get("X-UIPATH-OrganizationUnitId"))
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"folder":"` + r.Header.Get("X-UIPATH-OrganizationUnitId") + `"}`))
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--x-uipath-organization-unit-id", "1", "--cache", "--uri", server.URL}, context)
+	result := RunCli([]string{"myservice", "get-users", "--x-uipath-organization-unit-id", "2", "--cache", "--uri", server.URL}, context)
+
+	if requests != 2 {
+		t.Errorf("Expected cached response not to be shared between header values, but got %d requests for %v", requests, folders)
+	}
+	if !strings.Contains(result.StdOut, `"folder": "2"`) {
+		t.Errorf("Expected response for second folder, but got: %v", result.StdOut)
+	}
+}
+
+func TestCacheInfoAndClearCommands(t *testing.T) {
+	setCacheDirectory(t)
+	ifNoneMatch := ""
+	server := newETagServer(&ifNoneMatch)
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", cacheDefinition).
+		Build()
+
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+
+	info := RunCli([]string{"cache", "info"}, context)
+	if !strings.Contains(info.StdOut, `"entries": 1`) {
+		t.Errorf("Expected one cached response, but got: %v", info.StdOut)
+	}
+	clear := RunCli([]string{"cache", "clear"}, context)
+	if clear.StdOut != "Removed 1 cached responses\n" {
+		t.Errorf("Expected cached response to be removed, but got: %v", clear.StdOut)
+	}
+	RunCli([]string{"myservice", "get-users", "--cache", "--uri", server.URL}, context)
+	if ifNoneMatch != "" {
+		t.Errorf("Expected no cached response after clear, but got: %v", ifNoneMatch)
+	}
+}
+
+func newETagServer(ifNoneMatch *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ifNoneMatch = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+}
+
+func setCacheDirectory(t *testing.T) {
+	directory := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", directory)
+	t.Setenv("LocalAppData", directory)
+	t.Setenv("HOME", directory)
+}
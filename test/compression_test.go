@@ -0,0 +1,212 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+const compressionDefinition = `
+paths:
+  /queues:
+    get:
+      operationId: get-queues
+    post:
+      operationId: create-queue
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+`
+
+func TestResponseGzipIsDecompressed(t *testing.T) {
+	acceptEncoding := ""
+	server := newCompressingServer(&acceptEncoding, `{"name":"my-queue"}`)
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-queues", "--uri", server.URL, "--debug"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if acceptEncoding != "gzip, deflate, br" {
+		t.Errorf("Expected Accept-Encoding request header, but got: %v", acceptEncoding)
+	}
+	expected := `{
+  "name": "my-queue"
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected decompressed response %v, but got: %v", expected, result.StdOut)
+	}
+	if !strings.Contains(result.StdErr, `{"name":"my-queue"}`) || strings.Contains(result.StdErr, "Content-Encoding") {
+		t.Errorf("Expected decompressed response in debug output, but got: %v", result.StdErr)
+	}
+}
+
+func TestResponseDeflateIsDecompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		writer := zlib.NewWriter(&body)
+		_, _ = writer.Write([]byte(`{"name":"my-queue"}`))
+		writer.Close()
+		w.Header().Set("Content-Encoding", "deflate")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-queues", "--uri", server.URL, "--query", "name"}, context)
+
+	if result.StdOut != "\"my-queue\"\n" {
+		t.Errorf("Expected decompressed response, but got: %v", result.StdOut)
+	}
+}
+
+func TestResponseBrotliIsDecompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		writer := brotli.NewWriter(&body)
+		_, _ = writer.Write([]byte(`{"name":"my-queue"}`))
+		writer.Close()
+		w.Header().Set("Content-Encoding", "br")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-queues", "--uri", server.URL, "--query", "name"}, context)
+
+	if result.StdOut != "\"my-queue\"\n" {
+		t.Errorf("Expected decompressed response, but got: %v", result.StdOut)
+	}
+}
+
+func TestResponseGzipIsDecompressedWhenStreamedToFile(t *testing.T) {
+	acceptEncoding := ""
+	server := newCompressingServer(&acceptEncoding, "my-file-content")
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "queues.json")
+	result := RunCli([]string{"myservice", "get-queues", "--uri", server.URL, "--out-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "my-file-content" {
+		t.Errorf("Expected decompressed file content, but got: %v", string(data))
+	}
+}
+
+func TestCompressRequestGzipsJsonBody(t *testing.T) {
+	contentEncoding := ""
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncoding = r.Header.Get("Content-Encoding")
+		body = readGzipBody(t, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "create-queue", "--name", "my-queue", "--compress-request", "--uri", server.URL}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if contentEncoding != "gzip" {
+		t.Errorf("Expected Content-Encoding gzip, but got: %v", contentEncoding)
+	}
+	if body != `{"name":"my-queue"}` {
+		t.Errorf("Expected compressed json body, but got: %v", body)
+	}
+}
+
+func TestCompressRequestGzipsFileBody(t *testing.T) {
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = readGzipBody(t, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"name":"from-file"}`))
+	result := RunCli([]string{"myservice", "create-queue", "--file", path, "--compress-request", "--uri", server.URL}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if body != `{"name":"from-file"}` {
+		t.Errorf("Expected compressed file body, but got: %v", body)
+	}
+}
+
+func TestRequestIsNotCompressedByDefault(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", compressionDefinition).
+		WithResponse(200, "").
+		Build()
+
+	result := RunCli([]string{"myservice", "create-queue", "--name", "my-queue"}, context)
+
+	if result.RequestHeader["content-encoding"] != "" {
+		t.Errorf("Expected no Content-Encoding header, but got: %v", result.RequestHeader["content-encoding"])
+	}
+	if result.RequestBody != `{"name":"my-queue"}` {
+		t.Errorf("Expected uncompressed request body, but got: %v", result.RequestBody)
+	}
+}
+
+func newCompressingServer(acceptEncoding *string, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*acceptEncoding = r.Header.Get("Accept-Encoding")
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		_, _ = writer.Write([]byte(body))
+		writer.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(compressed.Bytes())
+	}))
+}
+
+func readGzipBody(t *testing.T, body io.Reader) string {
+	reader, err := gzip.NewReader(body)
+	if err != nil {
+		t.Errorf("Expected gzip request body, but got: %v", err)
+		return ""
+	}
+	data, _ := io.ReadAll(reader)
+	return string(data)
+}
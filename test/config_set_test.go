@@ -187,6 +187,90 @@ func TestConfigSetDebug(t *testing.T) {
 	}
 }
 
+func TestConfigSetFail(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "fail", "--value", "true"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  fail: true
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetTimeout(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "timeout", "--value", "5m"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  timeout: 5m0s
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetProxy(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "proxy", "--value", "http://proxy.mycompany.com:8080"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  proxy: http://proxy.mycompany.com:8080
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetClientCertificate(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "clientCert", "--value", "/certs/client.pem"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  clientCert: /certs/client.pem
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
 func TestConfigInvalidInsecure(t *testing.T) {
 	context := NewContextBuilder().
 		Build()
@@ -340,3 +424,104 @@ func TestConfigSetVersion(t *testing.T) {
 		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
 	}
 }
+
+func TestConfigSetRetryMaxRetries(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "retry.maxRetries", "--value", "5"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  retry:
+    maxRetries: 5
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetRetryBackoff(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "retry.backoff", "--value", "500ms"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  retry:
+    backoff: 500ms
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetRetryStatusCodes(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "retry.statusCodes", "--value", "429,503"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  retry:
+    statusCodes:
+    - 429
+    - 503
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigInvalidRetryBackoff(t *testing.T) {
+	context := NewContextBuilder().
+		Build()
+
+	result := RunCli([]string{"config", "set", "--key", "retry.backoff", "--value", "invalid"}, context)
+
+	if !strings.HasPrefix(result.StdErr, "Invalid value for 'retry.backoff'") {
+		t.Errorf("Expected invalid retry backoff error, but got %v", result.StdErr)
+	}
+}
+
+func TestConfigSetConnectionMaxIdleConnsPerHost(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "connection.maxIdleConnsPerHost", "--value", "20"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  connection:
+    maxIdleConnsPerHost: 20
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
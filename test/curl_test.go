@@ -0,0 +1,161 @@
+package test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCurlOutputDoesNotSendRequest(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    delete:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, "").
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--output", "curl"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.RequestUrl != "" {
+		t.Errorf("Request should not be sent, but got: %v", result.RequestUrl)
+	}
+	if !strings.HasPrefix(result.StdOut, "curl -X DELETE 'http://127.0.0.1:") {
+		t.Errorf("Expected curl command, but got: %v", result.StdOut)
+	}
+}
+
+func TestCurlOutputWithJsonBody(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    organization: my-org
+    tenant: my-tenant
+`
+	definition := `
+paths:
+  "{organization}/{tenant}/users":
+    post:
+      operationId: create-user
+      parameters:
+      - name: x-uipath-folder
+        in: header
+        schema:
+          type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "create-user", "--x-uipath-folder", "my-folder", "--name", "O'Brien", "--as-curl"}, context)
+
+	expected := `curl -X POST 'https://cloud.uipath.com/my-org/my-tenant/users' \
+  -H 'Content-Type: application/json' \`
+	if !strings.HasPrefix(result.StdOut, expected) {
+		t.Errorf("Expected curl command to start with %v, but got: %v", expected, result.StdOut)
+	}
+	expected = `  -H 'X-Uipath-Folder: my-folder' \`
+	if !strings.Contains(result.StdOut, expected) {
+		t.Errorf("Expected header parameter %v, but got: %v", expected, result.StdOut)
+	}
+	expected = `  -H "Authorization: Bearer $UIPATH_ACCESS_TOKEN" \
+  --data-raw '{"name":"O'\''Brien"}'
+`
+	if !strings.HasSuffix(result.StdOut, expected) {
+		t.Errorf("Expected curl command to end with %v, but got: %v", expected, result.StdOut)
+	}
+}
+
+func TestCurlOutputWithMultipartFile(t *testing.T) {
+	definition := `
+paths:
+  /upload:
+    post:
+      operationId: upload
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              properties:
+                file:
+                  type: string
+                  format: binary
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte("hello-world"))
+	result := RunCli([]string{"myservice", "upload", "--file", path, "--output", "curl"}, context)
+
+	expected := "-F 'file=@" + path + "'"
+	if !strings.Contains(result.StdOut, expected) {
+		t.Errorf("Expected multipart file reference %v, but got: %v", expected, result.StdOut)
+	}
+	if strings.Contains(result.StdOut, "Content-Type") {
+		t.Errorf("Multipart content type should be set by curl, but got: %v", result.StdOut)
+	}
+}
+
+func TestCurlOutputRedactsSensitiveHeaders(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    header:
+      x-uipath-license: my-license-key
+`
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--as-curl"}, context)
+
+	if strings.Contains(result.StdOut, "my-license-key") {
+		t.Errorf("Expected license header to be redacted, but got: %v", result.StdOut)
+	}
+	expected := "-H 'X-Uipath-License: ***'"
+	if !strings.Contains(result.StdOut, expected) {
+		t.Errorf("Expected redacted license header %v, but got: %v", expected, result.StdOut)
+	}
+}
+
+func TestCurlOutputNotSupportedForPluginCommand(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: my-plugin-command
+`
+	context := NewContextBuilder().
+		WithDefinition("mypluginservice", definition).
+		WithCommandPlugin(SimplePluginCommand{}).
+		Build()
+
+	result := RunCli([]string{"mypluginservice", "my-plugin-command", "--as-curl"}, context)
+
+	expected := "Curl output is not supported for command 'my-plugin-command'"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected curl not supported error, but got: %v", result.Error)
+	}
+}
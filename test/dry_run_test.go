@@ -0,0 +1,269 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDryRunDoesNotSendRequest(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    delete:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, "").
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--dry-run"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.RequestUrl != "" {
+		t.Errorf("Request should not be sent, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestDryRunPrintsResolvedUrl(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    organization: my-org
+    tenant: my-tenant
+`
+	definition := `
+paths:
+  "{organization}/{tenant}/users/{id}":
+    get:
+      operationId: get-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: filter
+        in: query
+        schema:
+          type: string
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--id", "5", "--filter", "name eq 'x'", "--dry-run"}, context)
+
+	request := parseDryRunOutput(t, result.StdOut)
+	if request["method"] != "GET" {
+		t.Errorf("Expected GET method, but got: %v", request["method"])
+	}
+	url := request["url"].(string)
+	expected := "/my-org/my-tenant/users/5?filter=name+eq+%27x%27"
+	if !strings.HasSuffix(url, expected) {
+		t.Errorf("Expected url to end with %v, but got: %v", expected, url)
+	}
+}
+
+func TestDryRunPrintsJsonBody(t *testing.T) {
+	definition := `
+paths:
+  /users:
+    post:
+      operationId: create-user
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "create-user", "--name", "John", "--age", "32", "--dry-run"}, context)
+
+	request := parseDryRunOutput(t, result.StdOut)
+	body := request["body"].(map[string]interface{})
+	if body["name"] != "John" || body["age"] != 32.0 {
+		t.Errorf("Expected json body, but got: %v", body)
+	}
+	header := request["header"].(map[string]interface{})
+	if header["Content-Type"] != "application/json" {
+		t.Errorf("Expected json content type, but got: %v", header["Content-Type"])
+	}
+}
+
+func TestDryRunPrintsFormBody(t *testing.T) {
+	definition := `
+paths:
+  /validate:
+    post:
+      operationId: validate
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              properties:
+                client_id:
+                  type: string
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "validate", "--client-id", "my-client-id", "--dry-run"}, context)
+
+	request := parseDryRunOutput(t, result.StdOut)
+	if request["body"] != "client_id=my-client-id" {
+		t.Errorf("Expected url encoded body, but got: %v", request["body"])
+	}
+}
+
+func TestDryRunPrintsMultipartBody(t *testing.T) {
+	definition := `
+paths:
+  /upload:
+    post:
+      operationId: upload
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              properties:
+                file:
+                  type: string
+                  format: binary
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte("hello-world"))
+	result := RunCli([]string{"myservice", "upload", "--file", path, "--dry-run"}, context)
+
+	request := parseDryRunOutput(t, result.StdOut)
+	body := request["body"].(string)
+	if !strings.Contains(body, `name="file"`) || !strings.Contains(body, "hello-world") {
+		t.Errorf("Expected multipart body, but got: %v", body)
+	}
+}
+
+func TestDryRunRedactsSensitiveHeaders(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    header:
+      x-uipath-license: my-license-key
+      x-uipath-test: abc
+`
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--dry-run"}, context)
+
+	request := parseDryRunOutput(t, result.StdOut)
+	header := request["header"].(map[string]interface{})
+	if header["X-Uipath-License"] != "***" {
+		t.Errorf("Expected license header to be redacted, but got: %v", header["X-Uipath-License"])
+	}
+	if header["X-Uipath-Test"] != "abc" {
+		t.Errorf("Expected test header to be printed, but got: %v", header["X-Uipath-Test"])
+	}
+}
+
+func TestDryRunSkipsAuthentication(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    auth:
+      clientId: my-client-id
+      clientSecret: dry-run-client-secret
+`
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		WithIdentityResponse(400, `{"error": "invalid_client"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--dry-run"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Expected authentication to be skipped, but got: %v", result.Error)
+	}
+	request := parseDryRunOutput(t, result.StdOut)
+	header := request["header"].(map[string]interface{})
+	if header["Authorization"] != nil {
+		t.Errorf("Expected no authorization header, but got: %v", header["Authorization"])
+	}
+}
+
+func TestDryRunTextOutput(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    delete:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--dry-run", "--output", "text", "--query", "method"}, context)
+
+	if result.StdOut != "DELETE\n" {
+		t.Errorf("Expected method in text output, but got: %v", result.StdOut)
+	}
+}
+
+func TestDryRunNotSupportedForPluginCommand(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: my-plugin-command
+`
+	context := NewContextBuilder().
+		WithDefinition("mypluginservice", definition).
+		WithCommandPlugin(SimplePluginCommand{}).
+		Build()
+
+	result := RunCli([]string{"mypluginservice", "my-plugin-command", "--dry-run"}, context)
+
+	expected := "Dry run is not supported for command 'my-plugin-command'"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected dry run not supported error, but got: %v", result.Error)
+	}
+}
+
+func parseDryRunOutput(t *testing.T, output string) map[string]interface{} {
+	result := map[string]interface{}{}
+	err := json.Unmarshal([]byte(output), &result)
+	if err != nil {
+		t.Fatalf("Failed to parse dry run output: %v, output: %v", err, output)
+	}
+	return result
+}
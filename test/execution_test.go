@@ -76,29 +76,33 @@ paths:
 	if !strings.HasPrefix(stdErr[0], expected) {
 		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[0])
 	}
-	expected = "X-Request-Id:"
-	if !strings.HasPrefix(stdErr[1], expected) {
+	expected = "Accept-Encoding: gzip, deflate, br"
+	if stdErr[1] != expected {
 		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[1])
 	}
+	expected = "X-Request-Id:"
+	if !strings.HasPrefix(stdErr[2], expected) {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[2])
+	}
 	expected = "HTTP/1.1 200 OK"
-	if stdErr[4] != expected {
-		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[4])
+	if stdErr[5] != expected {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[5])
 	}
 	expected = "Content-Length:"
-	if !strings.HasPrefix(stdErr[5], expected) {
-		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[5])
+	if !strings.HasPrefix(stdErr[6], expected) {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[6])
 	}
 	expected = "Content-Type: text/plain; charset=utf-8"
-	if stdErr[6] != expected {
-		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[6])
+	if stdErr[7] != expected {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[7])
 	}
 	expected = "Date:"
-	if !strings.HasPrefix(stdErr[7], expected) {
-		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[7])
+	if !strings.HasPrefix(stdErr[8], expected) {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[8])
 	}
 	expected = `{"hello":"world"}`
-	if stdErr[9] != expected {
-		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[9])
+	if stdErr[10] != expected {
+		t.Errorf("Expected on stderr %v, got: %v", expected, stdErr[10])
 	}
 }
 
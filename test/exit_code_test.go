@@ -0,0 +1,200 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+)
+
+const exitCodeDefinition = `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      parameters:
+      - name: id
+        in: query
+        required: true
+        schema:
+          type: string
+`
+
+func TestNotFoundWithoutFailReturnsSuccess(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithResponse(404, `{"message":"Not found"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeSuccess {
+		t.Errorf("Expected exit code 0, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestNotFoundWithFailReturnsClientErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithResponse(404, `{"message":"Not found"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--fail"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+	expected := `{
+  "message": "Not found"
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected response body on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestUnauthorizedWithFailReturnsAuthErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithResponse(401, `{"message":"Unauthorized"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--fail"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeAuthError {
+		t.Errorf("Expected auth error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestFailFromProfileCanBeDisabledWithNoFail(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    fail: true
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithConfig(config).
+		WithResponse(404, `{"message":"Not found"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1"}, context)
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code from profile, but got: %v", commandline.ExitCode(result.Error))
+	}
+
+	result = RunCli([]string{"myservice", "ping", "--id", "1", "--no-fail"}, context)
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeSuccess {
+		t.Errorf("Expected exit code 0 with --no-fail, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestAuthenticationFailureReturnsAuthErrorExitCode(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    auth:
+      clientId: failure-client-id
+      clientSecret: failure-client-secret
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithConfig(config).
+		WithResponse(200, "").
+		WithIdentityResponse(400, "Bad Request").
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeAuthError {
+		t.Errorf("Expected auth error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestServerErrorReturnsServerErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithResponse(500, `internal error`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--max-retries", "0"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeServerError {
+		t.Errorf("Expected server error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestMissingArgumentReturnsValidationErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestUnknownFlagReturnsValidationErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--unknown", "value"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestFlagWithoutValueReturnsValidationErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestMissingRequiredFlagReturnsValidationErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		Build()
+
+	result := RunCli([]string{"config", "set", "--key", "organization"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+	expected := "Invalid arguments:\n  Argument --value is missing"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected missing argument error %v, but got: %v", expected, result.Error)
+	}
+}
+
+func TestUnknownProfileReturnsConfigErrorExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--profile", "unknown"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeConfigError {
+		t.Errorf("Expected config error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestWaitTimeoutReturnsWaitTimeoutExitCode(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", exitCodeDefinition).
+		WithResponse(200, `{"status":"Pending"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--id", "1", "--wait", "status == 'Done'", "--wait-timeout", "1"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeWaitTimeout {
+		t.Errorf("Expected wait timeout exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
@@ -0,0 +1,235 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+)
+
+const forEachDefinition = `
+paths:
+  /users/{id}:
+    get:
+      operationId: get-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: filter
+        in: query
+        schema:
+          type: string
+`
+
+func TestForEachReadsValuesFromStdIn(t *testing.T) {
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n\n2\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		WithStdIn(stdIn).
+		WithUrlResponse("/users/1", 200, `{"id":1}`).
+		WithUrlResponse("/users/2", 200, `{"id":2}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	results := []map[string]interface{}{}
+	err := json.Unmarshal([]byte(result.StdOut), &results)
+	if err != nil {
+		t.Fatalf("Failed to parse for-each output: %v, output: %v", err, result.StdOut)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, but got: %v", result.StdOut)
+	}
+	for i, item := range results {
+		body := item["body"].(map[string]interface{})
+		if item["index"] != float64(i) || item["status"] != "succeeded" || body["id"] != float64(i+1) {
+			t.Errorf("Expected succeeded result for index %d, but got: %v", i, item)
+		}
+	}
+}
+
+func TestForEachReadsJsonArrayFromFile(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		WithResponse(200, `{}`).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`["my user"]`))
+	result := RunCli([]string{"myservice", "get-user", "--id", "1", "--for-each", "filter", "--for-each-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := "/users/1?filter=my+user"
+	if result.RequestUrl != expected {
+		t.Errorf("Expected request url %v, but got: %v", expected, result.RequestUrl)
+	}
+}
+
+func TestForEachNdjsonOutputReportsFailures(t *testing.T) {
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n2\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		WithStdIn(stdIn).
+		WithUrlResponse("/users/1", 200, `{"id":1}`).
+		WithUrlResponse("/users/2", 404, `{"message":"not found"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id", "--for-each-format", "ndjson", "--for-each-concurrency", "2"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code, but got: %v", result.Error)
+	}
+	lines := strings.Split(strings.TrimSpace(result.StdOut), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 NDJSON records, but got: %v", result.StdOut)
+	}
+	for _, line := range lines {
+		item := map[string]interface{}{}
+		err := json.Unmarshal([]byte(line), &item)
+		if err != nil {
+			t.Fatalf("Failed to parse NDJSON record: %v, output: %v", err, line)
+		}
+		if item["value"] == "2" && (item["status"] != "failed" || item["exitCode"] != float64(commandline.ExitCodeClientError)) {
+			t.Errorf("Expected failed result for value 2, but got: %v", item)
+		}
+		if item["value"] == "1" && item["status"] != "succeeded" {
+			t.Errorf("Expected succeeded result for value 1, but got: %v", item)
+		}
+	}
+}
+
+func TestForEachKeepsOperationConcurrencyParameter(t *testing.T) {
+	definition := `
+paths:
+  /users/{id}:
+    get:
+      operationId: get-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: concurrency
+        in: query
+        schema:
+          type: integer
+`
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithStdIn(stdIn).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id", "--for-each-concurrency", "2", "--concurrency", "0"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := "/users/1?concurrency=0"
+	if result.RequestUrl != expected {
+		t.Errorf("Expected request url %v, but got: %v", expected, result.RequestUrl)
+	}
+}
+
+func TestForEachInvalidValueReportedPerItem(t *testing.T) {
+	var stdIn bytes.Buffer
+	stdIn.WriteString("abc\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		WithStdIn(stdIn).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeValidationError {
+		t.Errorf("Expected validation error exit code, but got: %v", result.Error)
+	}
+	if !strings.Contains(result.StdOut, `"error": "Cannot convert 'id' value 'abc' to integer"`) {
+		t.Errorf("Expected conversion error in result, but got: %v", result.StdOut)
+	}
+}
+
+func TestForEachUnknownParameter(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "unknown"}, context)
+
+	expected := "Unknown parameter 'unknown' for --for-each"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected unknown parameter error, but got: %v", result.Error)
+	}
+}
+
+func TestForEachWithoutValuesShowsValidationError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id"}, context)
+
+	expected := "The --for-each parameter requires values from standard input or --for-each-file"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected missing values error, but got: %v", result.Error)
+	}
+}
+
+func TestForEachCannotBeCombinedWithWait(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", forEachDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id", "--wait", "id"}, context)
+
+	expected := "The --for-each parameter cannot be combined with --wait, --paginate or --out-file"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected combination error, but got: %v", result.Error)
+	}
+}
+
+func TestForEachPassesFalseBooleanSwitch(t *testing.T) {
+	var stdIn bytes.Buffer
+	stdIn.WriteString("false\ntrue\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", "").
+		WithCommandPlugin(SwitchPluginCommand{}).
+		WithStdIn(stdIn).
+		Build()
+
+	result := RunCli([]string{"myservice", "my-switch-command", "--name", "a", "--for-each", "delete"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	results := []map[string]interface{}{}
+	err := json.Unmarshal([]byte(result.StdOut), &results)
+	if err != nil {
+		t.Fatalf("Failed to parse for-each output: %v, output: %v", err, result.StdOut)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, but got: %v", result.StdOut)
+	}
+	for i, expected := range []bool{false, true} {
+		body := results[i]["body"].(map[string]interface{})
+		if body["delete"] != expected {
+			t.Errorf("Expected delete to be %v, but got: %v", expected, results[i])
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const mergeDefinition = `
+paths:
+  /products/{id}:
+    post:
+      operationId: create-product
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              required:
+                - name
+                - category
+              properties:
+                name:
+                  type: string
+                category:
+                  type: string
+                  default: general
+                tags:
+                  type: array
+                  items:
+                    type: string
+                price:
+                  type: object
+                  properties:
+                    value:
+                      type: integer
+                    currency:
+                      type: string
+`
+
+func TestMergeOverridesFieldFromFile(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		WithResponse(200, "").
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"name":"my-product","category":"tools","price":{"value":100,"currency":"EUR"},"tags":["a","b"]}`))
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", path, "--merge", "--price", "value=200", "--tags", "c"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `{"category":"tools","name":"my-product","price":{"currency":"EUR","value":200},"tags":["c"]}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected merged request body %v, but got: %v", expected, result.RequestBody)
+	}
+	if result.RequestUrl != "/products/1" {
+		t.Errorf("Expected path parameter in request url, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestMergeIsDefaultForJsonOperationsWithBodyArguments(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		WithResponse(200, "").
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"name":"my-product","category":"tools"}`))
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", path, "--name", "other-product"}, context)
+
+	expected := `{"category":"tools","name":"other-product"}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected merged request body %v, but got: %v", expected, result.RequestBody)
+	}
+}
+
+func TestFileWithoutBodyArgumentsIsSentAsIs(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		WithResponse(200, "").
+		Build()
+
+	path := createFile(t)
+	body := `{ "name": "my-product" }`
+	writeFile(t, path, []byte(body))
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", path}, context)
+
+	if result.RequestBody != body {
+		t.Errorf("Expected unmodified request body %v, but got: %v", body, result.RequestBody)
+	}
+}
+
+func TestMergeUsesDefaultsOnlyForMissingFields(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		WithResponse(200, "").
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"name":"my-product"}`))
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", path, "--merge"}, context)
+
+	expected := `{"category":"general","name":"my-product"}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected default value for missing field %v, but got: %v", expected, result.RequestBody)
+	}
+}
+
+func TestMergeValidatesRequiredArguments(t *testing.T) {
+	definition := strings.Replace(mergeDefinition, "                  default: general\n", "", 1)
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, "").
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`{"name":"my-product"}`))
+	result := RunCli([]string{"myservice", "create-product", "--file", path, "--merge"}, context)
+
+	expected := "Invalid arguments:\n  Argument --category is missing\n  Argument --id is missing"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected missing arguments error, but got: %v", result.Error)
+	}
+}
+
+func TestMergeReadsBodyFromStdIn(t *testing.T) {
+	var stdIn bytes.Buffer
+	stdIn.WriteString(`{"name":"my-product","category":"tools"}`)
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		WithStdIn(stdIn).
+		WithResponse(200, "").
+		Build()
+
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", "-", "--category", "books"}, context)
+
+	expected := `{"category":"books","name":"my-product"}`
+	if result.RequestBody != expected {
+		t.Errorf("Expected merged request body %v, but got: %v", expected, result.RequestBody)
+	}
+}
+
+func TestMergeInvalidJsonShowsValidationError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		Build()
+
+	path := createFile(t)
+	writeFile(t, path, []byte(`["my-product"]`))
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--file", path, "--merge"}, context)
+
+	expected := "must be a JSON object to be merged"
+	if result.Error == nil || !strings.HasSuffix(result.Error.Error(), expected) {
+		t.Errorf("Expected invalid body error, but got: %v", result.Error)
+	}
+}
+
+func TestMergeWithoutFileShowsValidationError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", mergeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "create-product", "--id", "1", "--name", "my-product", "--merge"}, context)
+
+	expected := "The --merge parameter requires a request body from --file"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected missing file error, but got: %v", result.Error)
+	}
+}
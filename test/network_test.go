@@ -0,0 +1,76 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProxyIsUsedForTokenAndServiceRequests(t *testing.T) {
+	config := fmt.Sprintf(`
+profiles:
+  - name: default
+    auth:
+      clientId: proxy-client-id
+      clientSecret: proxy-client-secret-%d
+`, time.Now().UnixNano())
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	var mutex sync.Mutex
+	proxiedPaths := []string{}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		proxiedPaths = append(proxiedPaths, r.URL.Path)
+		mutex.Unlock()
+		if r.URL.Path == "/identity_/connect/token" {
+			_, _ = w.Write([]byte(`{"access_token": "my-jwt-access-token", "expires_in": 3600, "token_type": "Bearer", "scope": "OR.Ping"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"proxied":true}`))
+	}))
+	defer proxy.Close()
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--uri", "http://my-service.invalid", "--proxy", proxy.URL}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if !strings.Contains(result.StdOut, `"proxied": true`) {
+		t.Errorf("Expected response from proxy, but got: %v", result.StdOut)
+	}
+	if strings.Join(proxiedPaths, ",") != "/identity_/connect/token,/ping" {
+		t.Errorf("Expected token and service request to be proxied, but got: %v", proxiedPaths)
+	}
+}
+
+func TestInvalidCaCertShowsError(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, "").
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--ca-cert", "not-found.pem"}, context)
+
+	if !strings.HasPrefix(result.StdErr, "Error reading CA certificate file 'not-found.pem'") {
+		t.Errorf("Expected CA certificate error, but got: %v", result.StdErr)
+	}
+}
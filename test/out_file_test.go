@@ -0,0 +1,108 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const outFileDefinition = `
+paths:
+  /download:
+    get:
+      operationId: download
+`
+
+func TestOutFileWritesResponseBodyToFile(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", outFileDefinition).
+		WithResponse(200, `{"hello":"world"}`).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	result := RunCli([]string{"myservice", "download", "--out-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.StdOut != "" {
+		t.Errorf("Expected stdout to be empty, but got: %v", result.StdOut)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"hello":"world"}` {
+		t.Errorf("Expected unmodified response body in file, but got: %v", string(data))
+	}
+}
+
+func TestOutFileWritesErrorResponseToStdOut(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", outFileDefinition).
+		WithResponse(404, `{"message":"Not found"}`).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	result := RunCli([]string{"myservice", "download", "--out-file", path}, context)
+
+	if !strings.Contains(result.StdOut, `"message": "Not found"`) {
+		t.Errorf("Expected error response on stdout, but got: %v", result.StdOut)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected file not to be created, but got: %v", err)
+	}
+}
+
+func TestOutFileDashWritesRawResponseToStdOut(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", outFileDefinition).
+		WithResponse(200, `{"hello":"world"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "download", "--out-file", "-"}, context)
+
+	if result.StdOut != `{"hello":"world"}` {
+		t.Errorf("Expected raw response body on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestBinaryContentTypeIsNotParsedAsJson(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", outFileDefinition).
+		WithResponse(200, "").
+		WithNextResponseHeader(200, map[string]string{"Content-Type": "application/octet-stream"}, `{"hello":"world"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "download"}, context)
+
+	if result.StdOut != `{"hello":"world"}` {
+		t.Errorf("Expected binary response body unmodified on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestOutFileCannotBeCombinedWithPaginate(t *testing.T) {
+	definition := `
+paths:
+  /odata/Users:
+    get:
+      operationId: list-users
+      parameters:
+      - name: $top
+        in: query
+        schema:
+          type: integer
+      - name: $skip
+        in: query
+        schema:
+          type: integer
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "list-users", "--paginate", "--out-file", "users.json"}, context)
+
+	expected := "The --out-file parameter cannot be combined with --wait or --paginate"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+)
+
+const paginationDefinition = `
+paths:
+  /odata/Users:
+    get:
+      operationId: Users_Get
+      parameters:
+      - name: $top
+        in: query
+        schema:
+          type: integer
+      - name: $skip
+        in: query
+        schema:
+          type: integer
+`
+
+func TestPaginateMergesAllPages(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"@odata.count":5,"value":[{"id":1},{"id":2}]}`).
+		WithNextResponse(200, `{"@odata.count":5,"value":[{"id":3},{"id":4}]}`).
+		WithResponse(200, `{"@odata.count":5,"value":[{"id":5}]}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `{
+  "@odata.count": 5,
+  "value": [
+    {
+      "id": 1
+    },
+    {
+      "id": 2
+    },
+    {
+      "id": 3
+    },
+    {
+      "id": 4
+    },
+    {
+      "id": 5
+    }
+  ]
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected merged pages on stdout, but got: %v", result.StdOut)
+	}
+	if result.RequestUrl != "/odata/Users?$top=2&$skip=4" {
+		t.Errorf("Expected last page to be requested, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestPaginateStopsAtMaxItems(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"value":[{"id":1},{"id":2}]}`).
+		WithResponse(200, `{"value":[{"id":3}]}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--max-items", "3", "--query", "value[].id"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `[
+  1,
+  2,
+  3
+]
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected query to be applied on merged pages, but got: %v", result.StdOut)
+	}
+	if result.RequestUrl != "/odata/Users?$top=1&$skip=2" {
+		t.Errorf("Expected last page to request remaining items only, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestPaginateTreatsTopAsMaxItems(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"value":[{"id":1},{"id":2}]}`).
+		WithResponse(200, `{"value":[{"id":3}]}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--top", "3", "--query", "value[].id"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `[
+  1,
+  2,
+  3
+]
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected items up to --top, but got: %v", result.StdOut)
+	}
+	if result.RequestUrl != "/odata/Users?$top=1&$skip=2" {
+		t.Errorf("Expected last page to request remaining items only, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestPaginateWritesCollectedItemsWhenPageFails(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"value":[{"id":1},{"id":2}]}`).
+		WithResponse(400, `{"message":"Invalid request"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--query", "value[].id"}, context)
+
+	if result.Error == nil {
+		t.Errorf("Expected error for failed page, but got none")
+	}
+	expected := `[
+  1,
+  2
+]
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected collected items on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestPaginateContinuesWhenServiceCapsPageSize(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"@odata.count":3,"value":[{"id":1}]}`).
+		WithNextResponse(200, `{"@odata.count":3,"value":[{"id":2}]}`).
+		WithResponse(200, `{"@odata.count":3,"value":[{"id":3}]}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--query", "value[].id"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.StdOut != "[\n  1,\n  2,\n  3\n]\n" {
+		t.Errorf("Expected all pages to be fetched, but got: %v", result.StdOut)
+	}
+	if result.RequestUrl != "/odata/Users?$top=2&$skip=2" {
+		t.Errorf("Expected last page to be requested, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestPaginateReturnsStatusErrorWhenPageFailsWithoutFail(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"value":[{"id":1},{"id":2}]}`).
+		WithResponse(404, `{"message":"Not found"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--no-fail", "--query", "value[].id"}, context)
+
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeClientError {
+		t.Errorf("Expected client error exit code, but got: %v", result.Error)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "status code '404'") {
+		t.Errorf("Expected status error, but got: %v", result.Error)
+	}
+	if result.StdOut != "[\n  1,\n  2\n]\n" {
+		t.Errorf("Expected collected items on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestPaginateWritesCollectedItemsWhenPageHasNoValue(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithNextResponse(200, `{"value":[{"id":1},{"id":2}]}`).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--page-size", "2", "--query", "value[].id"}, context)
+
+	expected := "Pagination stopped after 2 items: the page at $skip=2 does not contain a 'value' array"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected error %v, but got: %v", expected, result.Error)
+	}
+	if result.StdOut != "[\n  1,\n  2\n]\n" {
+		t.Errorf("Expected collected items on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestPaginateStartsAtProvidedSkip(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithResponse(200, `{"value":[]}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate", "--skip", "10"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.RequestUrl != "/odata/Users?$top=100&$skip=10" {
+		t.Errorf("Expected first page to start at provided skip, but got: %v", result.RequestUrl)
+	}
+}
+
+func TestPaginateNonListResponseIsWrittenAsIs(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", paginationDefinition).
+		WithResponse(400, `{"message":"Invalid request"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "users-get", "--paginate"}, context)
+
+	expected := `{
+  "message": "Invalid request"
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected error response on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestPaginateNotSupportedReturnsError(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--paginate"}, context)
+
+	if !strings.Contains(result.StdErr, "Operation 'ping' does not support pagination") {
+		t.Errorf("Expected pagination not supported error, but got: %v", result.StdErr)
+	}
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -337,3 +338,21 @@ func (c ParametrizedPluginCommand) Command() plugin.Command {
 func (c ParametrizedPluginCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
 	return nil
 }
+
+type SwitchPluginCommand struct{}
+
+func (c SwitchPluginCommand) Command() plugin.Command {
+	return *plugin.NewCommand("myservice").
+		WithOperation("my-switch-command", "This command echoes its switch").
+		WithParameter("name", plugin.ParameterTypeString, "The name", false).
+		WithParameter("delete", plugin.ParameterTypeBoolean, "The switch", false)
+}
+
+func (c SwitchPluginCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	result := map[string]interface{}{"delete": false}
+	for _, parameter := range context.Parameters {
+		result[parameter.Name] = parameter.Value
+	}
+	data, _ := json.Marshal(result)
+	return writer.WriteResponse(*output.NewResponseInfo(200, "200 OK", "HTTP/1.1", map[string][]string{}, bytes.NewReader(data)))
+}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const rateLimitDefinition = `
+paths:
+  /users/{id}:
+    get:
+      operationId: get-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+`
+
+func TestRateLimitQueuesRequests(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    rateLimit:
+      ratelimitedservice: 1/100ms
+`
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n2\n3\n")
+	context := NewContextBuilder().
+		WithDefinition("ratelimitedservice", rateLimitDefinition).
+		WithConfig(config).
+		WithStdIn(stdIn).
+		WithResponse(200, `{}`).
+		Build()
+
+	start := time.Now()
+	result := RunCli([]string{"ratelimitedservice", "get-user", "--for-each", "id", "--for-each-concurrency", "3"}, context)
+	elapsed := time.Since(start)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if elapsed < 200*time.Millisecond {
+		t.Errorf("Expected requests to be queued by the rate limit, but took: %v", elapsed)
+	}
+}
+
+func TestRateLimitAppliesOnlyToConfiguredService(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    rateLimit:
+      otherservice: 1/h
+`
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n2\n3\n")
+	context := NewContextBuilder().
+		WithDefinition("myservice", rateLimitDefinition).
+		WithConfig(config).
+		WithStdIn(stdIn).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--for-each", "id", "--timeout", "10s"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Expected no rate limit for other services, but got: %v", result.Error)
+	}
+}
+
+func TestRateLimitIsPassedToPlugin(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    rateLimit:
+      mypluginservice: 20/s
+`
+	pluginCommand := ContextPluginCommand{}
+	context := NewContextBuilder().
+		WithDefinition("mypluginservice", "").
+		WithConfig(config).
+		WithCommandPlugin(&pluginCommand).
+		Build()
+
+	RunCli([]string{"mypluginservice", "my-plugin-command"}, context)
+
+	if pluginCommand.Context.Network.RateLimiter == nil {
+		t.Errorf("Expected rate limiter in plugin network settings, but got nil")
+	}
+}
+
+func TestRateLimitTimeoutWhileQueued(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    rateLimit:
+      slowservice: 1/h
+`
+	var stdIn bytes.Buffer
+	stdIn.WriteString("1\n2\n")
+	context := NewContextBuilder().
+		WithDefinition("slowservice", rateLimitDefinition).
+		WithConfig(config).
+		WithStdIn(stdIn).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"slowservice", "get-user", "--for-each", "id", "--for-each-format", "ndjson", "--timeout", "200ms", "--max-retries", "0"}, context)
+
+	if result.Error == nil {
+		t.Errorf("Expected queued request to time out, but got no error")
+	}
+	if !strings.Contains(result.StdOut, `"status":"succeeded"`) {
+		t.Errorf("Expected first request to succeed, but got: %v", result.StdOut)
+	}
+}
+
+func TestRateLimitInvalidValueShowsValidationError(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    rateLimit:
+      myservice: fast
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", rateLimitDefinition).
+		WithConfig(config).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--id", "1"}, context)
+
+	expected := "Invalid rate limit for service 'myservice': Invalid rate limit 'fast', expected format: <requests>/<interval>, e.g. 20/s"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected invalid rate limit error, but got: %v", result.Error)
+	}
+}
+
+func TestConfigSetRateLimit(t *testing.T) {
+	configFile := createFile(t)
+	context := NewContextBuilder().
+		WithConfigFile(configFile).
+		Build()
+
+	RunCli([]string{"config", "set", "--key", "rateLimit.orchestrator", "--value", "20/s"}, context)
+
+	config, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Config file does not exist: %v", err)
+	}
+	expectedConfig := `profiles:
+- name: default
+  rateLimit:
+    orchestrator: 20/s
+`
+	if string(config) != expectedConfig {
+		t.Errorf("Expected generated config %v, but got %v", expectedConfig, string(config))
+	}
+}
+
+func TestConfigSetInvalidRateLimit(t *testing.T) {
+	context := NewContextBuilder().
+		Build()
+
+	result := RunCli([]string{"config", "set", "--key", "rateLimit.orchestrator", "--value", "0/s"}, context)
+
+	if !strings.HasPrefix(result.StdErr, "Invalid value for 'rateLimit.orchestrator'") {
+		t.Errorf("Expected invalid rate limit error, but got %v", result.StdErr)
+	}
+}
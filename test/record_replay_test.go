@@ -0,0 +1,139 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/UiPath/uipathcli/commandline"
+)
+
+const recordReplayDefinition = `
+paths:
+  /users/{id}:
+    get:
+      operationId: get-user
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+`
+
+func TestRecordSavesRequestsAndReplayServesThem(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    auth:
+      clientId: my-client-id
+      clientSecret: my-client-secret
+`
+	directory := t.TempDir()
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", recordReplayDefinition).
+		WithConfig(config).
+		WithIdentityResponse(200, `{"access_token": "my-access-token", "expires_in": 3600, "token_type": "Bearer", "scope": "OR.Users"}`).
+		WithResponse(200, `{"name":"John"}`).
+		Build()
+	result := RunCli([]string{"myservice", "get-user", "--id", "1", "--record", directory}, context)
+	if result.Error != nil {
+		t.Fatalf("Unexpected error while recording, got: %v", result.Error)
+	}
+
+	files, _ := os.ReadDir(directory)
+	if len(files) != 2 {
+		t.Errorf("Expected token and service request to be recorded, but got: %v", files)
+	}
+
+	context = NewContextBuilder().
+		WithDefinition("myservice", recordReplayDefinition).
+		WithConfig(config).
+		Build()
+	result = RunCli([]string{"myservice", "get-user", "--id", "1", "--replay", directory}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error while replaying, got: %v", result.Error)
+	}
+	expected := `{
+  "name": "John"
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected recorded response, but got: %v", result.StdOut)
+	}
+}
+
+func TestRecordAndReplayMultipartOperation(t *testing.T) {
+	definition := `
+paths:
+  /upload:
+    post:
+      operationId: upload
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              properties:
+                file:
+                  type: string
+                  format: binary
+`
+	directory := t.TempDir()
+	path := createFile(t)
+	writeFile(t, path, []byte("hello-world"))
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithResponse(200, `{"uploaded":true}`).
+		Build()
+	result := RunCli([]string{"myservice", "upload", "--file", path, "--record", directory}, context)
+	if result.Error != nil {
+		t.Fatalf("Unexpected error while recording, got: %v", result.Error)
+	}
+
+	context = NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+	result = RunCli([]string{"myservice", "upload", "--file", path, "--replay", directory}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error while replaying, got: %v", result.Error)
+	}
+	expected := `{
+  "uploaded": true
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected recorded response, but got: %v", result.StdOut)
+	}
+}
+
+func TestReplayWithoutMatchingRecordingFails(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", recordReplayDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--id", "2", "--replay", t.TempDir()}, context)
+
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "No recorded response found for request 'GET /users/2'") {
+		t.Errorf("Expected missing recording error, but got: %v", result.Error)
+	}
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeError {
+		t.Errorf("Expected generic error exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestRecordAndReplayCannotBeCombined(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", recordReplayDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "get-user", "--id", "1", "--record", "dir1", "--replay", "dir2"}, context)
+
+	expected := "The --record and --replay parameters cannot be combined"
+	if result.Error == nil || result.Error.Error() != expected {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
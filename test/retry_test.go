@@ -0,0 +1,109 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const retryDefinition = `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+
+func TestRetryThrottledRequestWithRetryAfter(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithNextResponseHeader(429, map[string]string{"Retry-After": "1"}, "Too many requests").
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	start := time.Now()
+	result := RunCli([]string{"myservice", "ping", "--retry-backoff", "1ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if time.Since(start) < 1*time.Second {
+		t.Errorf("Expected Retry-After header to be honored, but retried after %v", time.Since(start))
+	}
+	expected := `{
+  "id": 1
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected response body, but got: %v", result.StdOut)
+	}
+}
+
+func TestRetryWithMaxRetriesReturnsLastError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithNextResponse(503, "unavailable 1").
+		WithNextResponse(503, "unavailable 2").
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--max-retries", "1", "--retry-backoff", "1ms"}, context)
+
+	if result.Error == nil || !strings.Contains(result.StdErr, "Service returned status code '503' and body 'unavailable 2'") {
+		t.Errorf("Expected error after retries are exhausted, but got: %v", result.StdErr)
+	}
+}
+
+func TestRetryWithCustomStatusCodes(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithNextResponse(409, "conflict").
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--retry-status-codes", "409,503", "--retry-backoff", "1ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := `{
+  "id": 1
+}
+`
+	if result.StdOut != expected {
+		t.Errorf("Expected response body after retry, but got: %v", result.StdOut)
+	}
+}
+
+func TestRetryPolicyFromProfile(t *testing.T) {
+	config := `
+profiles:
+- name: default
+  retry:
+    maxRetries: 0
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithConfig(config).
+		WithNextResponse(503, "unavailable").
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping"}, context)
+
+	if result.Error == nil || !strings.Contains(result.StdErr, "Service returned status code '503' and body 'unavailable'") {
+		t.Errorf("Expected request not to be retried, but got: %v", result.StdErr)
+	}
+}
+
+func TestRetryInvalidStatusCodesReturnsError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--retry-status-codes", "abc"}, context)
+
+	if !strings.Contains(result.StdErr, "Invalid value for --retry-status-codes: 'abc' is not a status code") {
+		t.Errorf("Expected invalid status code error, but got: %v", result.StdErr)
+	}
+}
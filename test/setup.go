@@ -10,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/UiPath/uipathcli/auth"
 	"github.com/UiPath/uipathcli/cache"
@@ -63,22 +64,32 @@ func (b *ContextBuilder) WithStdIn(input bytes.Buffer) *ContextBuilder {
 }
 
 func (b *ContextBuilder) WithNextResponse(statusCode int, body string) *ContextBuilder {
-	b.context.NextResponses = append(b.context.NextResponses, ResponseData{statusCode, body})
+	b.context.NextResponses = append(b.context.NextResponses, ResponseData{statusCode, body, map[string]string{}})
+	return b
+}
+
+func (b *ContextBuilder) WithNextResponseHeader(statusCode int, header map[string]string, body string) *ContextBuilder {
+	b.context.NextResponses = append(b.context.NextResponses, ResponseData{statusCode, body, header})
 	return b
 }
 
 func (b *ContextBuilder) WithResponse(statusCode int, body string) *ContextBuilder {
-	b.context.Responses["*"] = ResponseData{statusCode, body}
+	b.context.Responses["*"] = ResponseData{statusCode, body, map[string]string{}}
 	return b
 }
 
 func (b *ContextBuilder) WithUrlResponse(url string, statusCode int, body string) *ContextBuilder {
-	b.context.Responses[url] = ResponseData{statusCode, body}
+	b.context.Responses[url] = ResponseData{statusCode, body, map[string]string{}}
 	return b
 }
 
 func (b *ContextBuilder) WithIdentityResponse(statusCode int, body string) *ContextBuilder {
-	b.context.IdentityResponse = ResponseData{statusCode, body}
+	b.context.IdentityResponse = ResponseData{statusCode, body, map[string]string{}}
+	return b
+}
+
+func (b *ContextBuilder) WithResponseDelay(delay time.Duration) *ContextBuilder {
+	b.context.ResponseDelay = delay
 	return b
 }
 
@@ -94,6 +105,7 @@ func (b *ContextBuilder) Build() Context {
 type ResponseData struct {
 	Status int
 	Body   string
+	Header map[string]string
 }
 
 type Context struct {
@@ -104,6 +116,7 @@ type Context struct {
 	NextResponses    []ResponseData
 	Responses        map[string]ResponseData
 	IdentityResponse ResponseData
+	ResponseDelay    time.Duration
 	CommandPlugin    plugin.CommandPlugin
 }
 
@@ -169,6 +182,16 @@ func RunCli(args []string, context Context) Result {
 				response = nextResponses[0]
 				context.NextResponses = nextResponses[1:]
 			}
+			if context.ResponseDelay > 0 {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(context.ResponseDelay):
+				}
+			}
+			for key, value := range response.Header {
+				w.Header().Set(key, value)
+			}
 			w.WriteHeader(response.Status)
 			_, _ = w.Write([]byte(response.Body))
 		}))
@@ -208,7 +231,7 @@ func RunCli(args []string, context Context) Result {
 		*config.NewConfigProvider(
 			config.NewConfigFileStoreWithData(context.ConfigFile, []byte(context.Config)),
 		),
-		executor.NewHttpExecutor(authenticators),
+		executor.NewHttpExecutor(authenticators, cache.NewFileResponseCache()),
 		executor.NewPluginExecutor(authenticators),
 	)
 	args = append([]string{"uipath"}, args...)
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UiPath/uipathcli/commandline"
+	"github.com/UiPath/uipathcli/log"
+	"github.com/UiPath/uipathcli/output"
+	"github.com/UiPath/uipathcli/plugin"
+)
+
+const timeoutDefinition = `
+paths:
+  /ping:
+    get:
+      operationId: ping
+`
+
+func TestTimeoutAbortsSlowRequest(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", timeoutDefinition).
+		WithResponseDelay(5*time.Second).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	start := time.Now()
+	result := RunCli([]string{"myservice", "ping", "--timeout", "100ms"}, context)
+
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("Expected request to be aborted after timeout, but took: %v", time.Since(start))
+	}
+	if result.StdErr != "Operation timed out after 100ms\n" {
+		t.Errorf("Expected timeout error, but got: %v", result.StdErr)
+	}
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeTimeout {
+		t.Errorf("Expected timeout exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestTimeoutFromProfile(t *testing.T) {
+	config := `
+profiles:
+  - name: default
+    timeout: 100ms
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", timeoutDefinition).
+		WithConfig(config).
+		WithResponseDelay(5*time.Second).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping"}, context)
+
+	if result.StdErr != "Operation timed out after 100ms\n" {
+		t.Errorf("Expected timeout error from profile, but got: %v", result.StdErr)
+	}
+}
+
+func TestTimeoutNotReachedReturnsResponse(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", timeoutDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--timeout", "10s"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+}
+
+func TestTimeoutStopsWaitingForCondition(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", timeoutDefinition).
+		WithResponse(200, `{"status":"Pending"}`).
+		Build()
+
+	start := time.Now()
+	result := RunCli([]string{"myservice", "ping", "--wait", "status == 'Done'", "--wait-timeout", "30", "--timeout", "200ms"}, context)
+
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("Expected wait to be aborted after timeout, but took: %v", time.Since(start))
+	}
+	if commandline.ExitCode(result.Error) != commandline.ExitCodeTimeout {
+		t.Errorf("Expected timeout exit code, but got: %v", commandline.ExitCode(result.Error))
+	}
+}
+
+func TestTimeoutCancelsPluginContext(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: my-blocking-command
+`
+	context := NewContextBuilder().
+		WithDefinition("mypluginservice", definition).
+		WithCommandPlugin(BlockingPluginCommand{}).
+		Build()
+
+	result := RunCli([]string{"mypluginservice", "my-blocking-command", "--timeout", "100ms"}, context)
+
+	if result.StdErr != "Operation timed out after 100ms\n" {
+		t.Errorf("Expected plugin to be cancelled after timeout, but got: %v", result.StdErr)
+	}
+}
+
+type BlockingPluginCommand struct{}
+
+func (c BlockingPluginCommand) Command() plugin.Command {
+	return *plugin.NewCommand("mypluginservice").
+		WithOperation("my-blocking-command", "This command blocks until it is cancelled")
+}
+
+func (c BlockingPluginCommand) Execute(context plugin.ExecutionContext, writer output.OutputWriter, logger log.Logger) error {
+	<-context.Context.Done()
+	return context.Context.Err()
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTimingPrintsRequestTimingOnStdErr(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--timing"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	lines := strings.Split(strings.TrimSpace(result.StdErr), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "Timing: GET http://127.0.0.1") {
+		t.Fatalf("Expected single timing line on stderr, but got: %v", result.StdErr)
+	}
+	for _, field := range []string{"/ping 200 ", "attempt=1", "dns=", "connect=", "tls=", "ttfb=", "transfer=", "total=", "bytes=8"} {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("Expected %v in timing line, but got: %v", field, lines[0])
+		}
+	}
+	if result.StdOut != "{\n  \"id\": 1\n}\n" {
+		t.Errorf("Expected response on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestTimingReportsRetryAttempts(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithNextResponse(503, "unavailable").
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--timing", "--retry-backoff", "1ms"}, context)
+
+	if !strings.Contains(result.StdErr, " 503 attempt=1 ") || !strings.Contains(result.StdErr, " 200 attempt=2 ") {
+		t.Errorf("Expected timing for every attempt, but got: %v", result.StdErr)
+	}
+}
+
+func TestTimingIncludesTokenRequest(t *testing.T) {
+	setCacheDirectory(t)
+	config := `
+profiles:
+  - name: default
+    auth:
+      clientId: timing-client-id
+      clientSecret: timing-client-secret
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithConfig(config).
+		WithResponse(200, `{"id":1}`).
+		WithIdentityResponse(200, `{"access_token": "my-jwt-access-token", "expires_in": 3600, "token_type": "Bearer", "scope": "OR.Ping"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--timing"}, context)
+
+	if !strings.Contains(result.StdErr, "/identity_/connect/token 200 attempt=1") {
+		t.Errorf("Expected timing for token request, but got: %v", result.StdErr)
+	}
+	if !strings.Contains(result.StdErr, "/ping 200 attempt=1") {
+		t.Errorf("Expected timing for operation request, but got: %v", result.StdErr)
+	}
+}
+
+func TestTimingWithDebugPrintsJsonBlock(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--timing", "--debug"}, context)
+
+	start := strings.LastIndex(result.StdErr, "{\n  \"method\"")
+	if start == -1 {
+		t.Fatalf("Expected json timing block in debug output, but got: %v", result.StdErr)
+	}
+	timing := map[string]interface{}{}
+	err := json.NewDecoder(strings.NewReader(result.StdErr[start:])).Decode(&timing)
+	if err != nil {
+		t.Fatalf("Failed to parse timing block: %v", err)
+	}
+	if timing["method"] != "GET" || timing["status"] != 200.0 || timing["bytes"] != 8.0 {
+		t.Errorf("Expected request details in timing block, but got: %v", timing)
+	}
+	for _, field := range []string{"dnsMs", "connectMs", "tlsHandshakeMs", "timeToFirstByteMs", "transferMs", "totalMs"} {
+		if _, found := timing[field]; !found {
+			t.Errorf("Expected %v in timing block, but got: %v", field, timing)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-01$`)
+
+func TestTraceSendsTraceparentHeader(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping"}, context)
+
+	traceparent := result.RequestHeader["traceparent"]
+	if !traceparentPattern.MatchString(traceparent) {
+		t.Errorf("Expected valid traceparent header, but got: %v", traceparent)
+	}
+}
+
+func TestTraceKeepsRequestIdAcrossRetries(t *testing.T) {
+	headers := []http.Header{}
+	mutex := sync.Mutex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		headers = append(headers, r.Header.Clone())
+		if len(headers) == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--uri", server.URL, "--retry-backoff", "1ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if len(headers) != 2 {
+		t.Fatalf("Expected 2 requests, but got: %v", len(headers))
+	}
+	if headers[0].Get("x-request-id") == "" || headers[0].Get("x-request-id") != headers[1].Get("x-request-id") {
+		t.Errorf("Expected same request id for all attempts, but got: %v and %v", headers[0].Get("x-request-id"), headers[1].Get("x-request-id"))
+	}
+	first := traceparentPattern.FindStringSubmatch(headers[0].Get("traceparent"))
+	second := traceparentPattern.FindStringSubmatch(headers[1].Get("traceparent"))
+	if first == nil || second == nil {
+		t.Fatalf("Expected traceparent header for all attempts, but got: %v", headers)
+	}
+	if first[1] != second[1] {
+		t.Errorf("Expected same trace id for all attempts, but got: %v and %v", first[1], second[1])
+	}
+	if first[2] == second[2] {
+		t.Errorf("Expected different span id for every attempt, but got: %v", first[2])
+	}
+}
+
+func TestTraceRequestIdArgumentOverridesRequestId(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(200, `{"id":1}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--request-id", "my-request-id"}, context)
+
+	requestId := result.RequestHeader["x-request-id"]
+	if requestId != "my-request-id" {
+		t.Errorf("Expected request id from argument, but got: %v", requestId)
+	}
+}
+
+func TestTraceFileWritesOtlpSpans(t *testing.T) {
+	setCacheDirectory(t)
+	config := `
+profiles:
+  - name: default
+    auth:
+      clientId: trace-client-id
+      clientSecret: trace-client-secret
+`
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithConfig(config).
+		WithResponse(200, `{"id":1}`).
+		WithIdentityResponse(200, `{"access_token": "my-jwt-access-token", "expires_in": 3600, "token_type": "Bearer", "scope": "OR.Ping"}`).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	result := RunCli([]string{"myservice", "ping", "--trace-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	spans := readTraceFile(t, path)
+	root := findSpan(t, spans, "uipath myservice ping")
+	auth := findSpan(t, spans, "auth")
+	token := findSpan(t, spans, "HTTP POST")
+	ping := findSpan(t, spans, "HTTP GET")
+	if root["parentSpanId"] != nil || auth["parentSpanId"] != root["spanId"] || ping["parentSpanId"] != root["spanId"] {
+		t.Errorf("Expected auth and request span as children of the command span, but got: %v", spans)
+	}
+	if token["parentSpanId"] != auth["spanId"] {
+		t.Errorf("Expected token request span as child of the auth span, but got: %v", spans)
+	}
+	for _, span := range spans {
+		if span["traceId"] != root["traceId"] {
+			t.Errorf("Expected all spans to share the trace id, but got: %v", span)
+		}
+	}
+	expected := "00-" + ping["traceId"].(string) + "-" + ping["spanId"].(string) + "-01"
+	if result.RequestHeader["traceparent"] != expected {
+		t.Errorf("Expected traceparent %v, but got: %v", expected, result.RequestHeader["traceparent"])
+	}
+	attributes := spanAttributes(ping)
+	if attributes["http.response.status_code"] != "200" || !strings.HasSuffix(attributes["url.full"], "/ping") {
+		t.Errorf("Expected http attributes on request span, but got: %v", attributes)
+	}
+}
+
+func TestTraceFileMarksFailedSpans(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", retryDefinition).
+		WithResponse(500, `{"error":"failed"}`).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	RunCli([]string{"myservice", "ping", "--trace-file", path, "--max-retries", "0"}, context)
+
+	spans := readTraceFile(t, path)
+	root := findSpan(t, spans, "uipath myservice ping")
+	status := root["status"].(map[string]interface{})
+	if status["code"] != 2.0 || !strings.Contains(status["message"].(string), "500") {
+		t.Errorf("Expected error status on command span, but got: %v", status)
+	}
+}
+
+func TestTraceFileRecordsPluginSpan(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("mypluginservice", "").
+		WithCommandPlugin(SimplePluginCommand{}).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	result := RunCli([]string{"mypluginservice", "my-plugin-command", "--trace-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	spans := readTraceFile(t, path)
+	root := findSpan(t, spans, "uipath mypluginservice my-plugin-command")
+	plugin := findSpan(t, spans, "plugin mypluginservice my-plugin-command")
+	if plugin["parentSpanId"] != root["spanId"] {
+		t.Errorf("Expected plugin span as child of the command span, but got: %v", spans)
+	}
+}
+
+func TestTraceFileBatchWritesSingleTrace(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", batchDefinition).
+		WithUrlResponse("/users/1", 200, `{"id":1}`).
+		WithUrlResponse("/users/2", 200, `{"id":2}`).
+		Build()
+
+	file := createFile(t)
+	writeFile(t, file, []byte(`{"service": "myservice", "operation": "users get-user", "parameters": {"id": 1}}
+{"service": "myservice", "operation": "users get-user", "parameters": {"id": 2}}
+`))
+	path := filepath.Join(t.TempDir(), "trace.json")
+	result := RunCli([]string{"batch", "--file", file, "--trace-file", path}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	spans := readTraceFile(t, path)
+	root := findSpan(t, spans, "uipath batch")
+	operations := 0
+	for _, span := range spans {
+		if span["traceId"] != root["traceId"] {
+			t.Errorf("Expected all spans to share the trace id, but got: %v", span)
+		}
+		if span["name"] == "uipath myservice users get-user" {
+			operations++
+			if span["parentSpanId"] != root["spanId"] {
+				t.Errorf("Expected operation span as child of the batch span, but got: %v", span)
+			}
+		}
+	}
+	if operations != 2 {
+		t.Errorf("Expected 2 operation spans, but got: %v", spans)
+	}
+}
+
+func readTraceFile(t *testing.T, path string) []map[string]interface{} {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading trace file: %v", err)
+	}
+	trace := struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []map[string]interface{} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}{}
+	err = json.Unmarshal(data, &trace)
+	if err != nil {
+		t.Fatalf("Error parsing trace file: %v", err)
+	}
+	if len(trace.ResourceSpans) != 1 || len(trace.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Expected single resource and scope in trace file, but got: %v", string(data))
+	}
+	return trace.ResourceSpans[0].ScopeSpans[0].Spans
+}
+
+func findSpan(t *testing.T, spans []map[string]interface{}, name string) map[string]interface{} {
+	for _, span := range spans {
+		if span["name"] == name {
+			return span
+		}
+	}
+	t.Fatalf("Could not find span '%s' in trace: %v", name, spans)
+	return nil
+}
+
+func spanAttributes(span map[string]interface{}) map[string]string {
+	result := map[string]string{}
+	for _, attribute := range span["attributes"].([]interface{}) {
+		entry := attribute.(map[string]interface{})
+		for _, value := range entry["value"].(map[string]interface{}) {
+			result[entry["key"].(string)] = value.(string)
+		}
+	}
+	return result
+}
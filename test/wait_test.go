@@ -1,7 +1,12 @@
 package test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestWaitNonBooleanExpressionReturnsError(t *testing.T) {
@@ -152,3 +157,281 @@ paths:
 		t.Errorf("Expected timeout error, but got: %v", result.Error)
 	}
 }
+
+func TestWaitConditionOnStatusCode(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithNextResponse(202, "").
+		WithResponse(200, `{"version":2}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait", "_status == `200`", "--wait-interval", "10ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if !strings.Contains(result.StdOut, `"version": 2`) {
+		t.Errorf("Expected response body, but got: %v", result.StdOut)
+	}
+}
+
+func TestWaitConditionOnHeader(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithNextResponseHeader(200, map[string]string{"X-State": "running"}, `{}`).
+		WithNextResponseHeader(200, map[string]string{"X-State": "done"}, `{}`).
+		WithResponse(200, `{}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait", "_headers.\"x-state\" == 'done'", "--wait-interval", "10ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if result.StdErr != "Condition is not met yet. Waiting...\n" {
+		t.Errorf("Expected single status message, but got: %v", result.StdErr)
+	}
+}
+
+func TestWaitFailStopsWaiting(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithNextResponse(200, `{"state":"Running"}`).
+		WithResponse(200, `{"state":"Faulted"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait", "state == 'Successful'", "--wait-fail", "state == 'Faulted'", "--wait-interval", "10ms"}, context)
+
+	if result.Error == nil || result.Error.Error() != "Stopped waiting because the --wait-fail condition is met" {
+		t.Errorf("Expected wait fail error, but got: %v", result.Error)
+	}
+	if !strings.Contains(result.StdOut, `"state": "Faulted"`) {
+		t.Errorf("Expected failed response on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestWaitProgressPrintsCurrentValue(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithNextResponse(200, `{"state":"Pending"}`).
+		WithNextResponse(200, `{"state":"Running"}`).
+		WithResponse(200, `{"state":"Successful"}`).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait", "state == 'Successful'", "--wait-progress", "state", "--wait-interval", "10ms"}, context)
+
+	expected := `Condition is not met yet (Pending). Waiting...
+Condition is not met yet (Running). Waiting...
+`
+	if result.StdErr != expected {
+		t.Errorf("Expected progress on standard error, but got: %v", result.StdErr)
+	}
+}
+
+func TestWaitIntervalControlsPollingDelay(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		WithNextResponse(200, `{"version":1}`).
+		WithNextResponse(200, `{"version":1}`).
+		WithResponse(200, `{"version":2}`).
+		Build()
+
+	start := time.Now()
+	result := RunCli([]string{"myservice", "ping", "--wait", "version == `2`", "--wait-interval", "50ms", "--wait-backoff"}, context)
+	elapsed := time.Since(start)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	if elapsed < 150*time.Millisecond || elapsed > 1*time.Second {
+		t.Errorf("Expected to wait 50ms and 100ms between polls, but took: %v", elapsed)
+	}
+}
+
+func TestWaitInvalidIntervalReturnsValidationError(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait", "version == `2`", "--wait-interval", "0s"}, context)
+
+	if result.Error == nil || result.Error.Error() != "Invalid value for --wait-interval: needs to be greater than 0" {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
+
+func TestWaitFailWithoutWaitReturnsValidationError(t *testing.T) {
+	definition := `
+paths:
+  /ping:
+    get:
+      operationId: ping
+      summary: Simple ping
+`
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", definition).
+		Build()
+
+	result := RunCli([]string{"myservice", "ping", "--wait-fail", "state == 'Faulted'"}, context)
+
+	if result.Error == nil || result.Error.Error() != "The --wait-fail parameter requires --wait" {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
+
+const waitProbeDefinition = `
+paths:
+  /jobs:
+    post:
+      operationId: start-job
+      tags:
+        - jobs
+      parameters:
+      - name: X-Folder
+        in: header
+        schema:
+          type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+  /jobs/{key}:
+    get:
+      operationId: get-job
+      tags:
+        - jobs
+      parameters:
+      - name: key
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: X-Folder
+        in: header
+        required: true
+        schema:
+          type: string
+    delete:
+      operationId: delete-job
+      tags:
+        - jobs
+      parameters:
+      - name: key
+        in: path
+        required: true
+        schema:
+          type: integer
+`
+
+func TestWaitProbePollsGetOperation(t *testing.T) {
+	requests := []string{}
+	mutex := sync.Mutex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("X-Folder"))
+		w.WriteHeader(200)
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"Id":123456789,"State":"Pending"}`))
+		} else if len(requests) < 3 {
+			_, _ = w.Write([]byte(`{"Id":123456789,"State":"Running"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"Id":123456789,"State":"Successful"}`))
+		}
+	}))
+	defer server.Close()
+
+	context := NewContextBuilder().
+		WithDefinition("myservice", waitProbeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "jobs", "start-job", "--uri", server.URL, "--name", "my-job", "--x-folder", "42",
+		"--wait", "State == 'Successful'", "--wait-probe", "jobs get-job", "--wait-interval", "10ms"}, context)
+
+	if result.Error != nil {
+		t.Errorf("Unexpected error, got: %v", result.Error)
+	}
+	expected := []string{"POST /jobs 42", "GET /jobs/123456789 42", "GET /jobs/123456789 42"}
+	if strings.Join(requests, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected single POST request followed by probe requests, but got: %v", requests)
+	}
+	if !strings.Contains(result.StdOut, `"State": "Successful"`) {
+		t.Errorf("Expected probe response on stdout, but got: %v", result.StdOut)
+	}
+}
+
+func TestWaitProbeRequiresGetOperation(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", waitProbeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "jobs", "start-job", "--wait", "State == 'Successful'", "--wait-probe", "jobs delete-job"}, context)
+
+	if result.Error == nil || result.Error.Error() != "The --wait-probe operation 'jobs delete-job' needs to be a GET operation" {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
+
+func TestWaitProbeUnknownOperationReturnsValidationError(t *testing.T) {
+	context := NewContextBuilder().
+		WithDefinition("myservice", waitProbeDefinition).
+		Build()
+
+	result := RunCli([]string{"myservice", "jobs", "start-job", "--wait", "State == 'Successful'", "--wait-probe", "jobs unknown"}, context)
+
+	if result.Error == nil || result.Error.Error() != "Unknown operation 'jobs unknown' for service 'myservice'" {
+		t.Errorf("Expected validation error, but got: %v", result.Error)
+	}
+}
@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const otlpStatusOk = 1
+const otlpStatusError = 2
+
+// The OtlpWriter stores the spans in the OTLP/JSON format so that the trace
+// can be imported into tracing tools which support OpenTelemetry.
+type OtlpWriter struct {
+	path        string
+	serviceName string
+}
+
+type otlpTrace struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceId           string          `json:"traceId"`
+	SpanId            string          `json:"spanId"`
+	ParentSpanId      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (w OtlpWriter) Write(spans []Span) error {
+	converted := []otlpSpan{}
+	for _, span := range spans {
+		converted = append(converted, w.convertSpan(span))
+	}
+	trace := otlpTrace{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{w.convertAttribute("service.name", w.serviceName)},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: w.serviceName},
+						Spans: converted,
+					},
+				},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error serializing trace: %w", err)
+	}
+	err = os.WriteFile(w.path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Error writing trace file '%s': %w", w.path, err)
+	}
+	return nil
+}
+
+func (w OtlpWriter) convertSpan(span Span) otlpSpan {
+	status := otlpStatus{Code: otlpStatusOk}
+	if span.Error != "" {
+		status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+	}
+	return otlpSpan{
+		TraceId:           span.TraceId,
+		SpanId:            span.SpanId,
+		ParentSpanId:      span.ParentSpanId,
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: fmt.Sprintf("%d", span.StartTime.UnixNano()),
+		EndTimeUnixNano:   fmt.Sprintf("%d", span.EndTime.UnixNano()),
+		Attributes:        w.convertAttributes(span.Attributes),
+		Status:            status,
+	}
+}
+
+func (w OtlpWriter) convertAttributes(attributes map[string]interface{}) []otlpAttribute {
+	keys := []string{}
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := []otlpAttribute{}
+	for _, key := range keys {
+		result = append(result, w.convertAttribute(key, attributes[key]))
+	}
+	return result
+}
+
+func (w OtlpWriter) convertAttribute(key string, value interface{}) otlpAttribute {
+	switch v := value.(type) {
+	case bool:
+		return otlpAttribute{key, otlpValue{BoolValue: &v}}
+	case int, int32, int64:
+		intValue := fmt.Sprintf("%d", v)
+		return otlpAttribute{key, otlpValue{IntValue: &intValue}}
+	case float64:
+		return otlpAttribute{key, otlpValue{DoubleValue: &v}}
+	default:
+		stringValue := fmt.Sprintf("%v", v)
+		return otlpAttribute{key, otlpValue{StringValue: &stringValue}}
+	}
+}
+
+func NewOtlpWriter(path string, serviceName string) *OtlpWriter {
+	return &OtlpWriter{path, serviceName}
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOtlpWriterConvertsAttributes(t *testing.T) {
+	tracer := NewTracer()
+	_, span := tracer.Start(context.Background(), "command")
+	span.SetAttribute("name", "value")
+	span.SetAttribute("count", 3)
+	span.SetAttribute("enabled", true)
+	span.End(nil)
+
+	trace := writeTrace(t, tracer.Spans())
+
+	expected := `[{"key":"count","value":{"intValue":"3"}},{"key":"enabled","value":{"boolValue":true}},{"key":"name","value":{"stringValue":"value"}}]`
+	attributes, _ := json.Marshal(trace.ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes)
+	if string(attributes) != expected {
+		t.Errorf("Expected attributes %v, but got: %v", expected, string(attributes))
+	}
+	resource, _ := json.Marshal(trace.ResourceSpans[0].Resource.Attributes)
+	if string(resource) != `[{"key":"service.name","value":{"stringValue":"uipathcli"}}]` {
+		t.Errorf("Expected service name resource attribute, but got: %v", string(resource))
+	}
+}
+
+func TestOtlpWriterSetsStatus(t *testing.T) {
+	tracer := NewTracer()
+	ctx, root := tracer.Start(context.Background(), "command")
+	_, child := Start(ctx, "request", SpanKindClient)
+	child.End(os.ErrDeadlineExceeded)
+	root.End(nil)
+
+	trace := writeTrace(t, tracer.Spans())
+
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+	if spans[0].Status.Code != otlpStatusError || spans[0].Status.Message != os.ErrDeadlineExceeded.Error() || spans[0].Kind != SpanKindClient {
+		t.Errorf("Expected failed client span, but got: %v", spans[0])
+	}
+	if spans[1].Status.Code != otlpStatusOk || spans[1].ParentSpanId != "" {
+		t.Errorf("Expected successful root span, but got: %v", spans[1])
+	}
+	if spans[0].StartTimeUnixNano == "" || spans[0].EndTimeUnixNano < spans[0].StartTimeUnixNano {
+		t.Errorf("Expected start and end time, but got: %v - %v", spans[0].StartTimeUnixNano, spans[0].EndTimeUnixNano)
+	}
+}
+
+func writeTrace(t *testing.T, spans []Span) otlpTrace {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	err := NewOtlpWriter(path, "uipathcli").Write(spans)
+	if err != nil {
+		t.Fatalf("Unexpected error writing trace: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	trace := otlpTrace{}
+	err = json.Unmarshal(data, &trace)
+	if err != nil {
+		t.Fatalf("Error parsing trace: %v", err)
+	}
+	return trace
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The Span records a single operation of the trace, e.g. an HTTP request, an
+// authentication step or a phase of a plugin.
+//
+// All methods can be called on a nil span so that callers do not need to
+// check whether the operation is traced.
+type Span struct {
+	TraceId      string
+	SpanId       string
+	ParentSpanId string
+	Name         string
+	Kind         SpanKind
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	Error        string
+
+	tracer *Tracer
+	mutex  *sync.Mutex
+	ended  bool
+}
+
+// Traceparent returns the W3C traceparent header value which identifies the
+// span as parent of the operations performed by the service.
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", s.TraceId, s.SpanId)
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Attributes[key] = value
+}
+
+// End completes the span and adds it to the trace. The span is marked as
+// failed when an error is provided.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.mutex.Unlock()
+	s.tracer.add(s)
+}
+
+func (s *Span) snapshot() Span {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	attributes := map[string]interface{}{}
+	for key, value := range s.Attributes {
+		attributes[key] = value
+	}
+	return Span{
+		TraceId:      s.TraceId,
+		SpanId:       s.SpanId,
+		ParentSpanId: s.ParentSpanId,
+		Name:         s.Name,
+		Kind:         s.Kind,
+		StartTime:    s.StartTime,
+		EndTime:      s.EndTime,
+		Attributes:   attributes,
+		Error:        s.Error,
+	}
+}
+
+func newSpan(tracer *Tracer, parentSpanId string, name string, kind SpanKind) *Span {
+	return &Span{
+		TraceId:      tracer.TraceId(),
+		SpanId:       newId(8),
+		ParentSpanId: parentSpanId,
+		Name:         name,
+		Kind:         kind,
+		StartTime:    time.Now(),
+		Attributes:   map[string]interface{}{},
+		tracer:       tracer,
+		mutex:        &sync.Mutex{},
+	}
+}
@@ -0,0 +1,10 @@
+package tracing
+
+// SpanKind describes the relationship of the span to the other spans of the
+// trace. The values correspond to the OTLP span kinds.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindClient   SpanKind = 3
+)
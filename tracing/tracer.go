@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// The Tracer collects the spans of a single CLI invocation. All spans share
+// the same trace id so that the requests can be correlated with the logs of
+// the services.
+type Tracer struct {
+	traceId string
+	spans   []*Span
+	mutex   *sync.Mutex
+}
+
+func (t Tracer) TraceId() string {
+	return t.traceId
+}
+
+// Start creates the root span of the trace and returns a copy of the context
+// which carries the span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	span := newSpan(t, "", name, SpanKindInternal)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Spans returns all completed spans in the order they ended.
+func (t *Tracer) Spans() []Span {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := []Span{}
+	for _, span := range t.spans {
+		result = append(result, span.snapshot())
+	}
+	return result
+}
+
+func (t *Tracer) add(span *Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.spans = append(t.spans, span)
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{
+		traceId: newId(16),
+		spans:   []*Span{},
+		mutex:   &sync.Mutex{},
+	}
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStartWithoutTraceReturnsNilSpan(t *testing.T) {
+	ctx, span := Start(context.Background(), "request", SpanKindClient)
+
+	if span != nil {
+		t.Errorf("Expected no span without trace, but got: %v", span)
+	}
+	span.SetAttribute("key", "value")
+	span.End(nil)
+	if span.Traceparent() != "" {
+		t.Errorf("Expected empty traceparent, but got: %v", span.Traceparent())
+	}
+	if SpanFromContext(ctx) != nil {
+		t.Errorf("Expected context without span")
+	}
+}
+
+func TestStartCreatesChildSpan(t *testing.T) {
+	tracer := NewTracer()
+	ctx, root := tracer.Start(context.Background(), "command")
+	_, child := Start(ctx, "request", SpanKindClient)
+
+	if child.TraceId != tracer.TraceId() || child.ParentSpanId != root.SpanId {
+		t.Errorf("Expected child span of the root span, but got: %v", child)
+	}
+	if len(child.TraceId) != 32 || len(child.SpanId) != 16 || child.SpanId == root.SpanId {
+		t.Errorf("Expected new span id, but got: %v", child.SpanId)
+	}
+	expected := "00-" + child.TraceId + "-" + child.SpanId + "-01"
+	if child.Traceparent() != expected {
+		t.Errorf("Expected traceparent %v, but got: %v", expected, child.Traceparent())
+	}
+}
+
+func TestSpansReturnsEndedSpansOnce(t *testing.T) {
+	tracer := NewTracer()
+	ctx, root := tracer.Start(context.Background(), "command")
+	_, child := Start(ctx, "request", SpanKindClient)
+	child.End(errors.New("Connection refused"))
+	child.End(nil)
+	root.End(nil)
+
+	spans := tracer.Spans()
+
+	if len(spans) != 2 || spans[0].Name != "request" || spans[1].Name != "command" {
+		t.Fatalf("Expected request and command span, but got: %v", spans)
+	}
+	if spans[0].Error != "Connection refused" || spans[1].Error != "" {
+		t.Errorf("Expected error only on request span, but got: %v", spans)
+	}
+}
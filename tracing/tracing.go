@@ -0,0 +1,36 @@
+// Package tracing records the spans of a CLI invocation and propagates the
+// W3C trace context to the services.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+type spanKey struct{}
+
+// Start creates a new span as child of the span stored in the context and
+// returns a copy of the context which carries the new span.
+// No span is created when the context is not part of a trace.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	span := newSpan(parent.tracer, parent.SpanId, name, kind)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the current span of the context or nil in case
+// the context is not part of a trace.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+func newId(size int) string {
+	bytes := make([]byte, size)
+	_, _ = rand.Read(bytes)
+	return fmt.Sprintf("%x", bytes)
+}
@@ -21,6 +21,10 @@ func (s FileStream) Name() string {
 	return s.name
 }
 
+func (s FileStream) Path() string {
+	return s.path
+}
+
 func (s FileStream) Size() (int64, error) {
 	fileStat, err := os.Stat(s.path)
 	if err != nil && errors.Is(err, os.ErrNotExist) {
@@ -0,0 +1,21 @@
+package utils
+
+// HttpStatusError is returned when a service responds with an unsuccessful
+// HTTP status code. The status code is used to determine the exit code of
+// the CLI.
+type HttpStatusError struct {
+	StatusCode int
+	err        error
+}
+
+func (e HttpStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e HttpStatusError) Unwrap() error {
+	return e.err
+}
+
+func NewHttpStatusError(statusCode int, err error) *HttpStatusError {
+	return &HttpStatusError{statusCode, err}
+}
@@ -0,0 +1,8 @@
+package utils
+
+// PermanentError can be implemented by errors which fail on every attempt.
+// Retry() returns them immediately even when they are marked as retryable.
+type PermanentError interface {
+	error
+	Permanent() bool
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/UiPath/uipathcli/log"
@@ -11,12 +12,23 @@ import (
 
 // The ProgressBar helps rendering a text-based progress indicator on the command-line.
 // It uses the standard error output interface of the logger for writing progress.
+//
+// The progress bar can be updated from multiple goroutines. Once it has been removed,
+// further updates are ignored so that a cancelled operation does not leave a
+// half-drawn progress bar behind.
 type ProgressBar struct {
 	logger         log.Logger
 	renderedLength int
+	removed        bool
+	mutex          sync.Mutex
 }
 
 func (b *ProgressBar) Update(text string, current int64, total int64, bytesPerSecond int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.removed {
+		return
+	}
 	b.logger.LogError("\r")
 	length := b.render(text, current, total, bytesPerSecond)
 	left := b.renderedLength - length
@@ -27,13 +39,17 @@ func (b *ProgressBar) Update(text string, current int64, total int64, bytesPerSe
 }
 
 func (b *ProgressBar) Remove() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
 	if b.renderedLength > 0 {
 		clear := fmt.Sprintf("\r%s\r", strings.Repeat(" ", b.renderedLength))
 		b.logger.LogError(clear)
 	}
+	b.renderedLength = 0
+	b.removed = true
 }
 
-func (b ProgressBar) render(text string, currentBytes int64, totalBytes int64, bytesPerSecond int64) int {
+func (b *ProgressBar) render(text string, currentBytes int64, totalBytes int64, bytesPerSecond int64) int {
 	percent := math.Min(float64(currentBytes)/float64(totalBytes)*100.0, 100.0)
 	barCount := int(percent / 5.0)
 	bar := strings.Repeat("█", barCount) + strings.Repeat(" ", 20-barCount)
@@ -53,7 +69,7 @@ func (b ProgressBar) render(text string, currentBytes int64, totalBytes int64, b
 	return utf8.RuneCountInString(output)
 }
 
-func (b ProgressBar) formatBytes(count int64) (string, string) {
+func (b *ProgressBar) formatBytes(count int64) (string, string) {
 	if count < 1000 {
 		return b.formatBytesInUnit(count, "B")
 	}
@@ -66,7 +82,7 @@ func (b ProgressBar) formatBytes(count int64) (string, string) {
 	return b.formatBytesInUnit(count, "GB")
 }
 
-func (b ProgressBar) formatBytesInUnit(count int64, unit string) (string, string) {
+func (b *ProgressBar) formatBytesInUnit(count int64, unit string) (string, string) {
 	if unit == "B" {
 		return fmt.Sprintf("%d", count), unit
 	}
@@ -80,5 +96,5 @@ func (b ProgressBar) formatBytesInUnit(count int64, unit string) (string, string
 }
 
 func NewProgressBar(logger log.Logger) *ProgressBar {
-	return &ProgressBar{logger, 0}
+	return &ProgressBar{logger: logger}
 }
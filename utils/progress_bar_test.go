@@ -67,6 +67,19 @@ func TestProgressBarShowsMultipleReadsFromProgressReader(t *testing.T) {
 	}
 }
 
+func TestProgressBarIgnoresUpdatesAfterRemove(t *testing.T) {
+	var output bytes.Buffer
+	progressBar := NewProgressBar(log.NewDefaultLogger(&output))
+
+	progressBar.Update("uploading...", 200, 1000, 1)
+	progressBar.Remove()
+	progressBar.Update("uploading...", 400, 1000, 1)
+
+	if !strings.HasSuffix(output.String(), "\r"+strings.Repeat(" ", 60)+"\r") {
+		t.Errorf("Should clear progress bar and ignore further updates, but got: %v", output.String())
+	}
+}
+
 func lastLine(output bytes.Buffer) string {
 	lines := strings.Split(output.String(), "\r")
 	return lines[len(lines)-1]
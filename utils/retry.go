@@ -2,19 +2,67 @@
 // multiple other packages.
 package utils
 
-import "time"
+import (
+	"context"
+	"errors"
+)
 
-const MaxAttempts = 3
+// Retries the given function according to the retry policy when it returns
+// an RetryableError. The delay between attempts is taken from the Retry-After
+// information of the error when available, otherwise the backoff of the policy
+// is used. In case the Retry-After delay exceeds the maximum backoff of the
+// policy, retrying stops so that a large value does not block the CLI.
+//
+// Retrying stops as soon as the provided context is cancelled or the error
+// is a PermanentError.
+func Retry(ctx context.Context, policy RetryPolicy, f func() error) error {
+	return RetryWithAttempt(ctx, policy, func(attempt int) error {
+		return f()
+	})
+}
 
-// Retries the given function up to 3 times when it returns an RetryableError.
-func Retry(f func() error) error {
+// RetryWithAttempt is like Retry but passes the number of the current attempt,
+// starting with 1, to the function.
+func RetryWithAttempt(ctx context.Context, policy RetryPolicy, f func(attempt int) error) error {
 	var err error
 	for i := 1; ; i++ {
-		err = f()
-		_, retryable := err.(*RetryableError)
-		if !retryable || i == MaxAttempts {
+		err = f(i)
+		var retryableErr *RetryableError
+		if !errors.As(err, &retryableErr) || i >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		var permanentErr PermanentError
+		if errors.As(err, &permanentErr) && permanentErr.Permanent() {
+			return err
+		}
+		delay := retryableErr.retryAfter
+		if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+			return err
+		}
+		if delay <= 0 {
+			delay = policy.Delay(i)
+		}
+		err = Sleep(ctx, delay)
+		if err != nil {
 			return err
 		}
-		time.Sleep(1 * time.Second)
 	}
 }
+
+type retryAttemptKey struct{}
+
+// WithRetryAttempt returns a copy of the context which carries the attempt
+// number so that it can be reported for the requests sent with the context.
+func WithRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+// RetryAttempt returns the attempt number stored in the context or 1 in case
+// the context does not carry an attempt number.
+func RetryAttempt(ctx context.Context) int {
+	attempt, ok := ctx.Value(retryAttemptKey{}).(int)
+	if !ok {
+		return 1
+	}
+	return attempt
+}
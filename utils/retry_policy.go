@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math/rand"
+	"time"
+)
+
+const DefaultMaxAttempts = 3
+const DefaultBackoff = 1 * time.Second
+const DefaultMaxBackoff = 30 * time.Second
+
+// The RetryPolicy defines how often and when failed operations are retried.
+//
+// The delay between attempts grows exponentially starting with the Backoff
+// duration and is capped at MaxBackoff. Jitter randomizes the delay to avoid
+// multiple clients retrying at the same time.
+// StatusCodes contains the HTTP status codes which should be retried. By default,
+// throttled requests (429) and server errors (5xx) are retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Jitter      bool
+	StatusCodes []int
+}
+
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt; i++ {
+		delay = delay * 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			delay = p.MaxBackoff
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter && delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint // Jitter does not require a secure random number
+	}
+	return delay
+}
+
+func (p RetryPolicy) IsRetryableStatusCode(statusCode int) bool {
+	if len(p.StatusCodes) == 0 {
+		return statusCode == 429 || statusCode >= 500
+	}
+	for _, code := range p.StatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func NewRetryPolicy(maxAttempts int, backoff time.Duration, maxBackoff time.Duration, jitter bool, statusCodes []int) *RetryPolicy {
+	return &RetryPolicy{maxAttempts, backoff, maxBackoff, jitter, statusCodes}
+}
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return NewRetryPolicy(DefaultMaxAttempts, DefaultBackoff, DefaultMaxBackoff, false, []int{})
+}
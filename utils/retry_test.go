@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	policy := NewRetryPolicy(4, 1*time.Millisecond, 1*time.Millisecond, false, []int{})
+
+	attempts := 0
+	err := Retry(context.Background(), *policy, func() error {
+		attempts++
+		return Retryable(errors.New("failed"))
+	})
+
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("Expected last error to be returned, but got: %v", err)
+	}
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, but got: %v", attempts)
+	}
+}
+
+func TestRetryDoesNotRetryNonRetryableError(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Millisecond, 1*time.Millisecond, false, []int{})
+
+	attempts := 0
+	_ = Retry(context.Background(), *policy, func() error {
+		attempts++
+		return errors.New("failed")
+	})
+
+	if attempts != 1 {
+		t.Errorf("Expected single attempt, but got: %v", attempts)
+	}
+}
+
+func TestRetryDetectsWrappedRetryableError(t *testing.T) {
+	policy := NewRetryPolicy(2, 1*time.Millisecond, 1*time.Millisecond, false, []int{})
+
+	attempts := 0
+	_ = Retry(context.Background(), *policy, func() error {
+		attempts++
+		return errors.Join(errors.New("wrapped"), Retryable(errors.New("failed")))
+	})
+
+	if attempts != 2 {
+		t.Errorf("Expected wrapped error to be retried, but got: %v attempts", attempts)
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Minute, 1*time.Minute, false, []int{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	err := Retry(ctx, *policy, func() error {
+		attempts++
+		cancel()
+		return Retryable(errors.New("failed"))
+	})
+
+	if attempts != 1 {
+		t.Errorf("Expected single attempt, but got: %v", attempts)
+	}
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("Expected last error to be returned, but got: %v", err)
+	}
+}
+
+func TestRetryPolicyDelayGrowsExponentially(t *testing.T) {
+	policy := NewRetryPolicy(5, 1*time.Second, 30*time.Second, false, []int{})
+
+	delays := []time.Duration{policy.Delay(1), policy.Delay(2), policy.Delay(3), policy.Delay(4)}
+
+	expected := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("Expected delays %v, but got: %v", expected, delays)
+			break
+		}
+	}
+}
+
+func TestRetryPolicyDelayIsCappedAtMaxBackoff(t *testing.T) {
+	policy := NewRetryPolicy(10, 1*time.Second, 5*time.Second, false, []int{})
+
+	delay := policy.Delay(8)
+
+	if delay != 5*time.Second {
+		t.Errorf("Expected delay to be capped at 5s, but got: %v", delay)
+	}
+}
+
+func TestRetryPolicyJitterRandomizesDelay(t *testing.T) {
+	policy := NewRetryPolicy(3, 2*time.Second, 30*time.Second, true, []int{})
+
+	delay := policy.Delay(1)
+
+	if delay < 1*time.Second || delay > 2*time.Second {
+		t.Errorf("Expected delay between 1s and 2s, but got: %v", delay)
+	}
+}
+
+func TestRetryPolicyRetriesThrottlingAndServerErrorsByDefault(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	if !policy.IsRetryableStatusCode(429) || !policy.IsRetryableStatusCode(503) {
+		t.Errorf("Expected 429 and 503 to be retryable by default")
+	}
+	if policy.IsRetryableStatusCode(404) {
+		t.Errorf("Expected 404 not to be retryable by default")
+	}
+}
+
+func TestRetryPolicyUsesConfiguredStatusCodes(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Second, 30*time.Second, false, []int{409})
+
+	if !policy.IsRetryableStatusCode(409) {
+		t.Errorf("Expected 409 to be retryable")
+	}
+	if policy.IsRetryableStatusCode(500) {
+		t.Errorf("Expected 500 not to be retryable")
+	}
+}
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	delay := ParseRetryAfter("120")
+
+	if delay != 120*time.Second {
+		t.Errorf("Expected 120s delay, but got: %v", delay)
+	}
+}
+
+func TestParseRetryAfterHttpDate(t *testing.T) {
+	date := time.Now().Add(10 * time.Second).UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT")
+
+	delay := ParseRetryAfter(date)
+
+	if delay <= 5*time.Second || delay > 10*time.Second {
+		t.Errorf("Expected delay of about 10s, but got: %v", delay)
+	}
+}
+
+func TestParseRetryAfterInvalidValue(t *testing.T) {
+	delay := ParseRetryAfter("invalid")
+
+	if delay != 0 {
+		t.Errorf("Expected no delay, but got: %v", delay)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	policy := NewRetryPolicy(2, 1*time.Millisecond, 1*time.Second, false, []int{})
+
+	start := time.Now()
+	attempts := 0
+	_ = Retry(context.Background(), *policy, func() error {
+		attempts++
+		return RetryableAfter(errors.New("throttled"), 50*time.Millisecond)
+	})
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, but got: %v", attempts)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("Expected to wait for Retry-After, but waited: %v", time.Since(start))
+	}
+}
+
+func TestRetryStopsWhenRetryAfterExceedsMaxBackoff(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Millisecond, 10*time.Millisecond, false, []int{})
+
+	attempts := 0
+	err := Retry(context.Background(), *policy, func() error {
+		attempts++
+		return RetryableAfter(errors.New("throttled"), ParseRetryAfter("86400"))
+	})
+
+	if attempts != 1 {
+		t.Errorf("Expected single attempt, but got: %v", attempts)
+	}
+	if err == nil || err.Error() != "throttled" {
+		t.Errorf("Expected last error to be returned, but got: %v", err)
+	}
+}
+
+type permanentTestError struct{}
+
+func (e permanentTestError) Error() string {
+	return "permanent"
+}
+
+func (e permanentTestError) Permanent() bool {
+	return true
+}
+
+func TestRetryDoesNotRetryPermanentError(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Millisecond, 1*time.Millisecond, false, []int{})
+
+	attempts := 0
+	_ = Retry(context.Background(), *policy, func() error {
+		attempts++
+		return Retryable(permanentTestError{})
+	})
+
+	if attempts != 1 {
+		t.Errorf("Expected single attempt, but got: %v", attempts)
+	}
+}
+
+func TestRetryWithAttemptPassesAttemptNumber(t *testing.T) {
+	policy := NewRetryPolicy(3, 1*time.Millisecond, 1*time.Millisecond, false, []int{})
+
+	attempts := []int{}
+	_ = RetryWithAttempt(context.Background(), *policy, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		return Retryable(errors.New("failed"))
+	})
+
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[2] != 3 {
+		t.Errorf("Expected attempt numbers 1 to 3, but got: %v", attempts)
+	}
+}
+
+func TestRetryAttemptFromContext(t *testing.T) {
+	ctx := WithRetryAttempt(context.Background(), 2)
+
+	if RetryAttempt(ctx) != 2 || RetryAttempt(context.Background()) != 1 {
+		t.Errorf("Expected attempt from context, but got: %v", RetryAttempt(ctx))
+	}
+}
@@ -1,15 +1,51 @@
 package utils
 
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // RetryableError can be returned inside of the Retry() block to indicate
 // that the function failed and should be retried.
 type RetryableError struct {
-	err error
+	err        error
+	retryAfter time.Duration
 }
 
 func (e RetryableError) Error() string {
 	return e.err.Error()
 }
 
+func (e RetryableError) Unwrap() error {
+	return e.err
+}
+
 func Retryable(err error) *RetryableError {
-	return &RetryableError{err}
+	return &RetryableError{err, 0}
+}
+
+// RetryableAfter marks the error as retryable and instructs the Retry() block
+// to wait for the provided duration before the next attempt.
+func RetryableAfter(err error, retryAfter time.Duration) *RetryableError {
+	return &RetryableError{err, retryAfter}
+}
+
+// ParseRetryAfter converts the value of the Retry-After HTTP header into a duration.
+// The header can either contain the number of seconds to wait or an HTTP date.
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err == nil {
+		return time.Until(date)
+	}
+	return 0
 }
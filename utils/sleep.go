@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep pauses the current goroutine for the given duration and returns early
+// with the context error in case the context is cancelled.
+func Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}